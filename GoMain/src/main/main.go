@@ -28,18 +28,25 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"common/logmgr"
+	"common/resourceutil"
 
 	configuremgr "controller/configuremgr/container"
 	"controller/discoverymgr"
+	"controller/schedulermgr"
 	"controller/scoringmgr"
 	"controller/servicemgr"
-	executor "controller/servicemgr/executor/containerexecutor"
+	"controller/servicemgr/executor/containerexecutor"
+	"controller/servicemgr/executor/nativeexecutor"
 
 	"orchestrationapi"
 
@@ -50,6 +57,7 @@ import (
 	"restinterface/internalhandler"
 	"restinterface/route"
 
+	resourceDB "db/bolt/resource"
 	"db/bolt/wrapper"
 )
 
@@ -66,13 +74,25 @@ const (
 
 	configPath = edgeDir + "apps"
 
-	cipherKeyFilePath = edgeDir + "orchestration_userID.txt"
-	deviceIDFilePath  = edgeDir + "orchestration_deviceID.txt"
+	cipherKeyFilePath        = edgeDir + "orchestration_userID.txt"
+	deviceIDFilePath         = edgeDir + "orchestration_deviceID.txt"
+	deviceLabelFilePath      = edgeDir + "orchestration_labels.txt"
+	deviceLimitFilePath      = edgeDir + "orchestration_limits.txt"
+	monitoringConfigFilePath = edgeDir + "orchestration_monitoring.txt"
+	registryAuthFilePath     = edgeDir + "orchestration_registry_auth.json"
+
+	shutdownTimeout = 10 * time.Second
 )
 
 var (
 	flagVersion                  bool
+	flagScheduler                string
+	flagResourceHistory          int
+	flagRTTInterval              time.Duration
 	commitID, version, buildTime string
+
+	orcheEngine    orchestrationapi.Orche
+	restEdgeRouter *route.RestRouter
 )
 
 func main() {
@@ -80,8 +100,15 @@ func main() {
 		log.Fatalf("[%s] Orchestaration initalize fail : %s", logPrefix, err.Error())
 	}
 
-	for {
-		time.Sleep(1000)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+
+	sig := <-sigChan
+	log.Printf("[%s] %s is received", logPrefix, sig.String())
+
+	if err := orchestrationDeinit(); err != nil {
+		log.Printf("[%s] Orchestaration deinitalize fail : %s", logPrefix, err.Error())
+		os.Exit(1)
 	}
 }
 
@@ -89,6 +116,9 @@ func main() {
 func orchestrationInit() error {
 	flag.BoolVar(&flagVersion, "v", false, "if true, print version and exit")
 	flag.BoolVar(&flagVersion, "version", false, "if true, print version and exit")
+	flag.StringVar(&flagScheduler, "scheduler", schedulermgr.BestScore, "default scheduling policy of service requests")
+	flag.IntVar(&flagResourceHistory, "resource-history", resourceDB.DefaultRetention, "number of samples kept for each resource")
+	flag.DurationVar(&flagRTTInterval, "rtt-interval", 0, "period of round trip time probes to other devices, it overrides the monitoring configuration")
 	flag.Parse()
 
 	logmgr.Init(logPath)
@@ -102,24 +132,56 @@ func orchestrationInit() error {
 	restIns.SetCipher(sha256.GetCipher(cipherKeyFilePath))
 
 	servicemgr.GetInstance().SetClient(restIns)
+	if limits, err := servicemgr.ReadLimits(deviceLimitFilePath); err == nil {
+		servicemgr.GetInstance().SetLimits(limits)
+	}
+
+	if intervals, err := resourceutil.ReadCollectorIntervals(monitoringConfigFilePath); err == nil {
+		for name, interval := range intervals {
+			if err := resourceutil.SetCollectorInterval(name, interval); err != nil {
+				log.Printf("[%s] %s", logPrefix, err.Error())
+			}
+		}
+	}
+
+	if err := schedulermgr.GetInstance().SetDefault(flagScheduler); err != nil {
+		return err
+	}
+
+	if err := resourceutil.SetHistoryRetention(flagResourceHistory); err != nil {
+		return err
+	}
+
+	if flagRTTInterval > 0 {
+		if err := resourceutil.SetRTTProbeInterval(flagRTTInterval); err != nil {
+			return err
+		}
+	}
+
+	if creds, err := containerexecutor.ReadRegistryCredentials(registryAuthFilePath); err == nil {
+		containerexecutor.GetInstance().SetRegistryCredentials(creds)
+	}
 
 	builder := orchestrationapi.OrchestrationBuilder{}
 	builder.SetWatcher(configuremgr.GetInstance(configPath))
 	builder.SetDiscovery(discoverymgr.GetInstance())
 	builder.SetScoring(scoringmgr.GetInstance())
 	builder.SetService(servicemgr.GetInstance())
-	builder.SetExecutor(executor.GetInstance())
+	// @Note : the container executor runs the requests of devices which do not give the execution type
+	builder.SetExecutor(containerexecutor.GetInstance())
+	builder.AddExecutor(executionType, containerexecutor.GetInstance())
+	builder.AddExecutor("native", nativeexecutor.GetInstance())
 	builder.SetClient(restIns)
 
-	orcheEngine := builder.Build()
+	orcheEngine = builder.Build()
 	if orcheEngine == nil {
 		log.Fatalf("[%s] Orchestaration initalize fail", logPrefix)
 		return errors.New("fail to init orchestration")
 	}
 
-	orcheEngine.Start(deviceIDFilePath, platform, executionType)
+	orcheEngine.Start(deviceIDFilePath, platform, executionType, deviceLabelFilePath)
 
-	restEdgeRouter := route.NewRestRouter()
+	restEdgeRouter = route.NewRestRouter()
 
 	internalapi, err := orchestrationapi.GetInternalAPI()
 	if err != nil {
@@ -150,3 +212,27 @@ func orchestrationInit() error {
 
 	return nil
 }
+
+// orchestrationDeinit stops REST server and orchestration service gracefully
+func orchestrationDeinit() error {
+	log.Printf("[%s] OrchestrationDeinit", logPrefix)
+
+	// every stage has its own deadline, an open stream should not expire the next stage
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	err := restEdgeRouter.Stop(ctx)
+	cancel()
+	if err != nil {
+		log.Printf("[%s] REST server shutdown : %s", logPrefix, err.Error())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := orcheEngine.Stop(ctx); err != nil {
+		return err
+	}
+
+	log.Println(logPrefix, "orchestration deinit done")
+
+	return nil
+}
@@ -73,6 +73,9 @@ function install_prerequisite() {
     echo "-----------------------------------"
     echo " Install prerequisite packages"
     echo "-----------------------------------"
+    ### go 1.13 or above is required for http.Server.BaseContext
+    make check-go-version || exit 1
+
     pkg_list=(
         "github.com/axw/gocov/gocov"
         "github.com/matm/gocov-html"
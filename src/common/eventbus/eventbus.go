@@ -0,0 +1,111 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package eventbus delivers internal events from a publisher to every subscriber of the topic
+package eventbus
+
+import (
+	"log"
+	"sync"
+)
+
+const (
+	logPrefix = "[eventbus]"
+
+	defaultChanSize = 32
+)
+
+// EventBus is the interface to publish and subscribe internal events
+type EventBus interface {
+	Publish(topic string, event interface{})
+	Subscribe(topic string) *Subscriber
+	Unsubscribe(sub *Subscriber)
+}
+
+// Subscriber receives events of the subscribed topic through C
+type Subscriber struct {
+	C     <-chan interface{}
+	topic string
+	ch    chan interface{}
+}
+
+type eventBusImpl struct {
+	sync.RWMutex
+	subscribers map[string]map[*Subscriber]struct{}
+}
+
+var eventBusIns *eventBusImpl
+
+func init() {
+	eventBusIns = &eventBusImpl{subscribers: make(map[string]map[*Subscriber]struct{})}
+}
+
+// GetInstance returns the singleton eventBusImpl instance
+func GetInstance() EventBus {
+	return eventBusIns
+}
+
+// Publish sends event to every subscriber of topic without blocking
+func (bus *eventBusImpl) Publish(topic string, event interface{}) {
+	bus.RLock()
+	defer bus.RUnlock()
+
+	for sub := range bus.subscribers[topic] {
+		select {
+		case sub.ch <- event:
+		default:
+			log.Println(logPrefix, "[Publish]", topic, "subchan is not receiving")
+		}
+	}
+}
+
+// Subscribe registers new subscriber of topic
+func (bus *eventBusImpl) Subscribe(topic string) *Subscriber {
+	ch := make(chan interface{}, defaultChanSize)
+	sub := &Subscriber{C: ch, topic: topic, ch: ch}
+
+	bus.Lock()
+	defer bus.Unlock()
+
+	if _, ok := bus.subscribers[topic]; !ok {
+		bus.subscribers[topic] = make(map[*Subscriber]struct{})
+	}
+	bus.subscribers[topic][sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe removes subscriber and closes its channel
+func (bus *eventBusImpl) Unsubscribe(sub *Subscriber) {
+	bus.Lock()
+	defer bus.Unlock()
+
+	subs, ok := bus.subscribers[sub.topic]
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(bus.subscribers, sub.topic)
+	}
+	close(sub.ch)
+}
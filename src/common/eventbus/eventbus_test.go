@@ -0,0 +1,73 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	testTopic  = "test/topic"
+	otherTopic = "test/other"
+	testEvent  = "event"
+)
+
+func TestPublish(t *testing.T) {
+	bus := GetInstance()
+
+	sub := bus.Subscribe(testTopic)
+	other := bus.Subscribe(otherTopic)
+	defer bus.Unsubscribe(sub)
+	defer bus.Unsubscribe(other)
+
+	bus.Publish(testTopic, testEvent)
+
+	t.Run("Success", func(t *testing.T) {
+		select {
+		case event := <-sub.C:
+			if event.(string) != testEvent {
+				t.Error("unexpected event : ", event)
+			}
+		case <-time.After(time.Second):
+			t.Error("event is not delivered")
+		}
+	})
+	t.Run("OtherTopic", func(t *testing.T) {
+		select {
+		case event := <-other.C:
+			t.Error("unexpected event : ", event)
+		default:
+		}
+	})
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus := GetInstance()
+
+	sub := bus.Subscribe(testTopic)
+	bus.Unsubscribe(sub)
+
+	if _, ok := <-sub.C; ok {
+		t.Error("channel is not closed")
+	}
+
+	// NOTE : should not be blocked or panic after unsubscribe
+	bus.Publish(testTopic, testEvent)
+	bus.Unsubscribe(sub)
+}
@@ -0,0 +1,349 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package metrics keeps counters, gauges and histograms of orchestration
+// and exposes them in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	logPrefix = "[metrics]"
+
+	// ContentType is the content type of the Prometheus text format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+
+	labelSeparator = "\xff"
+)
+
+// DefBuckets are the default histogram buckets in seconds
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metric interface {
+	getName() string
+	write(w io.Writer)
+}
+
+type registry struct {
+	sync.Mutex
+	metrics map[string]metric
+}
+
+var registryIns = &registry{metrics: make(map[string]metric)}
+
+// register adds m to the registry, registering the same name twice is a programming error
+func register(m metric) {
+	registryIns.Lock()
+	defer registryIns.Unlock()
+
+	if _, exist := registryIns.metrics[m.getName()]; exist {
+		panic(logPrefix + " duplicated metric " + m.getName())
+	}
+	registryIns.metrics[m.getName()] = m
+}
+
+// WriteText writes every registered metric in the Prometheus text format
+func WriteText(w io.Writer) error {
+	registryIns.Lock()
+	metrics := make([]metric, 0, len(registryIns.metrics))
+	for _, m := range registryIns.metrics {
+		metrics = append(metrics, m)
+	}
+	registryIns.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].getName() < metrics[j].getName()
+	})
+
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buf)
+	}
+	return buf.Flush()
+}
+
+// Handler returns the handler serving every registered metric
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := WriteText(w); err != nil {
+			log.Println(logPrefix, err.Error())
+		}
+	})
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+type vec struct {
+	sync.Mutex
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+func newVec(name, help, typ string, buckets []float64, labels []string) *vec {
+	return &vec{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+}
+
+func (v *vec) getName() string {
+	return v.name
+}
+
+// with returns the series of labelValues, v should be locked
+func (v *vec) with(labelValues []string) *series {
+	if len(labelValues) != len(v.labels) {
+		log.Println(logPrefix, v.name, "expects labels", v.labels, "but got", labelValues)
+		return nil
+	}
+
+	key := strings.Join(labelValues, labelSeparator)
+	s, exist := v.series[key]
+	if !exist {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if v.typ == typeHistogram {
+			s.counts = make([]uint64, len(v.buckets))
+		}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *vec) write(w io.Writer) {
+	v.Lock()
+	defer v.Unlock()
+
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(w, v.name, v.help, v.typ)
+	for _, key := range keys {
+		s := v.series[key]
+		if v.typ != typeHistogram {
+			writeSample(w, v.name, v.labels, s.labelValues, "", "", s.value)
+			continue
+		}
+
+		var cumulative uint64
+		for i, bound := range v.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, v.name+"_bucket", v.labels, s.labelValues, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, v.name+"_bucket", v.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(w, v.name+"_sum", v.labels, s.labelValues, "", "", s.value)
+		writeSample(w, v.name+"_count", v.labels, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// Counter is a metric which only goes up, partitioned by labels
+type Counter struct {
+	*vec
+}
+
+// NewCounter registers new counter
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{newVec(name, help, typeCounter, nil, labels)}
+	register(c)
+	return c
+}
+
+// Inc increases the counter of labelValues by 1
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter of labelValues by delta, negative delta is ignored
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if s := c.with(labelValues); s != nil {
+		s.value += delta
+	}
+}
+
+// Gauge is a metric which can go up and down, partitioned by labels
+type Gauge struct {
+	*vec
+}
+
+// NewGauge registers new gauge
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{newVec(name, help, typeGauge, nil, labels)}
+	register(g)
+	return g
+}
+
+// Set sets the gauge of labelValues to value
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.Lock()
+	defer g.Unlock()
+
+	if s := g.with(labelValues); s != nil {
+		s.value = value
+	}
+}
+
+// Histogram counts observations in buckets, partitioned by labels
+type Histogram struct {
+	*vec
+}
+
+// NewHistogram registers new histogram, DefBuckets is used if buckets is empty
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &Histogram{newVec(name, help, typeHistogram, sorted, labels)}
+	register(h)
+	return h
+}
+
+// Observe adds value to the histogram of labelValues
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.Lock()
+	defer h.Unlock()
+
+	s := h.with(labelValues)
+	if s == nil {
+		return
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+// GaugeFunc is a gauge whose values are read by a function whenever metrics are written
+type GaugeFunc struct {
+	name  string
+	help  string
+	label string
+	fn    func() map[string]float64
+}
+
+// NewGaugeFunc registers new gauge of which fn returns the values keyed by the value of label
+func NewGaugeFunc(name, help, label string, fn func() map[string]float64) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, label: label, fn: fn}
+	register(g)
+	return g
+}
+
+func (g *GaugeFunc) getName() string {
+	return g.name
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	values := g.fn()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(w, g.name, g.help, typeGauge)
+	for _, key := range keys {
+		writeSample(w, g.name, []string{g.label}, []string{key}, "", "", values[key])
+	}
+}
+
+func writeHeader(w io.Writer, name, help, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+func writeSample(w io.Writer, name string, labels, labelValues []string, extraLabel, extraValue string, value float64) {
+	pairs := make([]string, 0, len(labels)+1)
+	for i, label := range labels {
+		pairs = append(pairs, label+"=\""+escapeLabelValue(labelValues[i])+"\"")
+	}
+	if extraLabel != "" {
+		pairs = append(pairs, extraLabel+"=\""+extraValue+"\"")
+	}
+
+	if len(pairs) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+		return
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,126 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func writeMetrics(t *testing.T) string {
+	var buf bytes.Buffer
+	if err := WriteText(&buf); err != nil {
+		t.Fatal(err.Error())
+	}
+	return buf.String()
+}
+
+func assertContains(t *testing.T, text string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("expected line %q in\n%s", line, text)
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter("test_counter_total", "Counter of\ntest", "service", "status")
+
+	c.Inc("app", "Finished")
+	c.Add(2, "app", "Finished")
+	c.Inc("app", "Failed")
+	c.Add(-1, "app", "Failed")
+	c.Inc("app")
+
+	assertContains(t, writeMetrics(t),
+		`# HELP test_counter_total Counter of\ntest`,
+		`# TYPE test_counter_total counter`,
+		`test_counter_total{service="app",status="Failed"} 1`,
+		`test_counter_total{service="app",status="Finished"} 3`,
+	)
+}
+
+func TestGauge(t *testing.T) {
+	g := NewGauge("test_gauge", "Gauge", "name")
+
+	g.Set(1.5, `a"b`)
+	g.Set(-2, `a"b`)
+
+	assertContains(t, writeMetrics(t),
+		`# TYPE test_gauge gauge`,
+		`test_gauge{name="a\"b"} -2`,
+	)
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_duration_seconds", "Histogram", []float64{1, 0.1}, "route")
+
+	h.Observe(0.05, "r")
+	h.Observe(0.5, "r")
+	h.Observe(5, "r")
+
+	assertContains(t, writeMetrics(t),
+		`# TYPE test_duration_seconds histogram`,
+		`test_duration_seconds_bucket{route="r",le="0.1"} 1`,
+		`test_duration_seconds_bucket{route="r",le="1"} 2`,
+		`test_duration_seconds_bucket{route="r",le="+Inf"} 3`,
+		`test_duration_seconds_sum{route="r"} 5.55`,
+		`test_duration_seconds_count{route="r"} 3`,
+	)
+}
+
+func TestGaugeFunc(t *testing.T) {
+	NewGaugeFunc("test_resource", "Resource", "resource", func() map[string]float64 {
+		return map[string]float64{"cpu/usage": 12.5, "memory/free": 1024}
+	})
+
+	assertContains(t, writeMetrics(t),
+		`test_resource{resource="cpu/usage"} 12.5`,
+		`test_resource{resource="memory/free"} 1024`,
+	)
+}
+
+func TestRegisterDuplicated(t *testing.T) {
+	NewCounter("test_duplicated_total", "Duplicated")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	NewGauge("test_duplicated_total", "Duplicated")
+}
+
+func TestHandler(t *testing.T) {
+	NewCounter("test_handler_total", "Handler").Inc()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Error("unexpected status code", w.Code)
+	}
+	if w.Header().Get("Content-Type") != ContentType {
+		t.Error("unexpected content type", w.Header().Get("Content-Type"))
+	}
+	assertContains(t, w.Body.String(), `test_handler_total 1`)
+}
@@ -0,0 +1,222 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resourceutil
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"common/errors"
+)
+
+// cgroupUtil reads the resource limits and usage of the cgroup of orchestration in a container
+type cgroupUtil struct {
+	root        string
+	inContainer func() bool
+}
+
+const (
+	dockerEnvPath  = "/.dockerenv"
+	procCgroupPath = "/proc/1/cgroup"
+
+	// unlimitedMemory is the threshold of cgroup v1 memory limit regarded as no limit
+	unlimitedMemory = 1 << 62
+)
+
+var (
+	cgroup = cgroupUtil{root: "/sys/fs/cgroup"}
+)
+
+func init() {
+	cgroup.inContainer = detectContainer
+}
+
+func detectContainer() bool {
+	if _, err := os.Stat(dockerEnvPath); err == nil {
+		return true
+	}
+
+	data, err := ioutil.ReadFile(procCgroupPath)
+	if err != nil {
+		return false
+	}
+	for _, keyword := range []string{"docker", "kubepods", "containerd", "lxc"} {
+		if strings.Contains(string(data), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c cgroupUtil) isV2() bool {
+	_, err := os.Stat(filepath.Join(c.root, "cgroup.controllers"))
+	return err == nil
+}
+
+// cpuLimit gives the number of cpus allowed by the cpu quota of the container
+func (c cgroupUtil) cpuLimit() (cpus float64, ok bool) {
+	if !c.inContainer() {
+		return 0, false
+	}
+
+	var quota, period string
+	if c.isV2() {
+		data, err := ioutil.ReadFile(filepath.Join(c.root, "cpu.max"))
+		if err != nil {
+			return 0, false
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 {
+			return 0, false
+		}
+		quota, period = fields[0], fields[1]
+	} else {
+		var err error
+		if quota, err = readTrimmed(filepath.Join(c.root, "cpu", "cpu.cfs_quota_us")); err != nil {
+			return 0, false
+		}
+		if period, err = readTrimmed(filepath.Join(c.root, "cpu", "cpu.cfs_period_us")); err != nil {
+			return 0, false
+		}
+	}
+
+	quotaValue, err := strconv.ParseFloat(quota, 64)
+	if err != nil || quotaValue <= 0 {
+		return 0, false
+	}
+	periodValue, err := strconv.ParseFloat(period, 64)
+	if err != nil || periodValue <= 0 {
+		return 0, false
+	}
+	return quotaValue / periodValue, true
+}
+
+// cpuUsageSeconds gives the cumulative cpu time consumed by the container
+func (c cgroupUtil) cpuUsageSeconds() (float64, error) {
+	if c.isV2() {
+		usec, err := readStat(filepath.Join(c.root, "cpu.stat"), "usage_usec")
+		if err != nil {
+			return 0, err
+		}
+		return usec / 1000 / 1000, nil
+	}
+
+	nsec, err := readUint(filepath.Join(c.root, "cpuacct", "cpuacct.usage"))
+	if err != nil {
+		return 0, err
+	}
+	return float64(nsec) / 1000 / 1000 / 1000, nil
+}
+
+// memoryLimit gives the memory limit of the container in bytes
+func (c cgroupUtil) memoryLimit() (limit uint64, ok bool) {
+	if !c.inContainer() {
+		return 0, false
+	}
+
+	path := filepath.Join(c.root, "memory", "memory.limit_in_bytes")
+	if c.isV2() {
+		path = filepath.Join(c.root, "memory.max")
+	}
+
+	limit, err := readUint(path)
+	if err != nil || limit == 0 || limit >= unlimitedMemory {
+		return 0, false
+	}
+	return limit, true
+}
+
+// memoryWorkingSet gives the memory usage of the container except inactive page cache in bytes
+func (c cgroupUtil) memoryWorkingSet() (uint64, error) {
+	usagePath := filepath.Join(c.root, "memory", "memory.usage_in_bytes")
+	statPath, inactiveKey := filepath.Join(c.root, "memory", "memory.stat"), "total_inactive_file"
+	if c.isV2() {
+		usagePath = filepath.Join(c.root, "memory.current")
+		statPath, inactiveKey = filepath.Join(c.root, "memory.stat"), "inactive_file"
+	}
+
+	usage, err := readUint(usagePath)
+	if err != nil {
+		return 0, err
+	}
+
+	inactive, err := readStat(statPath, inactiveKey)
+	if err == nil && uint64(inactive) < usage {
+		usage -= uint64(inactive)
+	}
+	return usage, nil
+}
+
+// memoryAvailable gives the memory left to the limit of the container in bytes
+func (c cgroupUtil) memoryAvailable() (available uint64, ok bool) {
+	limit, ok := c.memoryLimit()
+	if !ok {
+		return 0, false
+	}
+
+	usage, err := c.memoryWorkingSet()
+	if err != nil {
+		return 0, false
+	}
+	if usage >= limit {
+		return 0, true
+	}
+	return limit - usage, true
+}
+
+func readTrimmed(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readUint reads a number, "max" of cgroup v2 is regarded as unlimited
+func readUint(path string) (uint64, error) {
+	value, err := readTrimmed(path)
+	if err != nil {
+		return 0, err
+	}
+	if value == "max" {
+		return unlimitedMemory, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// readStat reads the value of a key from flat keyed file like memory.stat or cpu.stat
+func readStat(path string, key string) (float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseFloat(fields[1], 64)
+		}
+	}
+	return 0, errors.NotFound{Message: key + " does not exist in " + path}
+}
@@ -0,0 +1,270 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resourceutil
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"common/errors"
+	"common/metrics"
+)
+
+const (
+	// CollectorCPU collects cpu/usage, cpu/freq and cpu/count
+	CollectorCPU = "cpu"
+	// CollectorMemory collects memory/available and memory/free
+	CollectorMemory = "memory"
+	// CollectorNetwork collects network/mbps and network/bandwidth
+	CollectorNetwork = "network"
+	// CollectorRTT probes network/rtt of other devices
+	CollectorRTT = "rtt"
+	// CollectorThroughput probes network/peerbandwidth of other devices
+	CollectorThroughput = "throughput"
+	// CollectorDisk collects disk/total, disk/free and disk/iobusy
+	CollectorDisk = "disk"
+	// CollectorThermal collects thermal/temperature, power/battery and power/ac
+	CollectorThermal = "thermal"
+
+	// maxCollectorBackoff limits the wait after consecutive failures of a collector
+	maxCollectorBackoff = 5 * time.Minute
+)
+
+// Collector is the interface implemented by sources of resources,
+// Collect stores the values of Resources into resource store every Interval
+type Collector interface {
+	Name() string
+	Resources() []string
+	Interval() time.Duration
+	Collect() error
+}
+
+// PeerCollector is the interface implemented by collectors measuring resources against other devices
+type PeerCollector interface {
+	Collector
+	GetPeerResource(resourceName string, deviceID string) (float64, error)
+}
+
+type collector struct {
+	name      string
+	resources []string
+	interval  time.Duration
+	collect   func() error
+}
+
+func (c collector) Name() string            { return c.name }
+func (c collector) Resources() []string     { return c.resources }
+func (c collector) Interval() time.Duration { return c.interval }
+func (c collector) Collect() error          { return c.collect() }
+
+type peerCollector struct {
+	collector
+	get func(resourceName string, deviceID string) (float64, error)
+}
+
+func (c peerCollector) GetPeerResource(resourceName string, deviceID string) (float64, error) {
+	return c.get(resourceName, deviceID)
+}
+
+var (
+	collectorMtx       sync.RWMutex
+	collectors         []Collector
+	resourceCollectors = make(map[string]Collector)
+	collectorIntervals = make(map[string]time.Duration)
+
+	collectorFailures = metrics.NewCounter(
+		"edge_orchestration_collector_failures_total",
+		"Number of failures of resource collectors",
+		"collector")
+)
+
+func init() {
+	metrics.NewGaugeFunc(
+		"edge_orchestration_resource",
+		"Latest value of resources stored by collectors",
+		"resource", getStoredResources)
+}
+
+// RegisterCollector adds new source of resources, it is started with the next StartMonitoringResource
+func RegisterCollector(c Collector) error {
+	if c == nil || len(c.Name()) == 0 || c.Interval() <= 0 {
+		return errors.InvalidParam{Message: "invalid collector"}
+	}
+
+	collectorMtx.Lock()
+	defer collectorMtx.Unlock()
+
+	for _, registered := range collectors {
+		if registered.Name() == c.Name() {
+			return errors.InvalidParam{Message: "already registered collector : " + c.Name()}
+		}
+	}
+	for _, name := range c.Resources() {
+		if _, exist := resourceCollectors[name]; exist {
+			return errors.InvalidParam{Message: "already collected resource : " + name}
+		}
+	}
+
+	collectors = append(collectors, c)
+	for _, name := range c.Resources() {
+		resourceCollectors[name] = c
+	}
+	return nil
+}
+
+// SetCollectorInterval overrides the period of a collector
+func SetCollectorInterval(name string, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.InvalidParam{Message: "interval should be positive"}
+	}
+
+	collectorMtx.Lock()
+	defer collectorMtx.Unlock()
+
+	for _, c := range collectors {
+		if c.Name() == name {
+			collectorIntervals[name] = interval
+			return nil
+		}
+	}
+	return errors.NotFound{Message: "collector " + name + " does not exist"}
+}
+
+// ReadCollectorIntervals reads the periods of collectors written as `name=duration` lines like `rtt=10s`
+func ReadCollectorIntervals(configPath string) (map[string]time.Duration, error) {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	intervals := make(map[string]time.Duration)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pair := strings.SplitN(line, "=", 2)
+		if len(pair) != 2 {
+			log.Println(logPrefix, "[ReadCollectorIntervals]", "invalid interval : ", line)
+			continue
+		}
+
+		interval, err := time.ParseDuration(strings.TrimSpace(pair[1]))
+		if err != nil || interval <= 0 {
+			log.Println(logPrefix, "[ReadCollectorIntervals]", "invalid interval : ", line)
+			continue
+		}
+		intervals[strings.TrimSpace(pair[0])] = interval
+	}
+
+	return intervals, scanner.Err()
+}
+
+func getCollectors() []Collector {
+	collectorMtx.RLock()
+	defer collectorMtx.RUnlock()
+
+	return append([]Collector{}, collectors...)
+}
+
+// getStoredResources returns the latest values of stored resources, resources of other devices are excluded
+func getStoredResources() map[string]float64 {
+	values := make(map[string]float64)
+	for _, c := range getCollectors() {
+		if _, isPeer := c.(PeerCollector); isPeer {
+			continue
+		}
+		for _, name := range c.Resources() {
+			if value, err := getStoredResource(name); err == nil {
+				values[name] = value
+			}
+		}
+	}
+	return values
+}
+
+func getResourceCollector(resourceName string) (Collector, bool) {
+	collectorMtx.RLock()
+	defer collectorMtx.RUnlock()
+
+	c, exist := resourceCollectors[resourceName]
+	return c, exist
+}
+
+func getCollectorInterval(c Collector) time.Duration {
+	collectorMtx.RLock()
+	defer collectorMtx.RUnlock()
+
+	if interval, exist := collectorIntervals[c.Name()]; exist {
+		return interval
+	}
+	return c.Interval()
+}
+
+// runCollector collects resources until monitoring is stopped, the period is doubled on each consecutive failure
+func runCollector(c Collector, stop <-chan struct{}) {
+	failures := 0
+	for {
+		interval := getCollectorInterval(c)
+		if err := c.Collect(); err != nil {
+			failures++
+			collectorFailures.Inc(c.Name())
+			interval = backoffInterval(interval, failures)
+			log.Println(logPrefix, "[", c.Name(), "]", "collect fail : ", err.Error(), ", retry after", interval)
+		} else {
+			failures = 0
+		}
+
+		if !waitNextMonitoring(stop, interval) {
+			return
+		}
+	}
+}
+
+func backoffInterval(interval time.Duration, failures int) time.Duration {
+	limit := maxCollectorBackoff
+	if interval > limit {
+		limit = interval
+	}
+
+	for i := 0; i < failures && interval < limit; i++ {
+		interval *= 2
+	}
+	if interval > limit {
+		interval = limit
+	}
+	return interval
+}
+
+// collectAll runs every check and returns the first error
+func collectAll(checks ...func() error) (err error) {
+	for _, check := range checks {
+		if checkErr := check(); checkErr != nil && err == nil {
+			err = checkErr
+		}
+	}
+	return
+}
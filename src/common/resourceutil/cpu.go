@@ -37,50 +37,56 @@ var (
 func init() {
 	cpu.info = commoncpu.Info
 	cpu.percent = commoncpu.Percent
-}
-
-func processCPUInfo() {
-	go func() {
-		for {
-			checkCPUUsage()
-			checkCPUFreq()
-			checkCPUCount()
 
-			time.Sleep(time.Duration(defaultProcessingTime) * time.Second)
-		}
-	}()
+	RegisterCollector(collector{
+		name:      CollectorCPU,
+		resources: []string{CPUUsage, CPUFreq, CPUCount},
+		interval:  time.Duration(defaultProcessingTime) * time.Second,
+		collect: func() error {
+			return collectAll(checkCPUUsage, checkCPUFreq, checkCPUCount)
+		},
+	})
 }
 
-func checkCPUUsage() {
+func checkCPUUsage() error {
 	var usage float64
 
-	cpus, err := cpu.percent(time.Second, true)
-	if err != nil {
-		log.Println(logPrefix, "usage of cpu is fail : ", err.Error())
-		return
-	}
+	if quota, ok := cgroup.cpuLimit(); ok {
+		containerUsage, err := getContainerCPUUsage(quota)
+		if err != nil {
+			log.Println(logPrefix, "usage of container cpu is fail : ", err.Error())
+			return err
+		}
+		usage = containerUsage
+	} else {
+		cpus, err := cpu.percent(time.Second, true)
+		if err != nil {
+			log.Println(logPrefix, "usage of cpu is fail : ", err.Error())
+			return err
+		}
 
-	for _, cpu := range cpus {
-		usage += float64(cpu)
+		for _, cpu := range cpus {
+			usage += float64(cpu)
+		}
+		usage /= float64(len(cpus))
 	}
-	usage /= float64(len(cpus))
 
 	info := resourceDB.ResourceInfo{}
 	info.Name = CPUUsage
 	info.Value = usage
 
-	err = resourceDBExecutor.Set(info)
+	err := resourceDBExecutor.Set(info)
 	if err != nil {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
-	return
+	return err
 }
 
-func checkCPUFreq() (out float64, err error) {
+func checkCPUFreq() error {
 	infos, err := cpu.info()
 	if err != nil {
 		log.Println(logPrefix, "cpu.Info() fail : ", err.Error())
-		return
+		return err
 	}
 
 	info := resourceDB.ResourceInfo{}
@@ -91,23 +97,50 @@ func checkCPUFreq() (out float64, err error) {
 	if err != nil {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
-	return
+	return err
 }
 
-func checkCPUCount() {
+func checkCPUCount() error {
 	infos, err := cpu.info()
 	if err != nil {
 		log.Println(logPrefix, "cpu info getting fail : ", err.Error())
-		return
+		return err
 	}
 
 	info := resourceDB.ResourceInfo{}
 	info.Name = CPUCount
 	info.Value = float64(len(infos))
+	if quota, ok := cgroup.cpuLimit(); ok && quota < info.Value {
+		info.Value = quota
+	}
 
 	err = resourceDBExecutor.Set(info)
 	if err != nil {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
-	return
+	return err
+}
+
+// getContainerCPUUsage gives the cpu usage of the container for a second as the percentage of its quota
+func getContainerCPUUsage(quota float64) (float64, error) {
+	prevUsage, err := cgroup.cpuUsageSeconds()
+	if err != nil {
+		return 0, err
+	}
+	prevTime := time.Now()
+
+	time.Sleep(1 * time.Second)
+
+	nextUsage, err := cgroup.cpuUsageSeconds()
+	if err != nil {
+		return 0, err
+	}
+
+	usage := (nextUsage - prevUsage) / time.Now().Sub(prevTime).Seconds() / quota * 100
+	if usage < 0 {
+		usage = 0
+	} else if usage > 100 {
+		usage = 100
+	}
+	return usage, nil
 }
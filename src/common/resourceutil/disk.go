@@ -0,0 +1,137 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resourceutil
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type diskUtil struct {
+	statfs    func(path string, buf *syscall.Statfs_t) error
+	diskStats func() (map[string]uint64, error)
+}
+
+const procDiskStatsPath = "/proc/diskstats"
+
+var (
+	disk = diskUtil{}
+
+	// diskPath is the mount point whose file system is measured
+	diskPath = "/"
+)
+
+func init() {
+	disk.statfs = syscall.Statfs
+	disk.diskStats = readDiskIOTicks
+
+	RegisterCollector(collector{
+		name:      CollectorDisk,
+		resources: []string{DiskTotal, DiskFree, DiskIOBusy},
+		interval:  time.Duration(defaultProcessingTime) * time.Second,
+		collect: func() error {
+			return collectAll(checkDiskSpace, checkDiskIOBusy)
+		},
+	})
+}
+
+func checkDiskSpace() error {
+	var stat syscall.Statfs_t
+	if err := disk.statfs(diskPath, &stat); err != nil {
+		log.Println(logPrefix, "statfs of", diskPath, "fail : ", err.Error())
+		return err
+	}
+
+	return collectAll(
+		func() error { return setResource(DiskTotal, float64(stat.Blocks)*float64(stat.Bsize)/1024) },
+		func() error { return setResource(DiskFree, float64(stat.Bavail)*float64(stat.Bsize)/1024) },
+	)
+}
+
+// checkDiskIOBusy stores the percentage of time the busiest disk was doing I/O for a second
+func checkDiskIOBusy() error {
+	prevTicks, err := disk.diskStats()
+	if err != nil {
+		log.Println(logPrefix, "disk stats getting fail : ", err.Error())
+		return err
+	}
+
+	prevTime := time.Now()
+	time.Sleep(1 * time.Second)
+
+	nextTicks, err := disk.diskStats()
+	if err != nil {
+		log.Println(logPrefix, "disk stats getting fail : ", err.Error())
+		return err
+	}
+	elapsed := float64(time.Now().Sub(prevTime).Nanoseconds()) / float64(time.Millisecond)
+
+	var busy float64
+	for name, next := range nextTicks {
+		prev, ok := prevTicks[name]
+		if !ok || next < prev {
+			continue
+		}
+		if usage := float64(next-prev) / elapsed * 100; usage > busy {
+			busy = usage
+		}
+	}
+	if busy > 100 {
+		busy = 100
+	}
+
+	return setResource(DiskIOBusy, busy)
+}
+
+// readDiskIOTicks reads milliseconds spent doing I/Os of each block device except virtual ones
+func readDiskIOTicks() (map[string]uint64, error) {
+	file, err := os.Open(procDiskStatsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseDiskIOTicks(bufio.NewScanner(file)), nil
+}
+
+func parseDiskIOTicks(scanner *bufio.Scanner) map[string]uint64 {
+	ticks := make(map[string]uint64)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 {
+			continue
+		}
+
+		name := fields[2]
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") || strings.HasPrefix(name, "zram") {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[12], 10, 64)
+		if err != nil {
+			continue
+		}
+		ticks[name] = value
+	}
+	return ticks
+}
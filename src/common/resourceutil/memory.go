@@ -36,53 +36,57 @@ var (
 
 func init() {
 	mem.virtualMemory = memutil.VirtualMemory
-}
-
-func processMEMInfo() {
-	go func() {
-		for {
-			checkMemoryAvailable()
-			checkMemoryFree()
 
-			time.Sleep(time.Duration(defaultProcessingTime) * time.Second)
-		}
-	}()
+	RegisterCollector(collector{
+		name:      CollectorMemory,
+		resources: []string{MemAvailable, MemFree},
+		interval:  time.Duration(defaultProcessingTime) * time.Second,
+		collect: func() error {
+			return collectAll(checkMemoryAvailable, checkMemoryFree)
+		},
+	})
 }
 
-func checkMemoryAvailable() {
+func checkMemoryAvailable() error {
 	memStat, err := mem.virtualMemory()
 	if err != nil {
 		log.Println(logPrefix, "mem info getting fail : ", err.Error())
-		return
+		return err
 	}
 
 	info := resourceDB.ResourceInfo{}
 	info.Name = MemAvailable
 	info.Value = float64(memStat.Available) / 1024
+	if available, ok := cgroup.memoryAvailable(); ok && float64(available)/1024 < info.Value {
+		info.Value = float64(available) / 1024
+	}
 
 	err = resourceDBExecutor.Set(info)
 	if err != nil {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
 
-	return
+	return err
 }
 
-func checkMemoryFree() {
+func checkMemoryFree() error {
 	memStat, err := mem.virtualMemory()
 	if err != nil {
 		log.Println(logPrefix, "mem info getting fail : ", err.Error())
-		return
+		return err
 	}
 
 	info := resourceDB.ResourceInfo{}
 	info.Name = MemFree
 	info.Value = float64(memStat.Free) / 1024
+	if available, ok := cgroup.memoryAvailable(); ok && float64(available)/1024 < info.Value {
+		info.Value = float64(available) / 1024
+	}
 
 	err = resourceDBExecutor.Set(info)
 	if err != nil {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
 
-	return
+	return err
 }
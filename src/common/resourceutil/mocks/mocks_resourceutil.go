@@ -22,7 +22,9 @@
 package mocks
 
 import (
+	resource "db/bolt/resource"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -111,6 +113,18 @@ func (mr *MockMonitorMockRecorder) StartMonitoringResource() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartMonitoringResource", reflect.TypeOf((*MockMonitor)(nil).StartMonitoringResource))
 }
 
+// StopMonitoringResource mocks base method
+func (m *MockMonitor) StopMonitoringResource() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StopMonitoringResource")
+}
+
+// StopMonitoringResource indicates an expected call of StopMonitoringResource
+func (mr *MockMonitorMockRecorder) StopMonitoringResource() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopMonitoringResource", reflect.TypeOf((*MockMonitor)(nil).StopMonitoringResource))
+}
+
 // MockGetResource is a mock of GetResource interface
 type MockGetResource struct {
 	ctrl     *gomock.Controller
@@ -149,6 +163,21 @@ func (mr *MockGetResourceMockRecorder) GetResource(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResource", reflect.TypeOf((*MockGetResource)(nil).GetResource), arg0)
 }
 
+// GetResourceHistory mocks base method
+func (m *MockGetResource) GetResourceHistory(arg0 string, arg1 time.Duration) (resource.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceHistory", arg0, arg1)
+	ret0, _ := ret[0].(resource.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceHistory indicates an expected call of GetResourceHistory
+func (mr *MockGetResourceMockRecorder) GetResourceHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceHistory", reflect.TypeOf((*MockGetResource)(nil).GetResourceHistory), arg0, arg1)
+}
+
 // SetDeviceID mocks base method
 func (m *MockGetResource) SetDeviceID(arg0 string) {
 	m.ctrl.T.Helper()
@@ -18,7 +18,10 @@
 package resourceutil
 
 import (
+	"io/ioutil"
 	"log"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,33 +31,35 @@ import (
 )
 
 type netUtil struct {
-	linkList func() ([]netutil.Link, error)
+	linkList  func() ([]netutil.Link, error)
+	linkSpeed func(name string) (float64, error)
 }
 
+const sysClassNetPath = "/sys/class/net"
+
 var (
 	net = netUtil{}
 )
 
 func init() {
 	net.linkList = netutil.LinkList
+	net.linkSpeed = readLinkSpeed
+
+	RegisterCollector(collector{
+		name:      CollectorNetwork,
+		resources: []string{NetMBps, NetBandwidth},
+		interval:  time.Duration(defaultProcessingTime) * time.Second,
+		collect: func() error {
+			return collectAll(checkNetworkMBps, checkNetworkBandwidth)
+		},
+	})
 }
 
-func processNetInfo() {
-	go func() {
-		for {
-			checkNetworkMBps()
-			checkNetworkBandwidth()
-
-			time.Sleep(time.Duration(defaultProcessingTime) * time.Second)
-		}
-	}()
-}
-
-func checkNetworkMBps() {
+func checkNetworkMBps() error {
 	linklist, err := net.linkList()
 	if err != nil {
 		log.Println(logPrefix, "network link getting fail : ", err.Error())
-		return
+		return err
 	}
 
 	var prevTotalBytes, nextTotalBytes uint64
@@ -69,7 +74,7 @@ func checkNetworkMBps() {
 	linklist, err = net.linkList()
 	if err != nil {
 		log.Println(logPrefix, "network link getting fail : ", err.Error())
-		return
+		return err
 	}
 
 	for _, link := range linklist {
@@ -86,40 +91,59 @@ func checkNetworkMBps() {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
 
-	return
+	return err
 }
 
-func checkNetworkBandwidth() {
+// checkNetworkBandwidth stores the fastest link speed of ethernet and wireless interfaces
+func checkNetworkBandwidth() error {
 	linklist, err := net.linkList()
 	if err != nil {
 		log.Println(logPrefix, "network link getting fail : ", err.Error())
-		return
+		return err
 	}
 
-	var count, total int
+	var speed float64
 
 	for _, link := range linklist {
-		if strings.Contains(link.Attrs().Name, "eth") ||
-			strings.Contains(link.Attrs().Name, "enp") ||
-			strings.Contains(link.Attrs().Name, "wl") {
-			count++
-			total += link.Attrs().TxQLen
+		name := link.Attrs().Name
+		if !strings.Contains(name, "eth") &&
+			!strings.Contains(name, "enp") &&
+			!strings.Contains(name, "wl") {
+			continue
+		}
+
+		linkSpeed, err := net.linkSpeed(name)
+		if err != nil {
+			log.Println(logPrefix, "link speed of", name, "getting fail : ", err.Error())
+			continue
+		}
+		if linkSpeed > speed {
+			speed = linkSpeed
 		}
 	}
 
-	if count == 0 {
-		log.Println("Not matched network interface")
-		return
+	if speed <= 0 {
+		log.Println(logPrefix, "Not matched network interface with link speed")
+		return nil
 	}
 
 	info := resourceDB.ResourceInfo{}
 	info.Name = NetBandwidth
-	info.Value = float64(total / count)
+	info.Value = speed
 
 	err = resourceDBExecutor.Set(info)
 	if err != nil {
 		log.Println(logPrefix, "DB error : ", err.Error())
 	}
 
-	return
+	return err
+}
+
+// readLinkSpeed reads the link speed in Mbps, it is -1 or unreadable if the link is down or does not report it
+func readLinkSpeed(name string) (float64, error) {
+	data, err := ioutil.ReadFile(filepath.Join(sysClassNetPath, name, "speed"))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
 }
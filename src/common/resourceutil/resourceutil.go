@@ -19,6 +19,9 @@
 package resourceutil
 
 import (
+	"sync"
+	"time"
+
 	"common/errors"
 
 	resourceDB "db/bolt/resource"
@@ -30,16 +33,14 @@ type ResourceImpl struct {
 }
 
 // MonitorImpl is implementation for Monitor interface
-type MonitorImpl struct {
-	netScoring func()
-	cpuScoring func()
-	memScoring func()
-	rttScoring func()
-}
+type MonitorImpl struct{}
 
 var (
 	resourceDBExecutor resourceDB.DBInterface
 	monitoringExecutor MonitorImpl
+
+	monitoringMtx  sync.Mutex
+	monitoringStop chan struct{}
 )
 
 func init() {
@@ -59,10 +60,24 @@ const (
 	MemAvailable = "memory/available"
 	// NetMBps defined network/mbps
 	NetMBps = "network/mbps"
-	// NetBandwidth defined network/bandwidth
+	// NetBandwidth defined network/bandwidth, the link speed in Mbps
 	NetBandwidth = "network/bandwidth"
 	// NetRTT defined network/rtt
 	NetRTT = "network/rtt"
+	// NetPeerBandwidth defined network/peerbandwidth, the measured throughput to target device in Mbps
+	NetPeerBandwidth = "network/peerbandwidth"
+	// DiskTotal defined disk/total, the size of root file system in KB
+	DiskTotal = "disk/total"
+	// DiskFree defined disk/free, the space of root file system available to services in KB
+	DiskFree = "disk/free"
+	// DiskIOBusy defined disk/iobusy, the percentage of time the busiest disk is doing I/O
+	DiskIOBusy = "disk/iobusy"
+	// ThermalTemp defined thermal/temperature, the hottest thermal zone in degrees Celsius
+	ThermalTemp = "thermal/temperature"
+	// PowerBattery defined power/battery, the battery capacity in percent
+	PowerBattery = "power/battery"
+	// PowerAC defined power/ac, 1 if an external power source is online and 0 if it runs on battery
+	PowerAC = "power/ac"
 
 	logPrefix             = "resourceutil"
 	defaultProcessingTime = 5
@@ -77,128 +92,106 @@ type Command interface {
 // Monitor is an interface to get device resource
 type Monitor interface {
 	StartMonitoringResource()
+	StopMonitoringResource()
 }
 
 // GetResource is an interface to get reource
 type GetResource interface {
 	GetResource(string) (float64, error)
+	GetResourceHistory(string, time.Duration) (resourceDB.History, error)
 	SetDeviceID(string)
 }
 
 // GetMonitoringInstance return MonitorImpl instance
 func GetMonitoringInstance() *MonitorImpl {
-	monitoringExecutor.netScoring = processNetInfo
-	monitoringExecutor.cpuScoring = processCPUInfo
-	monitoringExecutor.memScoring = processMEMInfo
-	monitoringExecutor.rttScoring = processRTT
 	return &monitoringExecutor
 }
 
-// StartMonitoringResource to get device resources
+// StartMonitoringResource runs every registered collector to get device resources
 func (m MonitorImpl) StartMonitoringResource() {
-	m.netScoring()
-	m.cpuScoring()
-	m.memScoring()
-	m.rttScoring()
+	monitoringMtx.Lock()
+	if monitoringStop != nil {
+		monitoringMtx.Unlock()
+		return
+	}
+	monitoringStop = make(chan struct{})
+	stop := monitoringStop
+	monitoringMtx.Unlock()
+
+	for _, c := range getCollectors() {
+		go runCollector(c, stop)
+	}
 }
 
-// GetResource returns a resource value that matches resourceName
-func (r *ResourceImpl) GetResource(resourceName string) (float64, error) {
-	switch resourceName {
-	case CPUUsage:
-		return getCPUUsage()
-	case CPUCount:
-		return getCPUCount()
-	case CPUFreq:
-		return getCPUFreq()
-	case MemFree:
-		return getMemoryFree()
-	case MemAvailable:
-		return getMemoryAvailable()
-	case NetMBps:
-		return getNetworkMBps()
-	case NetBandwidth:
-		return getNetworkBandwidth()
-	case NetRTT:
-		return getNetworkRTT(r.targetDeviceID)
-	default:
-		return 0.0, errors.NotSupport{Message: "Not suppoted resource name"}
+// StopMonitoringResource stops every resource monitoring routine
+func (m MonitorImpl) StopMonitoringResource() {
+	monitoringMtx.Lock()
+	defer monitoringMtx.Unlock()
+
+	if monitoringStop != nil {
+		close(monitoringStop)
+		monitoringStop = nil
 	}
 }
 
-// SetDeviceID set target device's id for RTT
-func (r *ResourceImpl) SetDeviceID(ID string) {
-	r.targetDeviceID = ID
+func getMonitoringStopChan() <-chan struct{} {
+	monitoringMtx.Lock()
+	defer monitoringMtx.Unlock()
+
+	return monitoringStop
 }
 
-func getCPUUsage() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(CPUUsage)
-	if err != nil {
-		return
+// waitNextMonitoring waits for next monitoring period and returns false if monitoring is stopped
+func waitNextMonitoring(stop <-chan struct{}, period time.Duration) bool {
+	select {
+	case <-stop:
+		return false
+	case <-time.After(period):
+		return true
 	}
-	out = info.Value
-	return
 }
 
-func getCPUFreq() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(CPUFreq)
-	if err != nil {
-		return
+// GetResource returns a resource value that matches resourceName
+func (r *ResourceImpl) GetResource(resourceName string) (float64, error) {
+	c, exist := getResourceCollector(resourceName)
+	if !exist {
+		return 0.0, errors.NotSupport{Message: "Not suppoted resource name"}
 	}
-	out = info.Value
-	return
-}
 
-func getCPUCount() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(CPUCount)
-	if err != nil {
-		return
+	if peer, ok := c.(PeerCollector); ok {
+		return peer.GetPeerResource(resourceName, r.targetDeviceID)
 	}
-	out = info.Value
-	return
+	return getStoredResource(resourceName)
 }
 
-func getMemoryAvailable() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(MemAvailable)
-	if err != nil {
-		return
+// GetResourceHistory returns the samples of a resource in the last window with their statistics
+func (r *ResourceImpl) GetResourceHistory(resourceName string, window time.Duration) (resourceDB.History, error) {
+	c, exist := getResourceCollector(resourceName)
+	if !exist {
+		return resourceDB.History{}, errors.NotSupport{Message: "Not suppoted resource name"}
 	}
-	out = info.Value
-	return
-}
 
-func getMemoryFree() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(MemFree)
-	if err != nil {
-		return
+	if _, ok := c.(PeerCollector); ok {
+		return resourceDB.History{}, errors.NotSupport{Message: "history of " + resourceName + " is not kept"}
 	}
-	out = info.Value
-	return
+	return resourceDBExecutor.GetHistory(resourceName, window)
 }
 
-func getNetworkMBps() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(NetMBps)
-	if err != nil {
-		return
-	}
-	out = info.Value
-	return
+// SetHistoryRetention sets the number of samples kept for each resource
+func SetHistoryRetention(size int) error {
+	return resourceDB.SetRetention(size)
 }
 
-func getNetworkBandwidth() (out float64, err error) {
-	info, err := resourceDBExecutor.Get(NetBandwidth)
-	if err != nil {
-		return
-	}
-	out = info.Value
-	return
+// SetDeviceID set target device's id for RTT
+func (r *ResourceImpl) SetDeviceID(ID string) {
+	r.targetDeviceID = ID
 }
 
-func getNetworkRTT(ID string) (out float64, err error) {
-	info, err := netDBExecutor.Get(ID)
+func getStoredResource(name string) (out float64, err error) {
+	info, err := resourceDBExecutor.Get(name)
 	if err != nil {
 		return
 	}
-	out = info.RTT
+	out = info.Value
 	return
 }
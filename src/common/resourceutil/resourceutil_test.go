@@ -18,7 +18,14 @@
 package resourceutil
 
 import (
+	"bufio"
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -28,8 +35,11 @@ import (
 	memutil "github.com/shirou/gopsutil/mem"
 	netutil "github.com/vishvananda/netlink"
 
+	netDB "db/bolt/network"
+	netDBMock "db/bolt/network/mocks"
 	resourceDB "db/bolt/resource"
 	resourceDBMock "db/bolt/resource/mocks"
+	helperMock "restinterface/resthelper/mocks"
 )
 
 type dummpyLink struct {
@@ -159,76 +169,19 @@ func fakeLinkListWithError() ([]netutil.Link, error) {
 	return linkList, errors.New("fakeLinkListPrevWithError")
 }
 
+func fakeLinkSpeed(name string) (float64, error) {
+	return dummyNetBandwidthResult, nil
+}
+
 func setupTestCase() {
+	cgroup.inContainer = func() bool { return false }
 	net.linkList = fakeLinkList
+	net.linkSpeed = fakeLinkSpeed
 	cpu.percent = fakeCPUPercent
 	cpu.info = fakeCPUInfo
 	mem.virtualMemory = fakeVirtualMemory
 }
 
-func setupNetMBpsTest() {
-	monitoringExecutor.netScoring = func() {
-		checkNetworkMBps()
-	}
-	monitoringExecutor.cpuScoring = func() {}
-	monitoringExecutor.memScoring = func() {}
-	monitoringExecutor.rttScoring = func() {}
-}
-
-func setupNetBandwidthTest() {
-	monitoringExecutor.netScoring = func() {
-		checkNetworkBandwidth()
-	}
-	monitoringExecutor.cpuScoring = func() {}
-	monitoringExecutor.memScoring = func() {}
-	monitoringExecutor.rttScoring = func() {}
-}
-
-func setupCPUUsageTest() {
-	monitoringExecutor.netScoring = func() {}
-	monitoringExecutor.cpuScoring = func() {
-		checkCPUUsage()
-	}
-	monitoringExecutor.memScoring = func() {}
-	monitoringExecutor.rttScoring = func() {}
-}
-
-func setupCPUFreqTest() {
-	monitoringExecutor.netScoring = func() {}
-	monitoringExecutor.cpuScoring = func() {
-		checkCPUFreq()
-	}
-	monitoringExecutor.memScoring = func() {}
-	monitoringExecutor.rttScoring = func() {}
-}
-
-func setupCPUCountTest() {
-	monitoringExecutor.netScoring = func() {}
-	monitoringExecutor.cpuScoring = func() {
-		checkCPUCount()
-	}
-	monitoringExecutor.memScoring = func() {}
-	monitoringExecutor.rttScoring = func() {}
-}
-
-func setupMemAvailableTest() {
-	monitoringExecutor.netScoring = func() {}
-	monitoringExecutor.cpuScoring = func() {}
-	monitoringExecutor.memScoring = func() {
-		checkMemoryAvailable()
-	}
-	monitoringExecutor.rttScoring = func() {}
-}
-
-func setupMemFreeTest() {
-	monitoringExecutor.netScoring = func() {}
-	monitoringExecutor.cpuScoring = func() {}
-	monitoringExecutor.memScoring = func() {
-		checkMemoryFree()
-	}
-	monitoringExecutor.rttScoring = func() {}
-}
-
 func TestGetCPUUsage_ExpectedSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -246,9 +199,7 @@ func TestGetCPUUsage_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupCPUUsageTest()
-	monitoringImpl.StartMonitoringResource()
+	checkCPUUsage()
 
 	cpuUsage, err := resourceIns.GetResource(CPUUsage)
 	if err != nil {
@@ -277,9 +228,7 @@ func TestGetCPUFreq_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupCPUFreqTest()
-	monitoringImpl.StartMonitoringResource()
+	checkCPUFreq()
 
 	cpuFreq, err := resourceIns.GetResource(CPUFreq)
 	if err != nil {
@@ -308,9 +257,7 @@ func TestGetCPUCount_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupCPUCountTest()
-	monitoringImpl.StartMonitoringResource()
+	checkCPUCount()
 
 	cpuCount, err := resourceIns.GetResource(CPUCount)
 	if err != nil {
@@ -339,9 +286,7 @@ func TestGetMemAvailable_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupMemAvailableTest()
-	monitoringImpl.StartMonitoringResource()
+	checkMemoryAvailable()
 
 	memAvailable, err := resourceIns.GetResource(MemAvailable)
 	if err != nil {
@@ -370,9 +315,7 @@ func TestGetMemFree_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupMemFreeTest()
-	monitoringImpl.StartMonitoringResource()
+	checkMemoryFree()
 
 	memFree, err := resourceIns.GetResource(MemFree)
 	if err != nil {
@@ -401,9 +344,7 @@ func TestGetNetMBps_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupNetMBpsTest()
-	monitoringImpl.StartMonitoringResource()
+	checkNetworkMBps()
 
 	netMBps, err := resourceIns.GetResource(NetMBps)
 	if err != nil {
@@ -432,9 +373,7 @@ func TestGetNetBandwidth_ExpectedSuccess(t *testing.T) {
 
 	setupTestCase()
 
-	monitoringImpl := GetMonitoringInstance()
-	setupNetBandwidthTest()
-	monitoringImpl.StartMonitoringResource()
+	checkNetworkBandwidth()
 
 	netBandwidth, err := resourceIns.GetResource(NetBandwidth)
 	if err != nil {
@@ -445,3 +384,570 @@ func TestGetNetBandwidth_ExpectedSuccess(t *testing.T) {
 		t.Errorf("%f != %f", netBandwidth, dummyNetBandwidthResult)
 	}
 }
+
+// swapCollectors replaces the registered collectors for a test and returns the function restoring them
+func swapCollectors(list ...Collector) func() {
+	collectorMtx.Lock()
+	prevCollectors, prevResources, prevIntervals := collectors, resourceCollectors, collectorIntervals
+	collectors, resourceCollectors, collectorIntervals = nil, make(map[string]Collector), make(map[string]time.Duration)
+	collectorMtx.Unlock()
+
+	for _, c := range list {
+		RegisterCollector(c)
+	}
+
+	return func() {
+		collectorMtx.Lock()
+		defer collectorMtx.Unlock()
+		collectors, resourceCollectors, collectorIntervals = prevCollectors, prevResources, prevIntervals
+	}
+}
+
+func TestStopMonitoringResource(t *testing.T) {
+	collected := make(chan struct{}, 1)
+	defer swapCollectors(collector{
+		name:     "test",
+		interval: time.Hour,
+		collect: func() error {
+			collected <- struct{}{}
+			return nil
+		},
+	})()
+
+	monitoringImpl := GetMonitoringInstance()
+	monitoringImpl.StartMonitoringResource()
+
+	stop := getMonitoringStopChan()
+	if stop == nil {
+		t.Fatal("unexpected nil stop channel")
+	}
+
+	select {
+	case <-collected:
+	case <-time.After(time.Second):
+		t.Error("collector is not started")
+	}
+
+	// NOTE : should not start collectors again while monitoring
+	monitoringImpl.StartMonitoringResource()
+	if getMonitoringStopChan() != stop {
+		t.Error("unexpected restart of monitoring")
+	}
+
+	monitoringImpl.StopMonitoringResource()
+	if waitNextMonitoring(stop, time.Second) {
+		t.Error("monitoring is not stopped")
+	}
+	if getMonitoringStopChan() != nil {
+		t.Error("unexpected stop channel")
+	}
+
+	// NOTE : should not panic on duplicated stop
+	monitoringImpl.StopMonitoringResource()
+}
+
+func TestRegisterCollector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceDBMockObj := resourceDBMock.NewMockDBInterface(ctrl)
+	resourceDBExecutor = resourceDBMockObj
+
+	defer swapCollectors()()
+
+	custom := collector{
+		name:      "gpu",
+		resources: []string{"gpu/usage"},
+		interval:  time.Second,
+		collect:   func() error { return nil },
+	}
+	peer := peerCollector{
+		collector: collector{
+			name:      "latency",
+			resources: []string{"network/latency"},
+			interval:  time.Second,
+			collect:   func() error { return nil },
+		},
+		get: func(resourceName string, deviceID string) (float64, error) {
+			if deviceID != "peer" {
+				t.Error("unexpected device id : ", deviceID)
+			}
+			return 3.0, nil
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		if err := RegisterCollector(custom); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := RegisterCollector(peer); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		resourceDBMockObj.EXPECT().Get("gpu/usage").Return(resourceDB.ResourceInfo{Name: "gpu/usage", Value: 42.0}, nil)
+		if value, err := resourceIns.GetResource("gpu/usage"); err != nil || value != 42.0 {
+			t.Error("unexpected resource : ", value, err)
+		}
+
+		resourceIns.SetDeviceID("peer")
+		if value, err := resourceIns.GetResource("network/latency"); err != nil || value != 3.0 {
+			t.Error("unexpected peer resource : ", value, err)
+		}
+		if _, err := resourceIns.GetResourceHistory("network/latency", time.Minute); err == nil {
+			t.Error("expected error of peer resource history")
+		}
+	})
+	t.Run("Metrics", func(t *testing.T) {
+		resourceDBMockObj.EXPECT().Get("gpu/usage").Return(resourceDB.ResourceInfo{Name: "gpu/usage", Value: 42.0}, nil)
+
+		values := getStoredResources()
+		if len(values) != 1 || values["gpu/usage"] != 42.0 {
+			t.Error("unexpected stored resources : ", values)
+		}
+	})
+	t.Run("Duplicated", func(t *testing.T) {
+		if err := RegisterCollector(custom); err == nil {
+			t.Error("expected error of duplicated name")
+		}
+
+		other := custom
+		other.name = "gpu2"
+		if err := RegisterCollector(other); err == nil {
+			t.Error("expected error of duplicated resource")
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		if err := RegisterCollector(nil); err == nil {
+			t.Error("expected error of nil collector")
+		}
+		if err := RegisterCollector(collector{name: "zero"}); err == nil {
+			t.Error("expected error of zero interval")
+		}
+	})
+	t.Run("NotSupported", func(t *testing.T) {
+		if _, err := resourceIns.GetResource("unknown"); err == nil {
+			t.Error("expected error of unknown resource")
+		}
+	})
+}
+
+func TestSetCollectorInterval(t *testing.T) {
+	c := collector{name: "test", interval: time.Second, collect: func() error { return nil }}
+	defer swapCollectors(c)()
+
+	if getCollectorInterval(c) != time.Second {
+		t.Error("unexpected default interval")
+	}
+	if err := SetCollectorInterval("test", time.Minute); err != nil || getCollectorInterval(c) != time.Minute {
+		t.Error("unexpected interval : ", getCollectorInterval(c), err)
+	}
+	if err := SetCollectorInterval("unknown", time.Minute); err == nil {
+		t.Error("expected error of unknown collector")
+	}
+	if err := SetCollectorInterval("test", 0); err == nil {
+		t.Error("expected error of zero interval")
+	}
+}
+
+func TestReadCollectorIntervals(t *testing.T) {
+	file, err := ioutil.TempFile("", "monitoring")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.Remove(file.Name())
+
+	file.WriteString("# intervals\nrtt=10s\ncpu = 1m\ninvalid\ndisk=-1s\nthermal=abc\n")
+	file.Close()
+
+	intervals, err := ReadCollectorIntervals(file.Name())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(intervals) != 2 || intervals["rtt"] != 10*time.Second || intervals["cpu"] != time.Minute {
+		t.Error("unexpected intervals : ", intervals)
+	}
+
+	if _, err := ReadCollectorIntervals(file.Name() + ".none"); err == nil {
+		t.Error("expected error of missing file")
+	}
+}
+
+func TestBackoffInterval(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		failures int
+		expected time.Duration
+	}{
+		{5 * time.Second, 0, 5 * time.Second},
+		{5 * time.Second, 1, 10 * time.Second},
+		{5 * time.Second, 3, 40 * time.Second},
+		{5 * time.Second, 100, maxCollectorBackoff},
+		{10 * time.Minute, 2, 10 * time.Minute},
+	}
+
+	for _, test := range tests {
+		if backoff := backoffInterval(test.interval, test.failures); backoff != test.expected {
+			t.Error("unexpected backoff : ", test, backoff)
+		}
+	}
+}
+
+func TestGetResourceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceDBMockObj := resourceDBMock.NewMockDBInterface(ctrl)
+	resourceDBExecutor = resourceDBMockObj
+
+	t.Run("Success", func(t *testing.T) {
+		expected := resourceDB.History{Name: CPUUsage, Window: time.Minute, Count: 3, Avg: 10.0}
+		resourceDBMockObj.EXPECT().GetHistory(CPUUsage, time.Minute).Return(expected, nil)
+
+		history, err := resourceIns.GetResourceHistory(CPUUsage, time.Minute)
+		if err != nil {
+			t.Error(err.Error())
+		} else if history.Avg != expected.Avg || history.Count != expected.Count {
+			t.Error("unexpected history : ", history)
+		}
+	})
+	t.Run("NotSupported", func(t *testing.T) {
+		if _, err := resourceIns.GetResourceHistory(NetRTT, time.Minute); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestCheckPeerBandwidth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	helperMockObj := helperMock.NewMockRestHelper(ctrl)
+	netDBMockObj := netDBMock.NewMockDBInterface(ctrl)
+
+	prevHelper, prevNetDB := helper, netDBExecutor
+	helper, netDBExecutor = helperMockObj, netDBMockObj
+	defer func() { helper, netDBExecutor = prevHelper, prevNetDB }()
+
+	info := netDB.NetworkInfo{ID: "peer", IPv4: []string{"10.0.0.2"}}
+
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			helperMockObj.EXPECT().MakeTargetURL("10.0.0.2", internalPort, pingAPI).Return("ping"),
+			helperMockObj.EXPECT().DoGet("ping").Return(nil, 200, nil),
+			helperMockObj.EXPECT().MakeTargetURL("10.0.0.2", internalPort, gomock.Any()).Return("throughput"),
+			helperMockObj.EXPECT().DoGet("throughput").Return(make([]byte, throughputProbeSize), 200, nil),
+			netDBMockObj.EXPECT().Update(gomock.Any()).Do(func(updated netDB.NetworkInfo) {
+				if updated.ID != "peer" || updated.Bandwidth <= 0 || updated.RTT != 0 {
+					t.Error("unexpected update : ", updated)
+				}
+			}).Return(nil),
+		)
+
+		checkPeerBandwidth(info)
+	})
+	t.Run("Unreachable", func(t *testing.T) {
+		gomock.InOrder(
+			helperMockObj.EXPECT().MakeTargetURL("10.0.0.2", internalPort, pingAPI).Return("ping"),
+			helperMockObj.EXPECT().DoGet("ping").Return(nil, 0, errors.New("unreachable")),
+		)
+
+		checkPeerBandwidth(info)
+	})
+}
+
+func TestCheckPeerRTT(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	helperMockObj := helperMock.NewMockRestHelper(ctrl)
+	netDBMockObj := netDBMock.NewMockDBInterface(ctrl)
+
+	prevHelper, prevNetDB := helper, netDBExecutor
+	helper, netDBExecutor = helperMockObj, netDBMockObj
+	defer func() { helper, netDBExecutor = prevHelper, prevNetDB }()
+
+	t.Run("PartialLoss", func(t *testing.T) {
+		info := netDB.NetworkInfo{ID: "peer", IPv4: []string{"10.0.0.2", "10.0.0.3"}}
+
+		helperMockObj.EXPECT().MakeTargetURL("10.0.0.2", internalPort, pingAPI).Return("reachable").Times(rttProbeCount)
+		helperMockObj.EXPECT().MakeTargetURL("10.0.0.3", internalPort, pingAPI).Return("unreachable").Times(rttProbeCount)
+		gomock.InOrder(
+			helperMockObj.EXPECT().DoGet("reachable").Return(nil, 200, nil).Times(rttProbeCount-1),
+			helperMockObj.EXPECT().DoGet("reachable").Return(nil, 0, errors.New("timeout")),
+		)
+		helperMockObj.EXPECT().DoGet("unreachable").Return(nil, 0, errors.New("timeout")).Times(rttProbeCount)
+		netDBMockObj.EXPECT().Update(gomock.Any()).Do(func(updated netDB.NetworkInfo) {
+			if updated.Unreachable || updated.RTT <= 0 || updated.Loss != 0.25 {
+				t.Error("unexpected update : ", updated)
+			}
+			if len(updated.Probes) != 2 || updated.Probes["10.0.0.3"].Loss != 1.0 {
+				t.Error("unexpected probes : ", updated.Probes)
+			}
+		}).Return(nil)
+
+		checkPeerRTT(info)
+	})
+	t.Run("Unreachable", func(t *testing.T) {
+		info := netDB.NetworkInfo{ID: "peer", IPv4: []string{"10.0.0.3"}}
+
+		helperMockObj.EXPECT().MakeTargetURL("10.0.0.3", internalPort, pingAPI).Return("unreachable").Times(rttProbeCount)
+		helperMockObj.EXPECT().DoGet("unreachable").Return(nil, 0, errors.New("timeout")).Times(rttProbeCount)
+		netDBMockObj.EXPECT().Update(gomock.Any()).Do(func(updated netDB.NetworkInfo) {
+			if !updated.Unreachable || updated.RTT != 0 || updated.Loss != 1.0 {
+				t.Error("unexpected update : ", updated)
+			}
+		}).Return(nil)
+
+		checkPeerRTT(info)
+
+		netDBMockObj.EXPECT().Get("peer").Return(netDB.NetworkInfo{ID: "peer", Unreachable: true}, nil)
+		resourceIns.SetDeviceID("peer")
+		if _, err := resourceIns.GetResource(NetRTT); err == nil {
+			t.Error("expected error of unreachable device")
+		}
+	})
+	t.Run("InvalidInterval", func(t *testing.T) {
+		if err := SetRTTProbeInterval(0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestCheckRTTs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	helperMockObj := helperMock.NewMockRestHelper(ctrl)
+	netDBMockObj := netDBMock.NewMockDBInterface(ctrl)
+
+	prevHelper, prevNetDB := helper, netDBExecutor
+	helper, netDBExecutor = helperMockObj, netDBMockObj
+	defer func() { helper, netDBExecutor = prevHelper, prevNetDB }()
+
+	netDBMockObj.EXPECT().GetList().Return([]netDB.NetworkInfo{
+		{ID: "peer1", IPv4: []string{"10.0.0.2"}},
+		{ID: "peer2", IPv4: []string{"10.0.0.3"}},
+	}, nil)
+	helperMockObj.EXPECT().MakeTargetURL(gomock.Any(), internalPort, pingAPI).Return("ping").Times(2 * rttProbeCount)
+	helperMockObj.EXPECT().DoGet("ping").Return(nil, 200, nil).Times(2 * rttProbeCount)
+
+	var mtx sync.Mutex
+	updated := make(map[string]bool)
+	netDBMockObj.EXPECT().Update(gomock.Any()).Do(func(info netDB.NetworkInfo) {
+		mtx.Lock()
+		updated[info.ID] = true
+		mtx.Unlock()
+	}).Return(nil).Times(2)
+
+	if err := checkRTTs(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// @Note : every probe is done when the collection returns
+	mtx.Lock()
+	defer mtx.Unlock()
+	if !updated["peer1"] || !updated["peer2"] {
+		t.Error("probes are not done : ", updated)
+	}
+}
+
+func TestParseDiskIOTicks(t *testing.T) {
+	stats := `   7       0 loop0 100 0 200 10 0 0 0 0 0 50 10
+   8       0 sda 1000 10 20000 300 500 20 8000 200 0 1500 500
+   8       1 sda1 900 10 18000 250 400 20 7000 150 0 1200 400
+ 179       0 mmcblk0 10 0 20 1 0 0 0 0 0 7
+ 253       0 broken 1 2
+`
+	ticks := parseDiskIOTicks(bufio.NewScanner(strings.NewReader(stats)))
+	if len(ticks) != 3 || ticks["sda"] != 1500 || ticks["sda1"] != 1200 || ticks["mmcblk0"] != 7 {
+		t.Error("unexpected ticks : ", ticks)
+	}
+}
+
+func TestCheckDiskResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceDBMockObj := resourceDBMock.NewMockDBInterface(ctrl)
+	resourceDBExecutor = resourceDBMockObj
+
+	prev := disk
+	defer func() { disk = prev }()
+
+	disk.statfs = func(path string, buf *syscall.Statfs_t) error {
+		buf.Bsize = 4096
+		buf.Blocks = 1024
+		buf.Bavail = 256
+		return nil
+	}
+	calls := 0
+	disk.diskStats = func() (map[string]uint64, error) {
+		calls++
+		return map[string]uint64{"sda": uint64(calls * 500)}, nil
+	}
+
+	gomock.InOrder(
+		resourceDBMockObj.EXPECT().Set(resourceDB.ResourceInfo{Name: DiskTotal, Value: 4096}).Return(nil),
+		resourceDBMockObj.EXPECT().Set(resourceDB.ResourceInfo{Name: DiskFree, Value: 1024}).Return(nil),
+		resourceDBMockObj.EXPECT().Set(gomock.Any()).Do(func(info resourceDB.ResourceInfo) {
+			if info.Name != DiskIOBusy || info.Value <= 0 || info.Value > 100 {
+				t.Error("unexpected io busy : ", info)
+			}
+		}).Return(nil),
+	)
+
+	checkDiskSpace()
+	checkDiskIOBusy()
+}
+
+func TestCheckThermalAndPower(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceDBMockObj := resourceDBMock.NewMockDBInterface(ctrl)
+	resourceDBExecutor = resourceDBMockObj
+
+	root, err := ioutil.TempDir("", "resourceutil")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(root)
+
+	writeFile := func(path, value string) {
+		os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err := ioutil.WriteFile(path, []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	writeFile(filepath.Join(root, "thermal", "thermal_zone0", "temp"), "45000")
+	writeFile(filepath.Join(root, "thermal", "thermal_zone1", "temp"), "61500")
+	writeFile(filepath.Join(root, "power_supply", "BAT0", "type"), "Battery")
+	writeFile(filepath.Join(root, "power_supply", "BAT0", "capacity"), "80")
+	writeFile(filepath.Join(root, "power_supply", "AC", "type"), "Mains")
+	writeFile(filepath.Join(root, "power_supply", "AC", "online"), "0")
+
+	prevThermal, prevPower := sysClassThermalPath, sysClassPowerSupplyPath
+	sysClassThermalPath = filepath.Join(root, "thermal")
+	sysClassPowerSupplyPath = filepath.Join(root, "power_supply")
+	defer func() { sysClassThermalPath, sysClassPowerSupplyPath = prevThermal, prevPower }()
+
+	gomock.InOrder(
+		resourceDBMockObj.EXPECT().Set(resourceDB.ResourceInfo{Name: ThermalTemp, Value: 61.5}).Return(nil),
+		resourceDBMockObj.EXPECT().Set(resourceDB.ResourceInfo{Name: PowerBattery, Value: 80}).Return(nil),
+		resourceDBMockObj.EXPECT().Set(resourceDB.ResourceInfo{Name: PowerAC, Value: 0}).Return(nil),
+	)
+
+	checkTemperature()
+	checkPowerSupply()
+
+	t.Run("NoSensor", func(t *testing.T) {
+		sysClassThermalPath = filepath.Join(root, "none")
+		sysClassPowerSupplyPath = filepath.Join(root, "none")
+
+		checkTemperature()
+		checkPowerSupply()
+	})
+}
+
+func TestCgroupResources(t *testing.T) {
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		root, err := ioutil.TempDir("", "cgroup")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for name, value := range files {
+			path := filepath.Join(root, name)
+			os.MkdirAll(filepath.Dir(path), os.ModePerm)
+			if err := ioutil.WriteFile(path, []byte(value), 0644); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+		return root
+	}
+
+	prev := cgroup
+	defer func() { cgroup = prev }()
+	cgroup.inContainer = func() bool { return true }
+
+	t.Run("V1", func(t *testing.T) {
+		cgroup.root = writeFiles(t, map[string]string{
+			"cpu/cpu.cfs_quota_us":         "150000\n",
+			"cpu/cpu.cfs_period_us":        "100000\n",
+			"cpuacct/cpuacct.usage":        "2500000000\n",
+			"memory/memory.limit_in_bytes": "1073741824\n",
+			"memory/memory.usage_in_bytes": "536870912\n",
+			"memory/memory.stat":           "cache 100\ntotal_inactive_file 268435456\n",
+		})
+		defer os.RemoveAll(cgroup.root)
+
+		if cpus, ok := cgroup.cpuLimit(); !ok || cpus != 1.5 {
+			t.Error("unexpected cpu limit : ", cpus, ok)
+		}
+		if usage, err := cgroup.cpuUsageSeconds(); err != nil || usage != 2.5 {
+			t.Error("unexpected cpu usage : ", usage, err)
+		}
+		if available, ok := cgroup.memoryAvailable(); !ok || available != 805306368 {
+			t.Error("unexpected available memory : ", available, ok)
+		}
+	})
+	t.Run("V2", func(t *testing.T) {
+		cgroup.root = writeFiles(t, map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"cpu.max":            "50000 100000\n",
+			"cpu.stat":           "usage_usec 1500000\nuser_usec 1000000\n",
+			"memory.max":         "1073741824\n",
+			"memory.current":     "268435456\n",
+			"memory.stat":        "anon 100\ninactive_file 0\n",
+		})
+		defer os.RemoveAll(cgroup.root)
+
+		if cpus, ok := cgroup.cpuLimit(); !ok || cpus != 0.5 {
+			t.Error("unexpected cpu limit : ", cpus, ok)
+		}
+		if usage, err := cgroup.cpuUsageSeconds(); err != nil || usage != 1.5 {
+			t.Error("unexpected cpu usage : ", usage, err)
+		}
+		if available, ok := cgroup.memoryAvailable(); !ok || available != 805306368 {
+			t.Error("unexpected available memory : ", available, ok)
+		}
+	})
+	t.Run("V2Unlimited", func(t *testing.T) {
+		cgroup.root = writeFiles(t, map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"cpu.max":            "max 100000\n",
+			"memory.max":         "max\n",
+			"memory.current":     "268435456\n",
+		})
+		defer os.RemoveAll(cgroup.root)
+
+		if _, ok := cgroup.cpuLimit(); ok {
+			t.Error("unexpected cpu limit")
+		}
+		if _, ok := cgroup.memoryAvailable(); ok {
+			t.Error("unexpected memory limit")
+		}
+	})
+	t.Run("CPUCount", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cgroup.root = writeFiles(t, map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"cpu.max":            "150000 100000\n",
+		})
+		defer os.RemoveAll(cgroup.root)
+
+		resourceDBMockObj := resourceDBMock.NewMockDBInterface(ctrl)
+		resourceDBExecutor = resourceDBMockObj
+		cpu.info = fakeCPUInfo
+
+		resourceDBMockObj.EXPECT().Set(resourceDB.ResourceInfo{Name: CPUCount, Value: 1.5}).Return(nil)
+		checkCPUCount()
+	})
+	t.Run("NotInContainer", func(t *testing.T) {
+		cgroup.inContainer = func() bool { return false }
+		if _, ok := cgroup.cpuLimit(); ok {
+			t.Error("unexpected cpu limit")
+		}
+	})
+}
@@ -19,9 +19,12 @@
 package resourceutil
 
 import (
-	"fmt"
+	"log"
+	"math"
+	"sync"
 	"time"
 
+	"common/errors"
 	"restinterface/resthelper"
 
 	netDB "db/bolt/network"
@@ -31,6 +34,9 @@ const (
 	pingAPI            = "/api/v1/ping"
 	internalPort       = 56001
 	defaultRttDuration = 5
+
+	// rttProbeCount is the number of pings to each address in a probe
+	rttProbeCount = 4
 )
 
 var (
@@ -41,33 +47,126 @@ var (
 func init() {
 	helper = resthelper.GetHelper()
 	netDBExecutor = netDB.Query{}
+
+	RegisterCollector(peerCollector{
+		collector: collector{
+			name:      CollectorRTT,
+			resources: []string{NetRTT},
+			interval:  time.Duration(defaultRttDuration) * time.Second,
+			collect:   checkRTTs,
+		},
+		get: func(resourceName string, deviceID string) (float64, error) {
+			return getNetworkRTT(deviceID)
+		},
+	})
+}
+
+// SetRTTProbeInterval sets the period of round trip time probes to other devices
+func SetRTTProbeInterval(interval time.Duration) error {
+	return SetCollectorInterval(CollectorRTT, interval)
+}
+
+// checkRTTs probes every peer concurrently and returns after every probe is done,
+// the next probes do not start before it not to pile up probes of unreachable peers
+func checkRTTs() error {
+	netInfos, err := netDBExecutor.GetList()
+	if err != nil {
+		return err
+	}
+
+	var wait sync.WaitGroup
+	wait.Add(len(netInfos))
+	for _, netInfo := range netInfos {
+		go func(info netDB.NetworkInfo) {
+			defer wait.Done()
+			checkPeerRTT(info)
+		}(netInfo)
+	}
+	wait.Wait()
+	return nil
 }
 
-func processRTT() {
-	go func() {
-		for {
-			netInfos, err := netDBExecutor.GetList()
-			if err != nil {
-				return
-			}
-
-			for _, netInfo := range netInfos {
-				totalCount := len(netInfo.IPv4)
-				ch := make(chan float64, totalCount)
-				for _, ip := range netInfo.IPv4 {
-					go func(targetIP string) {
-						ch <- checkRTT(targetIP)
-					}(ip)
-				}
-				go func(info netDB.NetworkInfo) {
-					result := selectMinRTT(ch, totalCount)
-					info.RTT = result
-					netDBExecutor.Update(info)
-				}(netInfo)
-			}
-			time.Sleep(time.Duration(defaultRttDuration) * time.Second)
+func getNetworkRTT(ID string) (out float64, err error) {
+	info, err := netDBExecutor.Get(ID)
+	if err != nil {
+		return
+	}
+	if info.Unreachable {
+		return 0.0, errors.NetworkError{Message: ID + " is unreachable"}
+	}
+	out = info.RTT
+	return
+}
+
+// checkPeerRTT probes every address of the device concurrently and keeps the statistics of the fastest one,
+// the device is unreachable if no address answers
+func checkPeerRTT(info netDB.NetworkInfo) {
+	type probe struct {
+		ip     string
+		result netDB.ProbeResult
+	}
+
+	ch := make(chan probe, len(info.IPv4))
+	for _, ip := range info.IPv4 {
+		go func(targetIP string) {
+			ch <- probe{targetIP, probeRTT(targetIP)}
+		}(ip)
+	}
+
+	updated := netDB.NetworkInfo{
+		ID:          info.ID,
+		Unreachable: true,
+		Loss:        1.0,
+		Probes:      make(map[string]netDB.ProbeResult),
+	}
+	for range info.IPv4 {
+		p := <-ch
+		updated.Probes[p.ip] = p.result
+		if p.result.Loss >= 1.0 {
+			continue
+		}
+		if updated.Unreachable || p.result.RTT < updated.RTT {
+			updated.RTT = p.result.RTT
+			updated.Jitter = p.result.Jitter
+			updated.Loss = p.result.Loss
+			updated.Unreachable = false
 		}
-	}()
+	}
+
+	if updated.Unreachable {
+		log.Println(logPrefix, info.ID, "is unreachable")
+	}
+
+	if err := netDBExecutor.Update(updated); err != nil {
+		log.Println(logPrefix, "DB error : ", err.Error())
+	}
+}
+
+// probeRTT pings the address several times, Jitter is the mean difference of consecutive round trip times
+func probeRTT(ip string) (result netDB.ProbeResult) {
+	var rtts []float64
+	for i := 0; i < rttProbeCount; i++ {
+		if rtt := checkRTT(ip); rtt != 0 {
+			rtts = append(rtts, rtt)
+		}
+	}
+
+	result.Loss = float64(rttProbeCount-len(rtts)) / float64(rttProbeCount)
+	if len(rtts) == 0 {
+		return
+	}
+
+	for idx, rtt := range rtts {
+		result.RTT += rtt
+		if idx > 0 {
+			result.Jitter += math.Abs(rtt - rtts[idx-1])
+		}
+	}
+	result.RTT /= float64(len(rtts))
+	if len(rtts) > 1 {
+		result.Jitter /= float64(len(rtts) - 1)
+	}
+	return
 }
 
 func checkRTT(ip string) (rtt float64) {
@@ -76,21 +175,9 @@ func checkRTT(ip string) (rtt float64) {
 	reqTime := time.Now()
 	_, _, err := helper.DoGet(targetURL)
 	if err != nil {
-		fmt.Println(err.Error())
+		log.Println(logPrefix, "ping fail : ", err.Error())
 		return
 	}
 
 	return time.Now().Sub(reqTime).Seconds()
 }
-
-func selectMinRTT(ch chan float64, totalCount int) (minRTT float64) {
-	for i := 0; i < totalCount; i++ {
-		select {
-		case rtt := <-ch:
-			if (rtt != 0 && rtt < minRTT) || minRTT == 0 {
-				minRTT = rtt
-			}
-		}
-	}
-	return
-}
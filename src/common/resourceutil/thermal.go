@@ -0,0 +1,145 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resourceutil
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"common/errors"
+	resourceDB "db/bolt/resource"
+)
+
+var (
+	sysClassThermalPath     = "/sys/class/thermal"
+	sysClassPowerSupplyPath = "/sys/class/power_supply"
+)
+
+func init() {
+	RegisterCollector(collector{
+		name:      CollectorThermal,
+		resources: []string{ThermalTemp, PowerBattery, PowerAC},
+		interval:  time.Duration(defaultProcessingTime) * time.Second,
+		collect: func() error {
+			return collectAll(checkTemperature, checkPowerSupply)
+		},
+	})
+}
+
+// checkTemperature stores the hottest thermal zone in degrees Celsius, nothing is stored without sensors
+func checkTemperature() error {
+	zones, _ := filepath.Glob(filepath.Join(sysClassThermalPath, "thermal_zone*"))
+
+	var found bool
+	var temperature float64
+	for _, zone := range zones {
+		milliCelsius, err := readSysValue(filepath.Join(zone, "temp"))
+		if err != nil {
+			continue
+		}
+		if celsius := milliCelsius / 1000; !found || celsius > temperature {
+			temperature = celsius
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return setResource(ThermalTemp, temperature)
+}
+
+// checkPowerSupply stores the lowest battery capacity and whether an external power source is online
+func checkPowerSupply() error {
+	supplies, _ := filepath.Glob(filepath.Join(sysClassPowerSupplyPath, "*"))
+	if len(supplies) == 0 {
+		return nil
+	}
+
+	var hasBattery, hasAC bool
+	var battery, online float64
+	for _, supply := range supplies {
+		data, err := ioutil.ReadFile(filepath.Join(supply, "type"))
+		if err != nil {
+			continue
+		}
+
+		switch strings.TrimSpace(string(data)) {
+		case "Battery":
+			capacity, err := readSysValue(filepath.Join(supply, "capacity"))
+			if err != nil {
+				continue
+			}
+			if !hasBattery || capacity < battery {
+				battery = capacity
+			}
+			hasBattery = true
+		case "Mains", "USB":
+			value, err := readSysValue(filepath.Join(supply, "online"))
+			if err != nil {
+				continue
+			}
+			hasAC = true
+			if value > 0 {
+				online = 1
+			}
+		}
+	}
+
+	var err error
+	if hasBattery {
+		err = setResource(PowerBattery, battery)
+	}
+	if hasAC || hasBattery {
+		// NOTE : a device without any external power source is running on battery
+		if acErr := setResource(PowerAC, online); err == nil {
+			err = acErr
+		}
+	}
+	return err
+}
+
+func readSysValue(path string) (float64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, errors.InvalidParam{Message: path + " : " + err.Error()}
+	}
+	return value, nil
+}
+
+func setResource(name string, value float64) error {
+	info := resourceDB.ResourceInfo{}
+	info.Name = name
+	info.Value = value
+
+	err := resourceDBExecutor.Set(info)
+	if err != nil {
+		log.Println(logPrefix, "DB error : ", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resourceutil
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	netDB "db/bolt/network"
+)
+
+const (
+	throughputAPI             = "/api/v1/ping/throughput"
+	throughputProbeSize       = 1 << 20
+	defaultThroughputDuration = 60
+)
+
+func init() {
+	RegisterCollector(peerCollector{
+		collector: collector{
+			name:      CollectorThroughput,
+			resources: []string{NetPeerBandwidth},
+			interval:  time.Duration(defaultThroughputDuration) * time.Second,
+			collect:   checkThroughputs,
+		},
+		get: func(resourceName string, deviceID string) (float64, error) {
+			return getNetworkPeerBandwidth(deviceID)
+		},
+	})
+}
+
+// checkThroughputs measures the throughput to every peer one by one not to disturb each other
+func checkThroughputs() error {
+	netInfos, err := netDBExecutor.GetList()
+	if err != nil {
+		return err
+	}
+
+	for _, netInfo := range netInfos {
+		checkPeerBandwidth(netInfo)
+	}
+	return nil
+}
+
+func getNetworkPeerBandwidth(ID string) (out float64, err error) {
+	info, err := netDBExecutor.Get(ID)
+	if err != nil {
+		return
+	}
+	out = info.Bandwidth
+	return
+}
+
+func checkPeerBandwidth(info netDB.NetworkInfo) {
+	var bandwidth float64
+	for _, ip := range info.IPv4 {
+		if mbps := checkThroughput(ip); mbps > bandwidth {
+			bandwidth = mbps
+		}
+	}
+
+	if bandwidth == 0 {
+		return
+	}
+
+	err := netDBExecutor.Update(netDB.NetworkInfo{ID: info.ID, Bandwidth: bandwidth})
+	if err != nil {
+		log.Println(logPrefix, "DB error : ", err.Error())
+	}
+}
+
+// checkThroughput downloads a payload from the peer and gives Mbps, the time of ping is excluded as latency
+func checkThroughput(ip string) (mbps float64) {
+	latency := checkRTT(ip)
+	if latency == 0 {
+		return
+	}
+
+	targetURL := helper.MakeTargetURL(ip, internalPort, fmt.Sprintf("%s?size=%d", throughputAPI, throughputProbeSize))
+
+	reqTime := time.Now()
+	payload, _, err := helper.DoGet(targetURL)
+	if err != nil {
+		log.Println(logPrefix, "throughput probe fail : ", err.Error())
+		return
+	}
+
+	elapsed := time.Now().Sub(reqTime).Seconds()
+	if elapsed > latency {
+		elapsed -= latency
+	}
+	if elapsed <= 0 || len(payload) == 0 {
+		return
+	}
+
+	return float64(len(payload)) * 8 / elapsed / 1000 / 1000
+}
@@ -26,9 +26,21 @@ const (
 	// ConstServiceStatusStarted is service status is started
 	ConstServiceStatusStarted = "Started"
 
+	// ConstServiceStatusPulling is service status is pulling the image of container
+	ConstServiceStatusPulling = "Pulling"
+
 	// ConstServiceStatusFinished is service status is finished
 	ConstServiceStatusFinished = "Finished"
 
+	// ConstServiceStatusRejected is service status is rejected by the limits of the device
+	ConstServiceStatusRejected = "Rejected"
+
+	// ConstServiceStatusTimedOut is service status is terminated by the deadline of execution
+	ConstServiceStatusTimedOut = "TimedOut"
+
+	// ConstServiceStatusTerminated is service status is terminated by stopping orchestration
+	ConstServiceStatusTerminated = "Terminated"
+
 	// ConstServiceFound is service status is found
 	ConstServiceFound = "Found"
 
@@ -25,6 +25,8 @@ const (
 	servicePort = 42425
 	//max txt size of mdns service
 	maxTXTSize = 400
+	//separator of execution types stored in system db
+	execTypeSeparator = ","
 	//Interval Second for active discovery
 	discoveryInterval = 60 * 60
 	//IP Code for Active Discovery
@@ -18,12 +18,17 @@
 package discoverymgr
 
 import (
+	"bufio"
 	"io/ioutil"
 	"log"
 	"net"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	errors "common/errors"
+	eventbus "common/eventbus"
 	networkhelper "common/networkhelper"
 	wrapper "controller/discoverymgr/wrapper"
 
@@ -37,7 +42,7 @@ import (
 
 // Discovery is the interface implementedy by all discovery functions
 type Discovery interface {
-	StartDiscovery(UUIDpath string, platform string, executionType string) error
+	StartDiscovery(UUIDpath string, platform string, executionTypes []string, labelPath string) error
 	StopDiscovery()
 	DeleteDeviceWithIP(targetIP string)
 	DeleteDeviceWithID(ID string)
@@ -51,6 +56,7 @@ type discoveryImpl struct{}
 var (
 	discoveryIns discoveryImpl
 	networkIns   networkhelper.Network
+	eventBusIns  eventbus.EventBus
 )
 
 func init() {
@@ -58,6 +64,7 @@ func init() {
 	shutdownChan = make(chan struct{})
 
 	networkIns = networkhelper.GetInstance()
+	eventBusIns = eventbus.GetInstance()
 
 	sysQuery = systemdb.Query{}
 	confQuery = configurationdb.Query{}
@@ -70,8 +77,9 @@ func GetInstance() Discovery {
 	return discoveryIns
 }
 
-// InitDiscovery starts server for network registration and do orchestration discovery activity
-func (discoveryImpl) StartDiscovery(UUIDpath string, platform string, executionType string) (err error) {
+// InitDiscovery starts server for network registration and do orchestration discovery activity,
+// every execution type supported by the device is advertised and the first one is the primary
+func (discoveryImpl) StartDiscovery(UUIDpath string, platform string, executionTypes []string, labelPath string) (err error) {
 	networkIns.StartNetwork()
 
 	UUIDStr, err := setDeviceID(UUIDpath)
@@ -79,8 +87,13 @@ func (discoveryImpl) StartDiscovery(UUIDpath string, platform string, executionT
 		log.Print(logPrefix, "[StartDiscovery]", "UUID ", UUIDStr, " is Temporary")
 	}
 
+	labels, labelErr := getDeviceLabels(labelPath)
+	if labelErr != nil {
+		log.Println(logPrefix, "[StartDiscovery]", "No device labels : ", labelErr)
+	}
+
 	// NOTE : startServer blocks until server is registered
-	startServer(UUIDStr, platform, executionType)
+	startServer(UUIDStr, platform, executionTypes, labels)
 
 	go detectNetworkChgRoutine()
 
@@ -192,10 +205,11 @@ func (discoveryImpl) ResetServiceName() {
 	}
 
 	var serverTXT []string
-	serverTXT = append(serverTXT, confItem.ExecType)
 	serverTXT = append(serverTXT, confItem.Platform)
+	serverTXT = append(serverTXT, wrapper.MakeExecTypeTXTs(confItem.SupportedExecTypes())...)
+	serverTXT = append(serverTXT, makeLabelTXTs(confItem.Labels)...)
 
-	setNewServiceList(serverTXT)
+	setNewServiceList(fitLabelTXTs(serverTXT))
 }
 
 func detectNetworkChgRoutine() {
@@ -250,6 +264,36 @@ func setDeviceID(UUIDPath string) (UUIDstr string, err error) {
 	return UUIDstr, err
 }
 
+// getDeviceLabels reads operator-defined labels (key=value per line) from labelPath
+func getDeviceLabels(labelPath string) (labels map[string]string, err error) {
+	file, err := os.Open(labelPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	labels = make(map[string]string)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pair := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(pair[0])
+		if len(pair) != 2 || len(key) == 0 {
+			log.Println(logPrefix, "[getDeviceLabels]", "invalid label : ", line)
+			continue
+		}
+		labels[key] = strings.TrimSpace(pair[1])
+	}
+
+	log.Println(logPrefix, "Labels : ", labels)
+	return labels, scanner.Err()
+}
+
 func getDeviceID() (id string, err error) {
 	id, err = getSystemDB(systemdb.ID)
 	if err != nil {
@@ -268,22 +312,33 @@ func getPlatform() (platform string, err error) {
 	return
 }
 
-func getExecType() (execType string, err error) {
-	execType, err = getSystemDB(systemdb.ExecType)
+func getExecTypes() (execTypes []string, err error) {
+	execType, err := getSystemDB(systemdb.ExecType)
 	if err != nil {
 		log.Println(err.Error())
 	}
 
+	return splitExecTypes(execType), err
+}
+
+// splitExecTypes converts the execution types stored in system db
+func splitExecTypes(value string) (execTypes []string) {
+	for _, execType := range strings.Split(value, execTypeSeparator) {
+		if execType = strings.TrimSpace(execType); len(execType) != 0 {
+			execTypes = append(execTypes, execType)
+		}
+	}
 	return
 }
 
-func startServer(deviceUUID string, platform string, executionType string) {
+func startServer(deviceUUID string, platform string, executionTypes []string, labels map[string]string) {
 	deviceDetectionRoutine()
 
-	deviceID, hostName, Text := setDeviceArgument(deviceUUID, platform, executionType)
+	deviceID, hostName, Text := setDeviceArgument(deviceUUID, platform, executionTypes, labels)
+	Text = fitLabelTXTs(Text)
 
-	// @Note store system information(id, platform and execution type) to system db
-	setSystemDB(deviceID, platform, executionType)
+	// @Note store system information(id, platform and execution types) to system db
+	setSystemDB(deviceID, platform, executionTypes)
 
 	hostIPAddr, netIface := setNetwotkArgument()
 	var myDeviceEntity wrapper.Entity
@@ -310,15 +365,53 @@ func startServer(deviceUUID string, platform string, executionType string) {
 	return
 }
 
-func setDeviceArgument(deviceUUID string, platform string, executionType string) (deviceID string, hostName string, Text []string) {
+func setDeviceArgument(deviceUUID string, platform string, executionTypes []string, labels map[string]string) (deviceID string, hostName string, Text []string) {
 	deviceID = "edge-orchestration-" + deviceUUID
 	hostName = "edge-" + deviceUUID
 
 	Text = append(Text, platform)
-	Text = append(Text, executionType)
+	Text = append(Text, wrapper.MakeExecTypeTXTs(executionTypes)...)
+	Text = append(Text, makeLabelTXTs(labels)...)
+	return
+}
+
+func makeLabelTXTs(labels map[string]string) (labelTXTs []string) {
+	for key, value := range labels {
+		labelTXTs = append(labelTXTs, wrapper.MakeLabelTXT(key, value))
+	}
+	sort.Strings(labelTXTs)
 	return
 }
 
+// fitLabelTXTs drops the labels which do not fit in the size limit of mDNS TXT,
+// the other entries are kept
+func fitLabelTXTs(serverTXT []string) []string {
+	var TXTSize int
+	for _, str := range serverTXT {
+		if _, _, isLabel := wrapper.ParseLabelTXT(str); !isLabel {
+			TXTSize += len(str)
+		}
+	}
+
+	fitTXT := make([]string, 0, len(serverTXT))
+	dropped := make([]string, 0)
+	for _, str := range serverTXT {
+		if _, _, isLabel := wrapper.ParseLabelTXT(str); isLabel {
+			if TXTSize+len(str) > maxTXTSize {
+				dropped = append(dropped, str)
+				continue
+			}
+			TXTSize += len(str)
+		}
+		fitTXT = append(fitTXT, str)
+	}
+
+	if len(dropped) != 0 {
+		log.Println(logPrefix, "[fitLabelTXTs]", "labels are not advertised for the size of mDNS TXT : ", dropped)
+	}
+	return fitTXT
+}
+
 func setNetwotkArgument() (hostIPAddr []string, netIface []net.Interface) {
 	for {
 		hostIPAddr, _ = networkIns.GetIPs()
@@ -357,7 +450,8 @@ func deviceDetectionRoutine() {
 					continue
 				}
 
-				_, confInfo, netInfo, serviceInfo := convertToDBInfo(*data)
+				deviceID, confInfo, netInfo, serviceInfo := convertToDBInfo(*data)
+				eventType := getDeviceEventType(confInfo, netInfo, serviceInfo)
 
 				if len(netInfo.IPv4) != 0 {
 					setNetworkDB(netInfo)
@@ -365,6 +459,10 @@ func deviceDetectionRoutine() {
 				// @Note Is it need to call Update API?
 				setConfigurationDB(confInfo)
 				setServiceDB(serviceInfo)
+
+				if len(eventType) != 0 {
+					publishDeviceEvent(eventType, deviceID, data.OrchestrationInfo)
+				}
 			}
 		}
 	}()
@@ -389,9 +487,11 @@ func serviceNameChecker(serviceName string) error {
 	}
 
 	platform, _ := getPlatform()
-	executionType, _ := getExecType()
+	executionTypes, _ := getExecTypes()
 
-	if serviceName == platform || serviceName == executionType {
+	if serviceName == platform || (len(executionTypes) != 0 && serviceName == executionTypes[0]) ||
+		strings.HasPrefix(serviceName, wrapper.ExecTypeTXTPrefix) ||
+		strings.HasPrefix(serviceName, wrapper.LabelTXTPrefix) {
 		return errors.InvalidParam{Message: "cannot change fixed field"}
 	}
 
@@ -438,7 +538,16 @@ func getIndexToDelete(serverTXT []string, serviceName string) (idxToDel int, err
 
 func setNewServiceList(serverTXT []string) {
 	// if len(serverTXT) > 2 {
-	newServiceList := serverTXT[2:]
+	newServiceList := make([]string, 0)
+	for _, txt := range serverTXT[2:] {
+		if _, _, isLabel := wrapper.ParseLabelTXT(txt); isLabel {
+			continue
+		}
+		if _, isExecType := wrapper.ParseExecTypeTXT(txt); isExecType {
+			continue
+		}
+		newServiceList = append(newServiceList, txt)
+	}
 
 	deviceID, err := getDeviceID()
 	if err != nil {
@@ -484,7 +593,9 @@ func convertToDBInfo(entity wrapper.Entity) (string, configurationdb.Configurati
 
 	confInfo.ID = entity.DeviceID
 	confInfo.ExecType = data.ExecutionType
+	confInfo.ExecTypes = data.ExecutionTypes
 	confInfo.Platform = data.Platform
+	confInfo.Labels = data.Labels
 
 	netInfo.ID = entity.DeviceID
 	netInfo.IPv4 = data.IPv4
@@ -495,7 +606,7 @@ func convertToDBInfo(entity wrapper.Entity) (string, configurationdb.Configurati
 	return entity.DeviceID, confInfo, netInfo, serviceInfo
 }
 
-func setSystemDB(id string, platform string, execType string) {
+func setSystemDB(id string, platform string, execTypes []string) {
 	sysInfo := systemdb.SystemInfo{Name: systemdb.ID, Value: id}
 	err := sysQuery.Set(sysInfo)
 	if err != nil {
@@ -508,7 +619,7 @@ func setSystemDB(id string, platform string, execType string) {
 		log.Println(logPrefix, err.Error())
 	}
 
-	sysInfo = systemdb.SystemInfo{Name: systemdb.ExecType, Value: execType}
+	sysInfo = systemdb.SystemInfo{Name: systemdb.ExecType, Value: strings.Join(execTypes, execTypeSeparator)}
 	err = sysQuery.Set(sysInfo)
 	if err != nil {
 		log.Println(logPrefix, err.Error())
@@ -549,7 +660,10 @@ func getSystemDB(name string) (string, error) {
 // DeleteDevice deletes device info by key
 func deleteDevice(deviceID string) {
 	log.Println(logPrefix, "[deleteDevice]", deviceID)
-	err := confQuery.Delete(deviceID)
+	_, err := confQuery.Get(deviceID)
+	existed := (err == nil)
+
+	err = confQuery.Delete(deviceID)
 	if err != nil {
 		log.Println(err.Error())
 	}
@@ -563,6 +677,82 @@ func deleteDevice(deviceID string) {
 	if err != nil {
 		log.Println(err.Error())
 	}
+
+	if existed {
+		publishDeviceEvent(DeviceLeft, deviceID, wrapper.OrchestrationInformation{})
+	}
+}
+
+// getDeviceEventType compares new device info with stored one
+// and returns empty string if nothing is changed
+func getDeviceEventType(confInfo configurationdb.Configuration, netInfo networkdb.NetworkInfo, serviceInfo servicedb.ServiceInfo) string {
+	prevConf, err := confQuery.Get(confInfo.ID)
+	if err != nil {
+		return DeviceJoined
+	}
+
+	if prevConf.Platform != confInfo.Platform ||
+		!equalStrings(prevConf.SupportedExecTypes(), confInfo.SupportedExecTypes()) ||
+		!equalLabels(prevConf.Labels, confInfo.Labels) {
+		return DeviceUpdated
+	}
+
+	if len(netInfo.IPv4) != 0 {
+		prevNet, err := netQuery.Get(netInfo.ID)
+		if err != nil || !equalStrings(prevNet.IPv4, netInfo.IPv4) {
+			return DeviceUpdated
+		}
+	}
+
+	prevService, err := serviceQuery.Get(serviceInfo.ID)
+	if err != nil || !equalStrings(prevService.Services, serviceInfo.Services) {
+		return DeviceUpdated
+	}
+
+	return ""
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// publishDeviceEvent notifies membership change of device to eventbus subscribers
+func publishDeviceEvent(eventType string, deviceID string, data wrapper.OrchestrationInformation) {
+	log.Println(logPrefix, "[publishDeviceEvent]", eventType, deviceID)
+
+	event := DeviceEvent{
+		Type:     eventType,
+		DeviceID: deviceID,
+		Info: OrchestrationInformation{
+			Platform:       data.Platform,
+			ExecutionType:  data.ExecutionType,
+			ExecutionTypes: data.ExecutionTypes,
+			IPv4:           data.IPv4,
+			ServiceList:    data.ServiceList,
+			Labels:         data.Labels,
+		},
+	}
+	eventBusIns.Publish(DeviceEventTopic, event)
 }
 
 // activeDiscovery calls advertise function of Zeroconf
@@ -18,9 +18,12 @@
 package discoverymgr
 
 import (
+	"io/ioutil"
 	"log"
 	"net"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,6 +43,7 @@ var (
 	mockWrapper          *wrappermocks.MockZeroconfInterface
 	mockNetwork          *networkmocks.MockNetwork
 	defaultUUIDPath      = "/etc/orchestration_deviceID.txt"
+	defaultLabelPath     = "/etc/orchestration_labels.txt"
 	defaultPlatform      = "LINUX"
 	defaultExecutionType = "Executable"
 	defaultService       = "ls"
@@ -90,7 +94,7 @@ func addDevice(Another bool) {
 	deviceID, confInfo, netInfo, serviceInfo := convertToDBInfo(defaultMyDeviceEntity)
 
 	log.Println(logPrefix, "[addDevice]", deviceID)
-	setSystemDB(deviceID, defaultPlatform, defaultExecutionType)
+	setSystemDB(deviceID, defaultPlatform, []string{defaultExecutionType})
 	setConfigurationDB(confInfo)
 	setNetworkDB(netInfo)
 	setServiceDB(serviceInfo)
@@ -222,7 +226,7 @@ func TestStartDiscovery(t *testing.T) {
 		//let the test start
 		discoveryInstance := GetInstance()
 		discoveryInstance.StartDiscovery(defaultUUIDPath,
-			defaultPlatform, defaultExecutionType)
+			defaultPlatform, []string{defaultExecutionType}, defaultLabelPath)
 
 		err := serverPresenceChecker()
 		if err != nil {
@@ -485,6 +489,14 @@ func TestServiceNameChecker(t *testing.T) {
 			if err == nil {
 				t.Error()
 			}
+			err = serviceNameChecker(defaultExecutionType)
+			if err == nil {
+				t.Error()
+			}
+			err = serviceNameChecker(wrapper.ExecTypeTXTPrefix + "container")
+			if err == nil {
+				t.Error()
+			}
 		})
 	})
 	closeTest()
@@ -597,3 +609,158 @@ func TestGetDeviceID(t *testing.T) {
 		}
 	})
 }
+
+func TestGetDeviceLabels(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		labelFile, err := ioutil.TempFile("", "labels")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		defer os.Remove(labelFile.Name())
+
+		labelFile.WriteString("# device labels\nroom = living\ncamera=true\ninvalid\n")
+		labelFile.Close()
+
+		labels, err := getDeviceLabels(labelFile.Name())
+		if err != nil {
+			t.Error(err.Error())
+		}
+
+		expected := map[string]string{"room": "living", "camera": "true"}
+		if reflect.DeepEqual(labels, expected) != true {
+			t.Error("unexpected labels : ", labels)
+		}
+	})
+	t.Run("Fail", func(t *testing.T) {
+		_, err := getDeviceLabels("/x/y/z/NoFileIsThisName")
+		if err == nil {
+			t.Error()
+		}
+	})
+}
+
+func TestSetNewServiceListWithLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createMockIns(ctrl)
+	addDevice(false)
+
+	serverTXT := []string{defaultPlatform, defaultExecutionType, wrapper.ExecTypeTXTPrefix + "container",
+		wrapper.MakeLabelTXT("room", "living"), defaultService}
+
+	mockWrapper.EXPECT().SetText(gomock.Eq(serverTXT)).Return()
+	setNewServiceList(serverTXT)
+
+	serviceInfo, err := serviceQuery.Get(defaultMyDeviceID)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if reflect.DeepEqual(serviceInfo.Services, defaultServiceList) != true {
+		t.Error("unexpected service list : ", serviceInfo.Services)
+	}
+
+	closeTest()
+}
+
+func TestSetDeviceArgument(t *testing.T) {
+	_, _, txt := setDeviceArgument("uuid", defaultPlatform, []string{defaultExecutionType, "container"}, map[string]string{"room": "living"})
+
+	// @Note : the second entry has the first execution type alone for devices of older versions
+	expected := []string{defaultPlatform, defaultExecutionType, wrapper.ExecTypeTXTPrefix + "container", wrapper.MakeLabelTXT("room", "living")}
+	if reflect.DeepEqual(txt, expected) != true {
+		t.Error("unexpected text : ", txt)
+	}
+}
+
+func TestFitLabelTXTs(t *testing.T) {
+	t.Run("Fit", func(t *testing.T) {
+		serverTXT := []string{defaultPlatform, defaultExecutionType, wrapper.MakeLabelTXT("room", "living")}
+		if txt := fitLabelTXTs(serverTXT); reflect.DeepEqual(txt, serverTXT) != true {
+			t.Error("unexpected text : ", txt)
+		}
+	})
+	t.Run("Oversize", func(t *testing.T) {
+		long := strings.Repeat("v", maxTXTSize/2)
+		labels := map[string]string{"a": long, "b": long, "c": "short"}
+		_, _, serverTXT := setDeviceArgument("uuid", defaultPlatform, []string{defaultExecutionType}, labels)
+
+		expected := []string{defaultPlatform, defaultExecutionType, wrapper.MakeLabelTXT("a", long), wrapper.MakeLabelTXT("c", "short")}
+		txt := fitLabelTXTs(serverTXT)
+		if reflect.DeepEqual(txt, expected) != true {
+			t.Error("unexpected text : ", txt)
+		}
+		if err := mdnsTXTSizeChecker(txt); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+	})
+}
+
+func TestGetDeviceEventType(t *testing.T) {
+	addDevice(false)
+
+	t.Run("Join", func(t *testing.T) {
+		_, confInfo, netInfo, serviceInfo := convertToDBInfo(anotherEntity)
+		if eventType := getDeviceEventType(confInfo, netInfo, serviceInfo); eventType != DeviceJoined {
+			t.Error("unexpected event type : ", eventType)
+		}
+	})
+	t.Run("NotChanged", func(t *testing.T) {
+		_, confInfo, netInfo, serviceInfo := convertToDBInfo(defaultMyDeviceEntity)
+		if eventType := getDeviceEventType(confInfo, netInfo, serviceInfo); len(eventType) != 0 {
+			t.Error("unexpected event type : ", eventType)
+		}
+	})
+	t.Run("Update", func(t *testing.T) {
+		tmpEntity := defaultMyDeviceEntity
+		tmpEntity.OrchestrationInfo.ServiceList = anotherServiceList
+		_, confInfo, netInfo, serviceInfo := convertToDBInfo(tmpEntity)
+		if eventType := getDeviceEventType(confInfo, netInfo, serviceInfo); eventType != DeviceUpdated {
+			t.Error("unexpected event type : ", eventType)
+		}
+	})
+	t.Run("ExecTypesUpdate", func(t *testing.T) {
+		tmpEntity := defaultMyDeviceEntity
+		tmpEntity.OrchestrationInfo.ExecutionTypes = []string{defaultExecutionType, "container"}
+		_, confInfo, netInfo, serviceInfo := convertToDBInfo(tmpEntity)
+		if eventType := getDeviceEventType(confInfo, netInfo, serviceInfo); eventType != DeviceUpdated {
+			t.Error("unexpected event type : ", eventType)
+		}
+	})
+
+	closeTest()
+}
+
+func TestPublishDeviceLeftEvent(t *testing.T) {
+	addDevice(true)
+
+	sub := eventBusIns.Subscribe(DeviceEventTopic)
+	defer eventBusIns.Unsubscribe(sub)
+
+	deleteDevice(anotherDeviceID)
+
+	select {
+	case data := <-sub.C:
+		event, ok := data.(DeviceEvent)
+		if !ok {
+			t.Fatal("unexpected event : ", data)
+		}
+		if event.Type != DeviceLeft || event.DeviceID != anotherDeviceID {
+			t.Error("unexpected event : ", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("leave event is not published")
+	}
+
+	t.Run("NotExisted", func(t *testing.T) {
+		deleteDevice(anotherDeviceID)
+		select {
+		case data := <-sub.C:
+			t.Error("unexpected event : ", data)
+		default:
+		}
+	})
+
+	closeTest()
+}
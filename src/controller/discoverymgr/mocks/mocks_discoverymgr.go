@@ -50,17 +50,17 @@ func (m *MockDiscovery) EXPECT() *MockDiscoveryMockRecorder {
 }
 
 // StartDiscovery mocks base method
-func (m *MockDiscovery) StartDiscovery(UUIDpath, platform, executionType string) error {
+func (m *MockDiscovery) StartDiscovery(UUIDpath, platform string, executionTypes []string, labelPath string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StartDiscovery", UUIDpath, platform, executionType)
+	ret := m.ctrl.Call(m, "StartDiscovery", UUIDpath, platform, executionTypes, labelPath)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StartDiscovery indicates an expected call of StartDiscovery
-func (mr *MockDiscoveryMockRecorder) StartDiscovery(UUIDpath, platform, executionType interface{}) *gomock.Call {
+func (mr *MockDiscoveryMockRecorder) StartDiscovery(UUIDpath, platform, executionTypes, labelPath interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDiscovery", reflect.TypeOf((*MockDiscovery)(nil).StartDiscovery), UUIDpath, platform, executionType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDiscovery", reflect.TypeOf((*MockDiscovery)(nil).StartDiscovery), UUIDpath, platform, executionTypes, labelPath)
 }
 
 // StopDiscovery mocks base method
@@ -29,15 +29,36 @@ import (
 
 const logPrefix = "[discoverymgr]"
 
+const (
+	// DeviceEventTopic is the eventbus topic of device membership events
+	DeviceEventTopic = "discoverymgr/device"
+
+	// DeviceJoined is the event type published when a new device is found
+	DeviceJoined = "join"
+	// DeviceUpdated is the event type published when information of a known device is changed
+	DeviceUpdated = "update"
+	// DeviceLeft is the event type published when a device is removed
+	DeviceLeft = "leave"
+)
+
 // OrchestrationInformation is the struct to handle orchestration
 type OrchestrationInformation struct {
-	Platform      string `json:"Platform"`
-	ExecutionType string `json:"ExecutionType"`
+	Platform       string   `json:"Platform"`
+	ExecutionType  string   `json:"ExecutionType"`
+	ExecutionTypes []string `json:"ExecutionTypes,omitempty"`
 
 	//interface-ip 형태의 구조체 리스트로.
 	IPv4 []string `json:"IPv4"`
 	// IPv6     []string   `json:"IPv6"`
-	ServiceList []string `json:"ServiceList"`
+	ServiceList []string          `json:"ServiceList"`
+	Labels      map[string]string `json:"Labels"`
+}
+
+// DeviceEvent is the struct of device membership event published to eventbus
+type DeviceEvent struct {
+	Type     string                   `json:"Type"`
+	DeviceID string                   `json:"DeviceID"`
+	Info     OrchestrationInformation `json:"Info"`
 }
 
 // ExportDeviceMap gives device info map for discoverymgr user
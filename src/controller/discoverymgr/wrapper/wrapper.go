@@ -21,11 +21,21 @@ package wrapper
 import (
 	"log"
 	"net"
+	"strings"
 
 	"github.com/grandcat/zeroconf"
 )
 
-const logPrefix = "[discovery][wrapper]"
+const (
+	logPrefix = "[discovery][wrapper]"
+
+	// LabelTXTPrefix is the prefix of text field entries which carry device labels
+	LabelTXTPrefix = "label:"
+
+	// ExecTypeTXTPrefix is the prefix of text field entries which carry the execution types after the first one,
+	// the first execution type is the second entry alone as devices of older versions expect
+	ExecTypeTXTPrefix = "exectype:"
+)
 
 // ZeroconfInterface is the interface implemented by wrapped functions using zeroconf
 // ToDo : How to deal w/ data conver function?
@@ -53,7 +63,10 @@ type OrchestrationInformation struct {
 	IPv4          []string
 	Platform      string
 	ExecutionType string
-	ServiceList   []string
+	// ExecutionTypes has every execution type supported by the device, the first one is ExecutionType
+	ExecutionTypes []string
+	ServiceList    []string
+	Labels         map[string]string
 }
 
 // ZeroconfImpl struct
@@ -170,9 +183,66 @@ func convertServiceEntrytoDB(data *zeroconf.ServiceEntry) (newDevice Orchestrati
 	} else {
 		newDevice.Platform = data.Text[0]
 		newDevice.ExecutionType = data.Text[1]
-		if len(data.Text) > 2 {
-			newDevice.ServiceList = append(newDevice.ServiceList, data.Text[2:]...)
+		if len(newDevice.ExecutionType) != 0 {
+			newDevice.ExecutionTypes = append(newDevice.ExecutionTypes, newDevice.ExecutionType)
+		}
+		for _, txt := range data.Text[2:] {
+			if execType, ok := ParseExecTypeTXT(txt); ok {
+				newDevice.ExecutionTypes = append(newDevice.ExecutionTypes, execType)
+				continue
+			}
+			if key, value, ok := ParseLabelTXT(txt); ok {
+				if newDevice.Labels == nil {
+					newDevice.Labels = make(map[string]string)
+				}
+				newDevice.Labels[key] = value
+				continue
+			}
+			newDevice.ServiceList = append(newDevice.ServiceList, txt)
 		}
 	}
 	return
 }
+
+// MakeExecTypeTXTs converts the execution types of a device to text field entries,
+// the first entry is the first execution type and others are prefixed with ExecTypeTXTPrefix
+func MakeExecTypeTXTs(execTypes []string) []string {
+	if len(execTypes) == 0 {
+		return []string{""}
+	}
+
+	txts := []string{execTypes[0]}
+	for _, execType := range execTypes[1:] {
+		txts = append(txts, ExecTypeTXTPrefix+execType)
+	}
+	return txts
+}
+
+// ParseExecTypeTXT converts text field entry to an execution type after the first one of a device
+func ParseExecTypeTXT(txt string) (execType string, ok bool) {
+	if !strings.HasPrefix(txt, ExecTypeTXTPrefix) {
+		return
+	}
+
+	execType = strings.TrimPrefix(txt, ExecTypeTXTPrefix)
+	return execType, len(execType) != 0
+}
+
+// MakeLabelTXT converts a device label to text field entry
+func MakeLabelTXT(key string, value string) string {
+	return LabelTXTPrefix + key + "=" + value
+}
+
+// ParseLabelTXT converts text field entry to a device label
+func ParseLabelTXT(txt string) (key string, value string, ok bool) {
+	if !strings.HasPrefix(txt, LabelTXTPrefix) {
+		return
+	}
+
+	pair := strings.SplitN(strings.TrimPrefix(txt, LabelTXTPrefix), "=", 2)
+	if len(pair) != 2 || len(pair[0]) == 0 {
+		return
+	}
+
+	return pair[0], pair[1], true
+}
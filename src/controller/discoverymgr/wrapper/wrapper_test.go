@@ -0,0 +1,72 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package wrapper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grandcat/zeroconf"
+)
+
+func TestMakeExecTypeTXTs(t *testing.T) {
+	t.Run("Single", func(t *testing.T) {
+		if txts := MakeExecTypeTXTs([]string{"native"}); reflect.DeepEqual(txts, []string{"native"}) != true {
+			t.Error("unexpected text : ", txts)
+		}
+	})
+	t.Run("Several", func(t *testing.T) {
+		expected := []string{"container", ExecTypeTXTPrefix + "native"}
+		if txts := MakeExecTypeTXTs([]string{"container", "native"}); reflect.DeepEqual(txts, expected) != true {
+			t.Error("unexpected text : ", txts)
+		}
+	})
+}
+
+func TestConvertServiceEntrytoDB(t *testing.T) {
+	t.Run("OldFormat", func(t *testing.T) {
+		entry := zeroconf.NewServiceEntry("edge-orchestration-id", "_orchestration._tcp", "local.")
+		entry.Text = []string{"linux", "container", "ls"}
+
+		info := convertServiceEntrytoDB(entry)
+		if info.Platform != "linux" || info.ExecutionType != "container" {
+			t.Error("unexpected information : ", info)
+		}
+		if reflect.DeepEqual(info.ExecutionTypes, []string{"container"}) != true {
+			t.Error("unexpected execution types : ", info.ExecutionTypes)
+		}
+		if reflect.DeepEqual(info.ServiceList, []string{"ls"}) != true {
+			t.Error("unexpected service list : ", info.ServiceList)
+		}
+	})
+	t.Run("SeveralExecTypes", func(t *testing.T) {
+		entry := zeroconf.NewServiceEntry("edge-orchestration-id", "_orchestration._tcp", "local.")
+		entry.Text = []string{"linux", "container", ExecTypeTXTPrefix + "native", MakeLabelTXT("room", "living"), "ls"}
+
+		info := convertServiceEntrytoDB(entry)
+		if info.ExecutionType != "container" {
+			t.Error("unexpected execution type : ", info.ExecutionType)
+		}
+		if reflect.DeepEqual(info.ExecutionTypes, []string{"container", "native"}) != true {
+			t.Error("unexpected execution types : ", info.ExecutionTypes)
+		}
+		if info.Labels["room"] != "living" || reflect.DeepEqual(info.ServiceList, []string{"ls"}) != true {
+			t.Error("unexpected information : ", info)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package schedulermgr
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultScoreTolerance is the ratio to the highest score to regard scores as near-equal
+const defaultScoreTolerance = 0.05
+
+// sortByScore orders candidates from the highest score keeping the order of equal ones
+func sortByScore(candidates []Candidate) []Candidate {
+	sorted := append([]Candidate{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+
+	return sorted
+}
+
+type bestScoreScheduler struct{}
+
+func (bestScoreScheduler) Name() string {
+	return BestScore
+}
+
+func (bestScoreScheduler) Schedule(serviceName string, candidates []Candidate) []Candidate {
+	return sortByScore(candidates)
+}
+
+func (b bestScoreScheduler) Preview(serviceName string, candidates []Candidate) []Candidate {
+	return b.Schedule(serviceName, candidates)
+}
+
+type roundRobinScheduler struct {
+	tolerance float64
+
+	mtx  sync.Mutex
+	next map[string]int
+}
+
+func newRoundRobinScheduler(tolerance float64) *roundRobinScheduler {
+	return &roundRobinScheduler{tolerance: tolerance, next: make(map[string]int)}
+}
+
+func (*roundRobinScheduler) Name() string {
+	return RoundRobin
+}
+
+// Schedule rotates the candidates whose scores are within tolerance of the highest score for each service
+func (r *roundRobinScheduler) Schedule(serviceName string, candidates []Candidate) []Candidate {
+	return r.rotate(serviceName, candidates, true)
+}
+
+// Preview returns the rotation of the next request without advancing it
+func (r *roundRobinScheduler) Preview(serviceName string, candidates []Candidate) []Candidate {
+	return r.rotate(serviceName, candidates, false)
+}
+
+func (r *roundRobinScheduler) rotate(serviceName string, candidates []Candidate, advance bool) []Candidate {
+	sorted := sortByScore(candidates)
+	if len(sorted) < 2 || sorted[0].Score <= 0 {
+		return sorted
+	}
+
+	threshold := sorted[0].Score * (1 - r.tolerance)
+	count := 0
+	for _, candidate := range sorted {
+		if candidate.Score < threshold {
+			break
+		}
+		count++
+	}
+
+	// NOTE : keep the order of near-equal candidates stable between requests
+	nearEqual := sorted[:count]
+	sort.SliceStable(nearEqual, func(i, j int) bool {
+		return nearEqual[i].ID < nearEqual[j].ID
+	})
+
+	r.mtx.Lock()
+	offset := r.next[serviceName] % count
+	if advance {
+		r.next[serviceName] = offset + 1
+	}
+	r.mtx.Unlock()
+
+	ordered := make([]Candidate, 0, len(sorted))
+	ordered = append(ordered, nearEqual[offset:]...)
+	ordered = append(ordered, nearEqual[:offset]...)
+	ordered = append(ordered, sorted[count:]...)
+
+	return ordered
+}
+
+type preferLocalScheduler struct{}
+
+func (preferLocalScheduler) Name() string {
+	return PreferLocal
+}
+
+// Schedule puts local device first if it got valid score
+func (preferLocalScheduler) Schedule(serviceName string, candidates []Candidate) []Candidate {
+	sorted := sortByScore(candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return isAvailableLocal(sorted[i]) && !isAvailableLocal(sorted[j])
+	})
+
+	return sorted
+}
+
+func (p preferLocalScheduler) Preview(serviceName string, candidates []Candidate) []Candidate {
+	return p.Schedule(serviceName, candidates)
+}
+
+func isAvailableLocal(candidate Candidate) bool {
+	return candidate.IsLocal && candidate.Score > 0
+}
+
+type leastRunningScheduler struct{}
+
+func (leastRunningScheduler) Name() string {
+	return LeastRunning
+}
+
+// Schedule orders candidates by the number of running services and then by score
+func (leastRunningScheduler) Schedule(serviceName string, candidates []Candidate) []Candidate {
+	sorted := sortByScore(candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if (sorted[i].Score > 0) != (sorted[j].Score > 0) {
+			return sorted[i].Score > 0
+		}
+		return sorted[i].History.Running < sorted[j].History.Running
+	})
+
+	return sorted
+}
+
+func (l leastRunningScheduler) Preview(serviceName string, candidates []Candidate) []Candidate {
+	return l.Schedule(serviceName, candidates)
+}
+
+type randomWeightedScheduler struct {
+	mtx    sync.Mutex
+	random func() float64
+}
+
+func newRandomWeightedScheduler() *randomWeightedScheduler {
+	source := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return &randomWeightedScheduler{random: source.Float64}
+}
+
+func (*randomWeightedScheduler) Name() string {
+	return RandomWeighted
+}
+
+// Schedule picks candidates one by one with the probability proportional to score
+func (r *randomWeightedScheduler) Schedule(serviceName string, candidates []Candidate) []Candidate {
+	remains := sortByScore(candidates)
+	ordered := make([]Candidate, 0, len(remains))
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for len(remains) > 0 {
+		total := 0.0
+		for _, candidate := range remains {
+			if candidate.Score > 0 {
+				total += candidate.Score
+			}
+		}
+		if total <= 0 {
+			break
+		}
+
+		picked := -1
+		point := r.random() * total
+		for idx, candidate := range remains {
+			if candidate.Score <= 0 {
+				continue
+			}
+			picked = idx
+			if point -= candidate.Score; point < 0 {
+				break
+			}
+		}
+
+		ordered = append(ordered, remains[picked])
+		remains = append(remains[:picked], remains[picked+1:]...)
+	}
+
+	return append(ordered, remains...)
+}
+
+// Preview returns the candidates ordered by score which is the most probable order of Schedule,
+// the random source is not consumed
+func (r *randomWeightedScheduler) Preview(serviceName string, candidates []Candidate) []Candidate {
+	return sortByScore(candidates)
+}
@@ -0,0 +1,128 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package schedulermgr
+
+import (
+	"testing"
+)
+
+func getIDs(candidates []Candidate) (ids []string) {
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ID)
+	}
+	return
+}
+
+func checkOrder(t *testing.T, candidates []Candidate, expected ...string) {
+	t.Helper()
+
+	ids := getIDs(candidates)
+	if len(ids) != len(expected) {
+		t.Fatal("unexpected order : ", ids)
+	}
+	for idx := range ids {
+		if ids[idx] != expected[idx] {
+			t.Fatal("unexpected order : ", ids)
+		}
+	}
+}
+
+func TestBestScore(t *testing.T) {
+	candidates := []Candidate{
+		{ID: "a", Score: 10},
+		{ID: "b", Score: 30},
+		{ID: "c", Score: 20},
+	}
+
+	checkOrder(t, bestScoreScheduler{}.Schedule("test", candidates), "b", "c", "a")
+	checkOrder(t, candidates, "a", "b", "c")
+}
+
+func TestRoundRobin(t *testing.T) {
+	scheduler := newRoundRobinScheduler(defaultScoreTolerance)
+	candidates := []Candidate{
+		{ID: "a", Score: 99},
+		{ID: "b", Score: 100},
+		{ID: "c", Score: 50},
+	}
+
+	checkOrder(t, scheduler.Schedule("test", candidates), "a", "b", "c")
+	checkOrder(t, scheduler.Schedule("test", candidates), "b", "a", "c")
+	checkOrder(t, scheduler.Schedule("test", candidates), "a", "b", "c")
+
+	t.Run("OtherService", func(t *testing.T) {
+		checkOrder(t, scheduler.Schedule("other", candidates), "a", "b", "c")
+	})
+	t.Run("Preview", func(t *testing.T) {
+		checkOrder(t, scheduler.Preview("test", candidates), "b", "a", "c")
+		checkOrder(t, scheduler.Preview("test", candidates), "b", "a", "c")
+		checkOrder(t, scheduler.Schedule("test", candidates), "b", "a", "c")
+		checkOrder(t, scheduler.Preview("test", candidates), "a", "b", "c")
+	})
+}
+
+func TestPreferLocal(t *testing.T) {
+	candidates := []Candidate{
+		{ID: "a", Score: 100},
+		{ID: "local", Score: 10, IsLocal: true},
+		{ID: "b", Score: 50},
+	}
+
+	checkOrder(t, preferLocalScheduler{}.Schedule("test", candidates), "local", "a", "b")
+
+	t.Run("InvalidLocalScore", func(t *testing.T) {
+		candidates[1].Score = 0
+		checkOrder(t, preferLocalScheduler{}.Schedule("test", candidates), "a", "b", "local")
+	})
+}
+
+func TestLeastRunning(t *testing.T) {
+	candidates := []Candidate{
+		{ID: "a", Score: 100, History: History{Running: 3}},
+		{ID: "b", Score: 50, History: History{Running: 1}},
+		{ID: "c", Score: 70, History: History{Running: 1}},
+		{ID: "d", Score: 0},
+	}
+
+	checkOrder(t, leastRunningScheduler{}.Schedule("test", candidates), "c", "b", "a", "d")
+}
+
+func TestRandomWeighted(t *testing.T) {
+	scheduler := newRandomWeightedScheduler()
+	candidates := []Candidate{
+		{ID: "a", Score: 10},
+		{ID: "b", Score: 30},
+		{ID: "c", Score: 0},
+	}
+
+	t.Run("Lowest", func(t *testing.T) {
+		scheduler.random = func() float64 { return 0.0 }
+		checkOrder(t, scheduler.Schedule("test", candidates), "b", "a", "c")
+	})
+	t.Run("Highest", func(t *testing.T) {
+		scheduler.random = func() float64 { return 0.99 }
+		checkOrder(t, scheduler.Schedule("test", candidates), "a", "b", "c")
+	})
+	t.Run("Preview", func(t *testing.T) {
+		scheduler.random = func() float64 {
+			t.Error("random source is consumed")
+			return 0.0
+		}
+		checkOrder(t, scheduler.Preview("test", candidates), "b", "a", "c")
+	})
+}
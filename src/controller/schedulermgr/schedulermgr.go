@@ -0,0 +1,190 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package schedulermgr provides scheduling policies to decide the order of devices to execute service application
+package schedulermgr
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const logPrefix = "[schedulermgr]"
+
+const (
+	// BestScore is the policy to choose the device of highest score
+	BestScore = "best-score"
+	// RoundRobin is the policy to rotate devices whose scores are near-equal to the highest score
+	RoundRobin = "round-robin"
+	// PreferLocal is the policy to choose local device if it is a candidate
+	PreferLocal = "prefer-local"
+	// LeastRunning is the policy to choose the device running the fewest services
+	LeastRunning = "least-running-services"
+	// RandomWeighted is the policy to choose the device randomly weighted by score
+	RandomWeighted = "random-weighted"
+)
+
+// Scheduler is the interface implemented by scheduling policies
+type Scheduler interface {
+	Name() string
+	Schedule(serviceName string, candidates []Candidate) []Candidate
+	// Preview returns the order Schedule would return without changing the state of the policy
+	Preview(serviceName string, candidates []Candidate) []Candidate
+}
+
+// Candidate is the device which can execute the service application
+type Candidate struct {
+	ID       string
+	Endpoint string
+	ExecType string
+	Score    float64
+	Labels   map[string]string
+	IsLocal  bool
+	History  History
+}
+
+// History is the placement history of a device
+type History struct {
+	Running    int
+	Placed     int
+	LastPlaced time.Time
+}
+
+// SchedulerMgr is the interface to select scheduling policy and keep placement history
+type SchedulerMgr interface {
+	Register(s Scheduler) error
+	SetDefault(name string) error
+	GetScheduler(name string) (Scheduler, error)
+
+	RecordPlacement(deviceID string)
+	RecordCompletion(deviceID string)
+	GetHistory(deviceID string) History
+}
+
+// SchedulerMgrImpl structure
+type SchedulerMgrImpl struct {
+	mtx         sync.RWMutex
+	schedulers  map[string]Scheduler
+	defaultName string
+
+	historyMtx sync.Mutex
+	histories  map[string]History
+}
+
+var schedulerMgr *SchedulerMgrImpl
+
+func init() {
+	schedulerMgr = &SchedulerMgrImpl{
+		schedulers:  make(map[string]Scheduler),
+		defaultName: BestScore,
+		histories:   make(map[string]History),
+	}
+
+	schedulerMgr.Register(bestScoreScheduler{})
+	schedulerMgr.Register(newRoundRobinScheduler(defaultScoreTolerance))
+	schedulerMgr.Register(preferLocalScheduler{})
+	schedulerMgr.Register(leastRunningScheduler{})
+	schedulerMgr.Register(newRandomWeightedScheduler())
+}
+
+// GetInstance returns the singleton SchedulerMgrImpl instance
+func GetInstance() *SchedulerMgrImpl {
+	return schedulerMgr
+}
+
+// Register adds new scheduling policy
+func (s *SchedulerMgrImpl) Register(scheduler Scheduler) error {
+	if scheduler == nil || len(scheduler.Name()) == 0 {
+		return errors.New("invalid scheduler")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exist := s.schedulers[scheduler.Name()]; exist {
+		return errors.New("already registered scheduler : " + scheduler.Name())
+	}
+	s.schedulers[scheduler.Name()] = scheduler
+
+	return nil
+}
+
+// SetDefault sets the scheduling policy used when a request does not select one
+func (s *SchedulerMgrImpl) SetDefault(name string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exist := s.schedulers[name]; !exist {
+		return errors.New("unknown scheduler : " + name)
+	}
+	log.Println(logPrefix, "[SetDefault]", name)
+	s.defaultName = name
+
+	return nil
+}
+
+// GetScheduler returns the scheduling policy of name, or default one if name is empty
+func (s *SchedulerMgrImpl) GetScheduler(name string) (Scheduler, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if len(name) == 0 {
+		name = s.defaultName
+	}
+
+	scheduler, exist := s.schedulers[name]
+	if !exist {
+		return nil, errors.New("unknown scheduler : " + name)
+	}
+
+	return scheduler, nil
+}
+
+// RecordPlacement records that a service is placed on the device
+func (s *SchedulerMgrImpl) RecordPlacement(deviceID string) {
+	s.historyMtx.Lock()
+	defer s.historyMtx.Unlock()
+
+	history := s.histories[deviceID]
+	history.Running++
+	history.Placed++
+	history.LastPlaced = time.Now()
+	s.histories[deviceID] = history
+}
+
+// RecordCompletion records that a service placed on the device is not running anymore
+func (s *SchedulerMgrImpl) RecordCompletion(deviceID string) {
+	s.historyMtx.Lock()
+	defer s.historyMtx.Unlock()
+
+	history, exist := s.histories[deviceID]
+	if !exist || history.Running == 0 {
+		return
+	}
+	history.Running--
+	s.histories[deviceID] = history
+}
+
+// GetHistory returns the placement history of the device
+func (s *SchedulerMgrImpl) GetHistory(deviceID string) History {
+	s.historyMtx.Lock()
+	defer s.historyMtx.Unlock()
+
+	return s.histories[deviceID]
+}
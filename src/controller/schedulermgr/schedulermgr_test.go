@@ -0,0 +1,115 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package schedulermgr
+
+import (
+	"testing"
+)
+
+type dummyScheduler struct{}
+
+func (dummyScheduler) Name() string {
+	return "dummy"
+}
+
+func (dummyScheduler) Schedule(serviceName string, candidates []Candidate) []Candidate {
+	return candidates
+}
+
+func (d dummyScheduler) Preview(serviceName string, candidates []Candidate) []Candidate {
+	return d.Schedule(serviceName, candidates)
+}
+
+func TestGetScheduler(t *testing.T) {
+	mgr := GetInstance()
+
+	t.Run("Default", func(t *testing.T) {
+		scheduler, err := mgr.GetScheduler("")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if scheduler.Name() != BestScore {
+			t.Error("unexpected default scheduler : ", scheduler.Name())
+		}
+	})
+	t.Run("BuiltIn", func(t *testing.T) {
+		for _, name := range []string{BestScore, RoundRobin, PreferLocal, LeastRunning, RandomWeighted} {
+			scheduler, err := mgr.GetScheduler(name)
+			if err != nil {
+				t.Error(err.Error())
+				continue
+			}
+			if scheduler.Name() != name {
+				t.Error("unexpected scheduler : ", scheduler.Name())
+			}
+		}
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := mgr.GetScheduler("unknown"); err == nil {
+			t.Error("unexpected success")
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	mgr := GetInstance()
+
+	if err := mgr.Register(dummyScheduler{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := mgr.Register(dummyScheduler{}); err == nil {
+		t.Error("unexpected success of duplicated register")
+	}
+
+	if err := mgr.SetDefault("dummy"); err != nil {
+		t.Fatal(err.Error())
+	}
+	defer mgr.SetDefault(BestScore)
+
+	scheduler, err := mgr.GetScheduler("")
+	if err != nil || scheduler.Name() != "dummy" {
+		t.Error("default scheduler is not changed")
+	}
+
+	if err := mgr.SetDefault("unknown"); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	mgr := GetInstance()
+	deviceID := "history-test-device"
+
+	mgr.RecordPlacement(deviceID)
+	mgr.RecordPlacement(deviceID)
+	mgr.RecordCompletion(deviceID)
+
+	history := mgr.GetHistory(deviceID)
+	if history.Running != 1 || history.Placed != 2 {
+		t.Error("unexpected history : ", history)
+	}
+	if history.LastPlaced.IsZero() {
+		t.Error("unexpected last placed time")
+	}
+
+	mgr.RecordCompletion(deviceID)
+	mgr.RecordCompletion(deviceID)
+	if history = mgr.GetHistory(deviceID); history.Running != 0 {
+		t.Error("unexpected running count : ", history.Running)
+	}
+}
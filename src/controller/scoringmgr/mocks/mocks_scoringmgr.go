@@ -64,3 +64,19 @@ func (mr *MockScoringMockRecorder) GetScore(ID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScore", reflect.TypeOf((*MockScoring)(nil).GetScore), ID)
 }
+
+// GetScoreWithComponents mocks base method
+func (m *MockScoring) GetScoreWithComponents(ID string) (float64, map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreWithComponents", ID)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(map[string]float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetScoreWithComponents indicates an expected call of GetScoreWithComponents
+func (mr *MockScoringMockRecorder) GetScoreWithComponents(ID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreWithComponents", reflect.TypeOf((*MockScoring)(nil).GetScoreWithComponents), ID)
+}
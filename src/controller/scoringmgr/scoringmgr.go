@@ -21,13 +21,27 @@ package scoringmgr
 import (
 	"common/resourceutil"
 	"math"
+	"time"
 )
 
 const logPrefix = "scoringmgr"
 
+// smoothingWindow is the window of samples averaged instead of a single noisy sample
+const smoothingWindow = 30 * time.Second
+
+const (
+	// ScoreComponentNetwork is the key of network bandwidth sub-score
+	ScoreComponentNetwork = "network"
+	// ScoreComponentCPU is the key of cpu sub-score
+	ScoreComponentCPU = "cpu"
+	// ScoreComponentRendering is the key of round trip time sub-score
+	ScoreComponentRendering = "rendering"
+)
+
 // Scoring is the interface to apply application specific scoring functions
 type Scoring interface {
 	GetScore(ID string) (scoreValue float64, err error)
+	GetScoreWithComponents(ID string) (scoreValue float64, components map[string]float64, err error)
 }
 
 // ScoringImpl structure
@@ -59,35 +73,79 @@ func (ScoringImpl) GetScore(ID string) (scoreValue float64, err error) {
 	return
 }
 
+// GetScoreWithComponents provides score value with its sub-scores and the reason of failure
+func (ScoringImpl) GetScoreWithComponents(ID string) (scoreValue float64, components map[string]float64, err error) {
+	components, err = calculateScoreComponents(ID)
+	if err != nil {
+		return 0.0, nil, err
+	}
+
+	return sumScoreComponents(components), components, nil
+}
+
 func calculateScore(ID string) float64 {
-	cpuUsage, err := resourceIns.GetResource(resourceutil.CPUUsage)
+	components, err := calculateScoreComponents(ID)
 	if err != nil {
 		return 0.0
 	}
+
+	return sumScoreComponents(components)
+}
+
+// calculateScoreComponents gives weighted sub-scores which make up the score together
+func calculateScoreComponents(ID string) (components map[string]float64, err error) {
+	cpuUsage, err := getSmoothedResource(resourceutil.CPUUsage)
+	if err != nil {
+		return nil, err
+	}
 	cpuCount, err := resourceIns.GetResource(resourceutil.CPUCount)
 	if err != nil {
-		return 0.0
+		return nil, err
 	}
 	cpuFreq, err := resourceIns.GetResource(resourceutil.CPUFreq)
 	if err != nil {
-		return 0.0
+		return nil, err
 	}
 	cpuScore := cpuScore(cpuUsage, cpuCount, cpuFreq)
 
 	netBandwidth, err := resourceIns.GetResource(resourceutil.NetBandwidth)
 	if err != nil {
-		return 0.0
+		return nil, err
 	}
-	netScore := netScore(netBandwidth)
 
 	resourceIns.SetDeviceID(ID)
+	// NOTE : the throughput to the requester is limited by the link speed as well
+	if peerBandwidth, err := resourceIns.GetResource(resourceutil.NetPeerBandwidth); err == nil &&
+		peerBandwidth > 0 && peerBandwidth < netBandwidth {
+		netBandwidth = peerBandwidth
+	}
+	netScore := netScore(netBandwidth)
+
 	rtt, err := resourceIns.GetResource(resourceutil.NetRTT)
 	if err != nil {
-		return 0.0
+		return nil, err
 	}
 	renderingScore := renderingScore(rtt)
 
-	return float64(netScore + (cpuScore / 2) + renderingScore)
+	components = make(map[string]float64)
+	components[ScoreComponentNetwork] = netScore
+	components[ScoreComponentCPU] = cpuScore / 2
+	components[ScoreComponentRendering] = renderingScore
+
+	return components, nil
+}
+
+// getSmoothedResource gives the average of the recent samples, or the latest value without history
+func getSmoothedResource(name string) (float64, error) {
+	history, err := resourceIns.GetResourceHistory(name, smoothingWindow)
+	if err == nil && history.Count > 0 {
+		return history.Avg, nil
+	}
+	return resourceIns.GetResource(name)
+}
+
+func sumScoreComponents(components map[string]float64) float64 {
+	return float64(components[ScoreComponentNetwork] + components[ScoreComponentCPU] + components[ScoreComponentRendering])
 }
 
 func netScore(bandWidth float64) (score float64) {
@@ -18,10 +18,12 @@
 package scoringmgr
 
 import (
+	"errors"
 	"testing"
 
 	"common/resourceutil"
 	resourceUtilMock "common/resourceutil/mocks"
+	resourceDB "db/bolt/resource"
 
 	"github.com/golang/mock/gomock"
 )
@@ -38,11 +40,13 @@ func TestGetScore_ExpectedSuccess(t *testing.T) {
 	resourceutilMockObj := resourceUtilMock.NewMockGetResource(ctrl)
 
 	gomock.InOrder(
+		resourceutilMockObj.EXPECT().GetResourceHistory(resourceutil.CPUUsage, gomock.Any()).Return(resourceDB.History{}, nil),
 		resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUUsage).Return(10.0, nil),
 		resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUCount).Return(10.0, nil),
 		resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUFreq).Return(10.0, nil),
 		resourceutilMockObj.EXPECT().GetResource(resourceutil.NetBandwidth).Return(10.0, nil),
 		resourceutilMockObj.EXPECT().SetDeviceID(dummyDevID),
+		resourceutilMockObj.EXPECT().GetResource(resourceutil.NetPeerBandwidth).Return(0.0, nil),
 		resourceutilMockObj.EXPECT().GetResource(resourceutil.NetRTT).Return(10.0, nil),
 	)
 	resourceIns = resourceutilMockObj
@@ -55,3 +59,86 @@ func TestGetScore_ExpectedSuccess(t *testing.T) {
 		t.Error("score : ", score, " expectedScore : ", expectedScore)
 	}
 }
+
+func TestGetScoreWithComponents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceutilMockObj := resourceUtilMock.NewMockGetResource(ctrl)
+	resourceIns = resourceutilMockObj
+
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			resourceutilMockObj.EXPECT().GetResourceHistory(resourceutil.CPUUsage, gomock.Any()).Return(resourceDB.History{}, errors.New("no history")),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUUsage).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUCount).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUFreq).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetBandwidth).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().SetDeviceID(dummyDevID),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetPeerBandwidth).Return(0.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetRTT).Return(10.0, nil),
+		)
+
+		score, components, err := GetInstance().GetScoreWithComponents(dummyDevID)
+		if err != nil {
+			t.Fatalf("Unexpected error return : %s", err.Error())
+		}
+		if score != expectedScore {
+			t.Error("score : ", score, " expectedScore : ", expectedScore)
+		}
+		if len(components) != 3 {
+			t.Error("unexpected components : ", components)
+		}
+	})
+	t.Run("Smoothed", func(t *testing.T) {
+		history := resourceDB.History{Name: resourceutil.CPUUsage, Count: 3, Latest: 90.0, Avg: 10.0}
+		gomock.InOrder(
+			resourceutilMockObj.EXPECT().GetResourceHistory(resourceutil.CPUUsage, smoothingWindow).Return(history, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUCount).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUFreq).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetBandwidth).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().SetDeviceID(dummyDevID),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetPeerBandwidth).Return(0.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetRTT).Return(10.0, nil),
+		)
+
+		score, _, err := GetInstance().GetScoreWithComponents(dummyDevID)
+		if err != nil {
+			t.Fatalf("Unexpected error return : %s", err.Error())
+		}
+		if score != expectedScore {
+			t.Error("score : ", score, " expectedScore : ", expectedScore)
+		}
+	})
+	t.Run("PeerBandwidth", func(t *testing.T) {
+		gomock.InOrder(
+			resourceutilMockObj.EXPECT().GetResourceHistory(resourceutil.CPUUsage, gomock.Any()).Return(resourceDB.History{}, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUUsage).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUCount).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUFreq).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetBandwidth).Return(10.0, nil),
+			resourceutilMockObj.EXPECT().SetDeviceID(dummyDevID),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetPeerBandwidth).Return(1.0, nil),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.NetRTT).Return(10.0, nil),
+		)
+
+		_, components, err := GetInstance().GetScoreWithComponents(dummyDevID)
+		if err != nil {
+			t.Fatalf("Unexpected error return : %s", err.Error())
+		}
+		if components[ScoreComponentNetwork] != netScore(1.0) {
+			t.Error("unexpected network score : ", components[ScoreComponentNetwork])
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		gomock.InOrder(
+			resourceutilMockObj.EXPECT().GetResourceHistory(resourceutil.CPUUsage, gomock.Any()).Return(resourceDB.History{}, errors.New("no history")),
+			resourceutilMockObj.EXPECT().GetResource(resourceutil.CPUUsage).Return(0.0, errors.New("cpu usage")),
+		)
+
+		score, components, err := GetInstance().GetScoreWithComponents(dummyDevID)
+		if err == nil || score != 0.0 || components != nil {
+			t.Error("unexpected success")
+		}
+	})
+}
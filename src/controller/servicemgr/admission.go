@@ -0,0 +1,205 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package servicemgr
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"common/errors"
+	"common/resourceutil"
+	"controller/servicemgr/executor"
+)
+
+// Keys of the limits file
+const (
+	ConstLimitMaxServices        = "max_services"
+	ConstLimitMaxInstances       = "max_instances."
+	ConstLimitMaxCPUUsage        = "max_cpu_usage"
+	ConstLimitMinMemoryAvailable = "min_memory_available"
+	ConstLimitMaxExecutionTime   = "max_execution_time"
+)
+
+// Limits restricts services executed on local device, zero value means no limit
+type Limits struct {
+	// MaxServices is the maximum number of concurrent services
+	MaxServices int
+	// MaxInstances is the maximum number of concurrent instances of each service
+	MaxInstances map[string]int
+	// MaxCPUUsage is the CPU usage (%) above which services are rejected
+	MaxCPUUsage float64
+	// MinMemoryAvailable is the available memory (KB) below which services are rejected
+	MinMemoryAvailable float64
+	// MaxExecutionTime is the deadline of every execution, shorter deadlines of requests are kept
+	MaxExecutionTime time.Duration
+}
+
+// Capacity is the limits and the current load of local device
+type Capacity struct {
+	Limits
+	RunningServices  int
+	RunningInstances map[string]int
+	// Saturated is set if a new service would be rejected regardless of its name
+	Saturated bool
+}
+
+var (
+	admissionMtx sync.Mutex
+	limits       Limits
+
+	resourceIns resourceutil.GetResource
+)
+
+func init() {
+	resourceIns = &resourceutil.ResourceImpl{}
+}
+
+// SetLimits sets the limits of services executed on local device
+func (sm *SMMgrImpl) SetLimits(l Limits) {
+	admissionMtx.Lock()
+	defer admissionMtx.Unlock()
+
+	log.Println(logPrefix, "[SetLimits]", l)
+	limits = l
+}
+
+// GetCapacity returns the limits and the current load of local device
+func (sm SMMgrImpl) GetCapacity() Capacity {
+	admissionMtx.Lock()
+	defer admissionMtx.Unlock()
+
+	capacity := Capacity{Limits: limits}
+	capacity.RunningServices, capacity.RunningInstances = countRunningServices()
+	capacity.Saturated = checkDeviceLimits(capacity.RunningServices) != nil
+
+	return capacity
+}
+
+// admitService records the service as running if it does not exceed the limits
+func admitService(info executor.ServiceExecutionInfo) error {
+	admissionMtx.Lock()
+	defer admissionMtx.Unlock()
+
+	running, instances := countRunningServices()
+	if err := checkDeviceLimits(running); err != nil {
+		return err
+	}
+
+	if max, ok := limits.MaxInstances[info.ServiceName]; ok && max > 0 && instances[info.ServiceName] >= max {
+		return errors.SystemError{Message: "max instances of " + info.ServiceName + " are running"}
+	}
+
+	runningServiceMap.Set(info.ServiceID, info)
+	return nil
+}
+
+// executionTimeout gives the deadline of execution from the requested timeout and the limit of local device
+func executionTimeout(requested time.Duration) time.Duration {
+	admissionMtx.Lock()
+	defer admissionMtx.Unlock()
+
+	if limits.MaxExecutionTime > 0 && (requested <= 0 || requested > limits.MaxExecutionTime) {
+		return limits.MaxExecutionTime
+	}
+	return requested
+}
+
+func checkDeviceLimits(running int) error {
+	if limits.MaxServices > 0 && running >= limits.MaxServices {
+		return errors.SystemError{Message: "max services are running"}
+	}
+
+	if limits.MaxCPUUsage > 0 {
+		if usage, err := resourceIns.GetResource(resourceutil.CPUUsage); err == nil && usage > limits.MaxCPUUsage {
+			return errors.SystemError{Message: "cpu usage is over the limit"}
+		}
+	}
+
+	if limits.MinMemoryAvailable > 0 {
+		if available, err := resourceIns.GetResource(resourceutil.MemAvailable); err == nil && available < limits.MinMemoryAvailable {
+			return errors.SystemError{Message: "available memory is under the limit"}
+		}
+	}
+
+	return nil
+}
+
+func countRunningServices() (running int, instances map[string]int) {
+	instances = make(map[string]int)
+	for item := range runningServiceMap.Iter() {
+		info := item.Value.(executor.ServiceExecutionInfo)
+		instances[info.ServiceName]++
+		running++
+	}
+	return
+}
+
+// ReadLimits reads the limits (key=value per line) from limitPath
+func ReadLimits(limitPath string) (l Limits, err error) {
+	file, err := os.Open(limitPath)
+	if err != nil {
+		return l, err
+	}
+	defer file.Close()
+
+	l.MaxInstances = make(map[string]int)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pair := strings.SplitN(line, "=", 2)
+		if len(pair) != 2 {
+			log.Println(logPrefix, "[ReadLimits]", "invalid limit : ", line)
+			continue
+		}
+		key, value := strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])
+
+		var parseErr error
+		switch {
+		case key == ConstLimitMaxServices:
+			l.MaxServices, parseErr = strconv.Atoi(value)
+		case key == ConstLimitMaxCPUUsage:
+			l.MaxCPUUsage, parseErr = strconv.ParseFloat(value, 64)
+		case key == ConstLimitMinMemoryAvailable:
+			l.MinMemoryAvailable, parseErr = strconv.ParseFloat(value, 64)
+		case key == ConstLimitMaxExecutionTime:
+			var seconds float64
+			seconds, parseErr = strconv.ParseFloat(value, 64)
+			l.MaxExecutionTime = time.Duration(seconds * float64(time.Second))
+		case strings.HasPrefix(key, ConstLimitMaxInstances) && len(key) > len(ConstLimitMaxInstances):
+			l.MaxInstances[key[len(ConstLimitMaxInstances):]], parseErr = strconv.Atoi(value)
+		default:
+			log.Println(logPrefix, "[ReadLimits]", "unknown limit : ", line)
+		}
+
+		if parseErr != nil {
+			log.Println(logPrefix, "[ReadLimits]", "invalid limit : ", line)
+		}
+	}
+
+	return l, scanner.Err()
+}
@@ -0,0 +1,146 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package servicemgr
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"common/resourceutil"
+	resourceutilmocks "common/resourceutil/mocks"
+	"controller/servicemgr/executor"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestAdmitService(t *testing.T) {
+	defer GetInstance().SetLimits(Limits{})
+
+	info := func(id uint64, name string) executor.ServiceExecutionInfo {
+		return executor.ServiceExecutionInfo{ServiceID: id, ServiceName: name}
+	}
+	defer func() {
+		for _, id := range []uint64{1001, 1002, 1003} {
+			runningServiceMap.Remove(id)
+		}
+	}()
+
+	t.Run("MaxInstances", func(t *testing.T) {
+		GetInstance().SetLimits(Limits{MaxServices: 2, MaxInstances: map[string]int{serviceName: 1}})
+
+		if err := admitService(info(1001, serviceName)); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+		if err := admitService(info(1002, serviceName)); err == nil {
+			t.Error("expected error for max instances")
+		}
+	})
+	t.Run("MaxServices", func(t *testing.T) {
+		if err := admitService(info(1002, serviceName2)); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+		if err := admitService(info(1003, "other")); err == nil {
+			t.Error("expected error for max services")
+		}
+
+		capacity := GetInstance().GetCapacity()
+		if capacity.Saturated == false || capacity.RunningServices != 2 || capacity.RunningInstances[serviceName] != 1 {
+			t.Error("unexpected capacity : ", capacity)
+		}
+	})
+}
+
+func TestCheckDeviceLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceMock := resourceutilmocks.NewMockGetResource(ctrl)
+	defaultResource := resourceIns
+	resourceIns = resourceMock
+	defer func() {
+		resourceIns = defaultResource
+		GetInstance().SetLimits(Limits{})
+	}()
+
+	GetInstance().SetLimits(Limits{MaxCPUUsage: 80, MinMemoryAvailable: 1024})
+
+	t.Run("CPUUsage", func(t *testing.T) {
+		resourceMock.EXPECT().GetResource(resourceutil.CPUUsage).Return(90.0, nil)
+		if err := checkDeviceLimits(0); err == nil {
+			t.Error("expected error for cpu usage")
+		}
+	})
+	t.Run("MemoryAvailable", func(t *testing.T) {
+		resourceMock.EXPECT().GetResource(resourceutil.CPUUsage).Return(10.0, nil)
+		resourceMock.EXPECT().GetResource(resourceutil.MemAvailable).Return(512.0, nil)
+		if err := checkDeviceLimits(0); err == nil {
+			t.Error("expected error for available memory")
+		}
+	})
+	t.Run("Success", func(t *testing.T) {
+		resourceMock.EXPECT().GetResource(resourceutil.CPUUsage).Return(10.0, nil)
+		resourceMock.EXPECT().GetResource(resourceutil.MemAvailable).Return(2048.0, nil)
+		if err := checkDeviceLimits(0); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+	})
+}
+
+func TestExecutionTimeout(t *testing.T) {
+	defer GetInstance().SetLimits(Limits{})
+
+	if timeout := executionTimeout(time.Minute); timeout != time.Minute {
+		t.Error("unexpected timeout without limit : ", timeout)
+	}
+
+	GetInstance().SetLimits(Limits{MaxExecutionTime: time.Minute})
+	tests := map[string]struct{ requested, expected time.Duration }{
+		"NoDeadline": {0, time.Minute},
+		"Shorter":    {time.Second, time.Second},
+		"Longer":     {time.Hour, time.Minute},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if timeout := executionTimeout(test.requested); timeout != test.expected {
+				t.Error("unexpected timeout : ", timeout)
+			}
+		})
+	}
+}
+
+func TestReadLimits(t *testing.T) {
+	file, err := ioutil.TempFile("", "limits")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.Remove(file.Name())
+
+	file.WriteString("# limits\nmax_services = 4\nmax_instances.ls=2\nmax_cpu_usage=90.5\nmin_memory_available=1024\nmax_execution_time=1.5\nunknown=1\n")
+	file.Close()
+
+	l, err := ReadLimits(file.Name())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if l.MaxServices != 4 || l.MaxInstances["ls"] != 2 || l.MaxCPUUsage != 90.5 || l.MinMemoryAvailable != 1024 ||
+		l.MaxExecutionTime != 1500*time.Millisecond {
+		t.Error("unexpected limits : ", l)
+	}
+}
@@ -19,15 +19,17 @@
 package androidexecutor
 
 import (
-	"bufio"
 	"errors"
 	"log"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"controller/servicemgr"
 	"controller/servicemgr/executor"
 	"controller/servicemgr/notification"
+	"controller/servicemgr/servicelog"
 )
 
 var (
@@ -58,20 +60,34 @@ func (t AndroidExecutor) Execute(s executor.ServiceExecutionInfo) (err error) {
 	log.Println(logPrefix, t.ServiceName, t.ParamStr)
 	log.Println(logPrefix, "parameter length :", len(t.ParamStr))
 
+	logs := servicelog.GetInstance()
+	logs.Open(t.NotificationTargetURL, t.ServiceID)
+
+	startTime := time.Now()
 	cmd, pid, err := t.setService()
 	if err != nil {
+		logs.Close(t.NotificationTargetURL, t.ServiceID)
+		t.notifyServiceResult(executor.Ended(notification.ExecutionResult{
+			ServiceID: t.ServiceID,
+			Status:    servicemgr.ConstServiceStatusFailed,
+			Reason:    executor.Reason(executor.ReasonStartFailed, err),
+		}, startTime))
 		return
 	}
 
 	log.Println(logPrefix, "Just ran subprocess ", pid)
+	if notiErr := t.NotifyStarted(t.ServiceExecutionInfo, pid, startTime); notiErr != nil {
+		log.Println(logPrefix, notiErr.Error())
+	}
 
 	executeCh := make(chan error)
 	go func() {
 		executeCh <- cmd.Wait()
 	}()
 
-	status, err := t.waitService(executeCh)
-	t.notifyServiceStatus(status)
+	result, err := t.waitService(cmd, executeCh)
+	logs.Close(t.NotificationTargetURL, t.ServiceID)
+	t.notifyServiceResult(executor.Ended(result, startTime))
 
 	return
 }
@@ -87,43 +103,96 @@ func (t AndroidExecutor) setService() (cmd *exec.Cmd, pid int, err error) {
 	log.Println(logPrefix, "Adb start cmd: ", adbStart)
 	cmd = exec.Command(adbPath, adbStart[0:]...)
 
-	stdout, _ := cmd.StdoutPipe()
+	// @Note : stdout and stderr are captured while the process is running and stdin is fed from the request
+	logs := servicelog.GetInstance()
+	cmd.Stdout = logs.Writer(t.NotificationTargetURL, t.ServiceID, servicelog.StreamStdout)
+	cmd.Stderr = logs.Writer(t.NotificationTargetURL, t.ServiceID, servicelog.StreamStderr)
+	if len(t.Stdin) != 0 {
+		cmd.Stdin = strings.NewReader(t.Stdin)
+	}
+
+	// @Note : the process is terminated with its children when its deadline is passed
+	cmd.SysProcAttr = executor.ProcessGroup()
+
 	err = cmd.Start()
 	if err != nil {
 		log.Println(logPrefix, err.Error())
 		return
 	}
 
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		m := scanner.Text()
-		log.Println(m)
-	}
-
 	pid = cmd.Process.Pid
 
 	return
 }
 
-func (t AndroidExecutor) waitService(executeCh <-chan error) (status string, e error) {
-	e = <-executeCh
+func (t AndroidExecutor) waitService(cmd *exec.Cmd, executeCh <-chan error) (result notification.ExecutionResult, e error) {
+	timedOut, terminated := false, false
+	select {
+	case e = <-executeCh:
+	case <-executor.Deadline(t.Timeout):
+		log.Println(logPrefix, t.ServiceName, "is terminated by its deadline :", t.Timeout)
+		timedOut = true
+		e = executor.Terminate(cmd.Process, executeCh)
+		t.forceStop()
+	case <-executor.Stopping():
+		log.Println(logPrefix, t.ServiceName, "is terminated by stopping orchestration")
+		terminated = true
+		e = executor.Terminate(cmd.Process, executeCh)
+		t.forceStop()
+	}
 
-	status = servicemgr.ConstServiceStatusFinished
+	result.ServiceID = t.ServiceID
+	result.Status = servicemgr.ConstServiceStatusFinished
+	result.ExitCode, result.Signal = executor.ExitStatus(e)
 
 	if e != nil {
 		if e.Error() == os.Kill.String() {
 			log.Println(logPrefix, "Success to delete service")
 		} else {
-			status = servicemgr.ConstServiceStatusFailed
+			result.Status = servicemgr.ConstServiceStatusFailed
+			result.Reason = e.Error()
 			log.Println(logPrefix, t.ServiceName, "exited with error : ", e)
 		}
 	} else {
 		log.Println(logPrefix, t.ServiceName, "is exited with no error")
 	}
 
+	if timedOut {
+		result = executor.TimedOut(result, t.Timeout)
+	} else if terminated {
+		result = executor.Terminated(result)
+	}
+
 	return
 }
 
-func (t AndroidExecutor) notifyServiceStatus(status string) {
-	t.NotiImplIns.InvokeNotification(t.NotificationTargetURL, float64(t.ServiceID), status)
+// forceStop stops the package of the activity, the activity is not stopped by terminating am start
+func (t AndroidExecutor) forceStop() {
+	cmd, err := forceStopCommand(t.ParamStr)
+	if err != nil {
+		log.Println(logPrefix, err.Error())
+		return
+	}
+
+	log.Println(logPrefix, "Adb force-stop cmd: ", cmd.Args[1:])
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.Println(logPrefix, "force-stop is failed :", err.Error(), string(out))
+	}
+}
+
+// forceStopCommand returns am force-stop of the package in the component (package/activity) given to am start -n
+func forceStopCommand(params []string) (*exec.Cmd, error) {
+	if len(params) < 1 {
+		return nil, errors.New("error: empty parameter")
+	}
+
+	pkg := strings.SplitN(params[0], "/", 2)[0]
+	if len(pkg) == 0 {
+		return nil, errors.New("error: no package in " + params[0])
+	}
+	return exec.Command(adbPath, "force-stop", pkg), nil
+}
+
+func (t AndroidExecutor) notifyServiceResult(result notification.ExecutionResult) {
+	t.NotifyResult(t.ServiceExecutionInfo, result)
 }
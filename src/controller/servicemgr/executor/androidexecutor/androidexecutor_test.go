@@ -0,0 +1,118 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package androidexecutor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"controller/servicemgr"
+	"controller/servicemgr/executor"
+	"controller/servicemgr/notification"
+	notificationMock "controller/servicemgr/notification/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestForceStopCommand(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := forceStopCommand([]string{"com.example.app/.MainActivity", "-e", "key", "value"})
+		if err != nil {
+			t.Fatal("unexpected error : ", err.Error())
+		}
+		if expected := []string{adbPath, "force-stop", "com.example.app"}; !reflect.DeepEqual(cmd.Args, expected) {
+			t.Error("unexpected command : ", cmd.Args)
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		for _, params := range [][]string{nil, {"/.MainActivity"}} {
+			if _, err := forceStopCommand(params); err == nil {
+				t.Error("expected error : ", params)
+			}
+		}
+	})
+}
+
+func TestExecuteTimedOut(t *testing.T) {
+	dir, err := ioutil.TempDir("", "androidexecutor")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	// @Note : the fake am records its arguments and am start does not return until it is terminated
+	record := filepath.Join(dir, "record")
+	fakeAm := filepath.Join(dir, "am")
+	script := "#!/bin/sh\necho \"$@\" >> " + record + "\nif [ \"$1\" = start ]; then exec sleep 10; fi\n"
+	if err := ioutil.WriteFile(fakeAm, []byte(script), 0700); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defaultPath := adbPath
+	adbPath = fakeAm
+	defer func() {
+		adbPath = defaultPath
+	}()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	noti := notificationMock.NewMockNotification(ctrl)
+	gomock.InOrder(
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusStarted {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			}),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusTimedOut {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			}),
+	)
+
+	tExecutor := GetInstance()
+	tExecutor.SetNotiImpl(noti)
+	defer tExecutor.SetNotiImpl(notification.GetInstance())
+
+	s := executor.ServiceExecutionInfo{
+		ServiceID:   uint64(1),
+		ServiceName: "example",
+		ParamStr:    []string{"com.example.app/.MainActivity"},
+		Timeout:     100 * time.Millisecond,
+	}
+	tExecutor.Execute(s)
+
+	out, err := ioutil.ReadFile(record)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []string{"start -n com.example.app/.MainActivity", "force-stop com.example.app"}
+	if calls := strings.Split(strings.TrimSpace(string(out)), "\n"); !reflect.DeepEqual(calls, expected) {
+		t.Error("unexpected am calls : ", calls)
+	}
+}
@@ -19,12 +19,16 @@ package containerexecutor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
-	"os"
+	"time"
 
 	"docker.io/go-docker"
 	"docker.io/go-docker/api/types"
 	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/events"
+	"docker.io/go-docker/api/types/filters"
 	"docker.io/go-docker/api/types/network"
 )
 
@@ -35,13 +39,12 @@ type CEImpl interface {
 	Start(id string) error
 	Wait(id string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error)
 	Logs(id string) (io.ReadCloser, error)
-	ImagePull(image string) error
-
-	// @Note : When below api is need to implments, it will be opened
-	// PS() ([]types.Container, error)
-	// Stop(id string, timeout *time.Duration) error
-	// Events() (<-chan events.Message, <-chan error)
-	// ImageTag(source string, target string) error
+	ImagePull(image string, registryAuth string, progress func(status string)) error
+	ImageExists(image string) (bool, error)
+	PS() ([]types.Container, error)
+	Stop(id string, timeout *time.Duration) error
+	Events() (<-chan events.Message, <-chan error)
+	ImageTag(source string, target string) error
 }
 
 // CEDocker structure
@@ -79,45 +82,93 @@ func (ce CEDocker) Wait(id string, condition container.WaitCondition) (statusCh
 	return ce.client.ContainerWait(ce.ctx, id, container.WaitConditionNotRunning)
 }
 
-// Logs is to logs container
+// Logs is to follow logs of container until it is exited
 func (ce CEDocker) Logs(id string) (io.ReadCloser, error) {
 	opts := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Timestamps: true,
+		Follow:     true,
 	}
 	return ce.client.ContainerLogs(ce.ctx, id, opts)
 }
 
-// ImagePull is to pull container images
-func (ce CEDocker) ImagePull(image string) (err error) {
-	reader, err := ce.client.ImagePull(ce.ctx, image, types.ImagePullOptions{})
-	if err == nil {
-		io.Copy(os.Stdout, reader)
+// pullMessage is a message of docker reporting the progress of pulling image
+type pullMessage struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress string `json:"progress"`
+	Error    string `json:"error"`
+}
+
+// String gives the message as "<layer>: <status> <progress>"
+func (m pullMessage) String() string {
+	str := m.Status
+	if len(m.ID) != 0 {
+		str = m.ID + ": " + str
+	}
+	if len(m.Progress) != 0 {
+		str += " " + m.Progress
 	}
+	return str
+}
 
-	return
+// ImagePull is to pull container images with the encoded auth of registry, progress is called with every message
+// of pulling and the error reported in the messages is returned
+func (ce CEDocker) ImagePull(image string, registryAuth string, progress func(status string)) error {
+	reader, err := ce.client.ImagePull(ce.ctx, image, types.ImagePullOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var msg pullMessage
+		if err = decoder.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if len(msg.Error) != 0 {
+			return errors.New(msg.Error)
+		}
+		progress(msg.String())
+	}
 }
 
-// PS function
-// func (ce CEDocker) PS() ([]types.Container, error) {
-// 	return ce.client.ContainerList(ce.ctx, types.ContainerListOptions{})
-// }
-
-// Stop function
-// func (ce CEDocker) Stop(id string, timeout *time.Duration) (err error) {
-// 	return ce.client.ContainerStop(ce.ctx, id, timeout)
-// }
-
-// Events function
-// func (ce CEDocker) Events() (<-chan events.Message, <-chan error) {
-// 	return ce.client.Events(ce.ctx, types.EventsOptions{})
-// }// Events function
-// func (ce CEDocker) Events() (<-chan events.Message, <-chan error) {
-// 	return ce.client.Events(ce.ctx, types.EventsOptions{})
-// }
-
-// ImageTag function
-// func (ce CEDocker) ImageTag(source string, target string) (err error) {
-// 	return ce.client.ImageTag(ce.ctx, source, target)
-// }
+// ImageExists is to check whether the image is on the device
+func (ce CEDocker) ImageExists(image string) (bool, error) {
+	_, _, err := ce.client.ImageInspectWithRaw(ce.ctx, image)
+	if docker.IsErrNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PS is to list running containers managed by orchestration
+func (ce CEDocker) PS() ([]types.Container, error) {
+	args := filters.NewArgs()
+	args.Add("label", ConstLabelServiceID)
+	return ce.client.ContainerList(ce.ctx, types.ContainerListOptions{Filters: args})
+}
+
+// Stop is to stop container, it is killed if not stopped until timeout
+func (ce CEDocker) Stop(id string, timeout *time.Duration) (err error) {
+	return ce.client.ContainerStop(ce.ctx, id, timeout)
+}
+
+// Events is to receive events of containers managed by orchestration
+func (ce CEDocker) Events() (<-chan events.Message, <-chan error) {
+	args := filters.NewArgs()
+	args.Add("type", events.ContainerEventType)
+	args.Add("label", ConstLabelServiceID)
+	return ce.client.Events(ce.ctx, types.EventsOptions{Filters: args})
+}
+
+// ImageTag is to tag source image with target
+func (ce CEDocker) ImageTag(source string, target string) (err error) {
+	return ce.client.ImageTag(ce.ctx, source, target)
+}
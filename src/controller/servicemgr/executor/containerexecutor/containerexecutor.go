@@ -19,11 +19,17 @@
 package containerexecutor
 
 import (
+	"errors"
+	"io"
 	"log"
-	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 	"unsafe"
 
 	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/events"
 	"docker.io/go-docker/api/types/network"
 	githubcontainer "github.com/docker/docker/api/types/container"
 	githubnetwork "github.com/docker/docker/api/types/network"
@@ -32,19 +38,63 @@ import (
 
 	servicemgr "controller/servicemgr"
 	"controller/servicemgr/executor"
+	"controller/servicemgr/executor/containerspec"
 	"controller/servicemgr/notification"
+	"controller/servicemgr/servicelog"
 )
 
+const (
+	// ConstLabelServiceID is the label of containers holding the service ID of orchestration
+	ConstLabelServiceID = "org.edge-orchestration.service-id"
+
+	// ConstLabelServiceName is the label of containers holding the service name of orchestration
+	ConstLabelServiceName = "org.edge-orchestration.service-name"
+
+	// stopTimeout is the time given to a container to exit before it is killed on Cancel
+	stopTimeout = 10 * time.Second
+
+	// logFlushTimeout is the time waiting the log of exited container to be captured
+	logFlushTimeout = 5 * time.Second
+)
+
+// pullProgressInterval is the minimum interval of Pulling notifications of a service
+var pullProgressInterval = time.Second
+
 var (
 	logPrefix         = "[containerexecutor]"
 	containerExecutor *ContainerExecutor
+
+	// containerMtx protects runningContainers and watchingEvents
+	containerMtx      sync.Mutex
+	runningContainers = make(map[uint64]*runningContainer)
+	watchingEvents    bool
 )
 
+// runningContainer keeps a container started by Execute until its execution is notified
+type runningContainer struct {
+	containerID string
+	canceled    bool
+	timedOut    bool
+	terminated  bool
+	killed      bool
+}
+
+// ContainerService is a running container labelled with the service ID of orchestration
+type ContainerService struct {
+	ServiceID   uint64
+	ServiceName string
+	ContainerID string
+	Image       string
+	State       string
+	Status      string
+}
+
 // ContainerExecutor struct
 type ContainerExecutor struct {
 	executor.ServiceExecutionInfo
 
-	ceImplIns CEImpl
+	ceImplIns     CEImpl
+	registryCreds map[string]RegistryCredential
 	executor.HasClientNotification
 }
 
@@ -68,63 +118,391 @@ func (c ContainerExecutor) Execute(s executor.ServiceExecutionInfo) (err error)
 
 	log.Println(logPrefix, c.ServiceName, c.ParamStr)
 	log.Println(logPrefix, "parameter length :", len(c.ParamStr))
-	paramLen := len(c.ParamStr)
+	startTime := time.Now()
+	servicelog.GetInstance().Open(s.NotificationTargetURL, s.ServiceID)
+
+	// @Note : Convert container spec or docker run arguments to the configurations of container
+	run, err := parseContainerRun(s.ParamStr)
+	if err != nil {
+		log.Println(logPrefix, err.Error())
+		c.notifyFailure(executor.Reason(executor.ReasonInvalidConfig, err), startTime)
+		return
+	}
 
-	// @Note : Pull docker image
-	pullErr := c.ceImplIns.ImagePull(s.ParamStr[paramLen-1])
-	if pullErr != nil {
+	// @Note : Obtain docker image by its pull policy, the requester is notified with Pulling while it is pulled
+	if err = c.obtainImage(run.conf.Image, run.pullPolicy); err != nil {
 		log.Println(logPrefix, err.Error())
+		c.notifyFailure(executor.Reason(executor.ReasonPullFailed, err), startTime)
+		return
 	}
 
-	// @Note : Create containers with converting configuration
-	resp, createErr := c.ceImplIns.Create(convertConfig(s.ParamStr))
-	if createErr != nil {
+	// @Note : Create containers with labels of service
+	containerConf := run.conf
+	setServiceLabels(containerConf, s)
+	resp, err := c.ceImplIns.Create(containerConf, run.hostConf, run.networkConf)
+	if err != nil {
 		log.Println(logPrefix, err.Error())
-	} else {
-		log.Println(logPrefix, "create container :", resp.ID[:10])
+		c.notifyFailure(executor.Reason(executor.ReasonCreateFailed, err), startTime)
+		return
 	}
+	log.Println(logPrefix, "create container :", resp.ID[:10])
+
+	// @Note : Start container, it is watched to know whether it is killed outside of orchestration
+	c.watchEvents()
+	addRunningContainer(s.ServiceID, resp.ID)
+	defer removeRunningContainer(s.ServiceID)
 
-	// @Note : Start container
 	err = c.ceImplIns.Start(resp.ID)
 	if err != nil {
 		log.Println("err :", err)
+		c.notifyFailure(executor.Reason(executor.ReasonStartFailed, err), startTime)
+		c.removeContainer(resp.ID)
 		return err
 	}
 
-	// @Note : Waiting Container execution status
-	var executionStatus string
+	// @Note : Follow log of container to give it to the requester
+	logDone := c.followLogs(resp.ID, containerConf.Tty)
+
+	// @Note : Waiting Container execution status, the container is stopped if its deadline is passed
+	result := notification.ExecutionResult{ServiceID: s.ServiceID}
+	deadline := executor.Deadline(s.Timeout)
+	stopping := executor.Stopping()
 	statusCh, errCh := c.ceImplIns.Wait(resp.ID, container.WaitConditionNotRunning)
-	select {
-	case err = <-errCh:
-		log.Println(logPrefix, err.Error())
-		executionStatus = servicemgr.ConstServiceStatusFailed
-	case status := <-statusCh:
-		log.Println(logPrefix, "container execution status :", status.StatusCode)
-		if status.StatusCode == 0 {
-			executionStatus = servicemgr.ConstServiceStatusFinished
+	for waiting := true; waiting; {
+		select {
+		case err = <-errCh:
+			log.Println(logPrefix, err.Error())
+			result.Status = servicemgr.ConstServiceStatusFailed
+			result.Reason = executor.Reason(executor.ReasonWaitFailed, err)
+			waiting = false
+		case status := <-statusCh:
+			log.Println(logPrefix, "container execution status :", status.StatusCode)
+			result.ExitCode, result.Signal = exitStatus(status.StatusCode)
+			result.Status = servicemgr.ConstServiceStatusFinished
+			if status.StatusCode != 0 {
+				result.Status = servicemgr.ConstServiceStatusFailed
+			}
+			waiting = false
+		case <-deadline:
+			deadline = nil
+			c.stopTimedOut(s.ServiceID, resp.ID)
+		case <-stopping:
+			stopping = nil
+			c.stopTerminated(s.ServiceID, resp.ID)
 		}
 	}
 
-	// @Note : get log of container
-	out, logErr := c.ceImplIns.Logs(resp.ID)
-	if logErr != nil {
-		log.Println(logPrefix, err.Error())
-	} else {
-		stdcopy.StdCopy(os.Stdout, os.Stderr, out)
+	switch canceled, timedOut, terminated, killed := getStoppedReason(s.ServiceID); {
+	case canceled:
+		result.Status = servicemgr.ConstServiceStatusCanceled
+	case timedOut:
+		result = executor.TimedOut(result, s.Timeout)
+	case terminated:
+		result = executor.Terminated(result)
+	case killed:
+		log.Println(logPrefix, c.ServiceName, "is killed outside of orchestration")
+		result.Status = servicemgr.ConstServiceStatusKilled
+	}
+
+	// @Note : wait the rest of log of container
+	select {
+	case <-logDone:
+	case <-time.After(logFlushTimeout):
+		log.Println(logPrefix, "log of", c.ServiceName, "is not ended")
 	}
+	servicelog.GetInstance().Close(s.NotificationTargetURL, s.ServiceID)
 
 	// @Note : make notification
-	c.NotiImplIns.InvokeNotification(c.NotificationTargetURL, float64(c.ServiceID), executionStatus)
+	c.NotifyResult(s, executor.Ended(result, startTime))
 
 	// @Note : Remove container after execution
-	removeErr := c.ceImplIns.Remove(resp.ID)
-	if removeErr != nil {
+	c.removeContainer(resp.ID)
+
+	return
+}
+
+// Cancel stops the container of serviceID, the requester of the service is notified with Canceled
+func (c ContainerExecutor) Cancel(serviceID uint64) error {
+	containerID, err := c.findContainer(serviceID)
+	if err != nil {
+		return err
+	}
+
+	containerMtx.Lock()
+	if running, ok := runningContainers[serviceID]; ok {
+		running.canceled = true
+	}
+	containerMtx.Unlock()
+
+	log.Println(logPrefix, "[Cancel]", serviceID, containerID)
+	timeout := stopTimeout
+	return c.ceImplIns.Stop(containerID, &timeout)
+}
+
+// stopTimedOut stops the container passed its deadline, docker kills it if it is not exited in TerminationGracePeriod
+func (c ContainerExecutor) stopTimedOut(serviceID uint64, containerID string) {
+	log.Println(logPrefix, c.ServiceName, "is stopped by its deadline :", c.Timeout)
+
+	containerMtx.Lock()
+	if running, ok := runningContainers[serviceID]; ok {
+		running.timedOut = true
+	}
+	containerMtx.Unlock()
+
+	timeout := executor.TerminationGracePeriod
+	if err := c.ceImplIns.Stop(containerID, &timeout); err != nil {
+		log.Println(logPrefix, err.Error())
+	}
+}
+
+// stopTerminated stops the container of serviceID when orchestration is stopped
+func (c ContainerExecutor) stopTerminated(serviceID uint64, containerID string) {
+	log.Println(logPrefix, c.ServiceName, "is stopped by stopping orchestration")
+
+	containerMtx.Lock()
+	if running, ok := runningContainers[serviceID]; ok {
+		running.terminated = true
+	}
+	containerMtx.Unlock()
+
+	timeout := executor.TerminationGracePeriod
+	if err := c.ceImplIns.Stop(containerID, &timeout); err != nil {
 		log.Println(logPrefix, err.Error())
 	}
+}
 
+// List returns running containers labelled with the service ID of orchestration
+func (c ContainerExecutor) List() (services []ContainerService, err error) {
+	containers, err := c.ceImplIns.PS()
+	if err != nil {
+		return
+	}
+
+	services = make([]ContainerService, 0, len(containers))
+	for _, item := range containers {
+		serviceID, parseErr := strconv.ParseUint(item.Labels[ConstLabelServiceID], 10, 64)
+		if parseErr != nil {
+			continue
+		}
+		services = append(services, ContainerService{
+			ServiceID:   serviceID,
+			ServiceName: item.Labels[ConstLabelServiceName],
+			ContainerID: item.ID,
+			Image:       item.Image,
+			State:       item.State,
+			Status:      item.Status,
+		})
+	}
 	return
 }
 
+// followLogs captures the output of container until it is exited, the returned channel is closed at the end of output,
+// the output of container with tty is not multiplexed and it is captured as stdout
+func (c ContainerExecutor) followLogs(id string, tty bool) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		out, err := c.ceImplIns.Logs(id)
+		if err != nil {
+			log.Println(logPrefix, err.Error())
+			return
+		}
+		defer out.Close()
+
+		logs := servicelog.GetInstance()
+		stdout := logs.Writer(c.NotificationTargetURL, c.ServiceID, servicelog.StreamStdout)
+		stderr := logs.Writer(c.NotificationTargetURL, c.ServiceID, servicelog.StreamStderr)
+		if tty {
+			_, err = io.Copy(stdout, out)
+		} else {
+			_, err = stdcopy.StdCopy(stdout, stderr, out)
+		}
+		if err != nil {
+			log.Println(logPrefix, err.Error())
+		}
+		stdout.Close()
+		stderr.Close()
+	}()
+
+	return done
+}
+
+// obtainImage pulls the image by policy with the credential of its registry, the image on the device is used
+// without pulling unless the policy is Always
+func (c ContainerExecutor) obtainImage(image string, policy string) error {
+	if policy != containerspec.PullAlways {
+		exists, err := c.ceImplIns.ImageExists(image)
+		if err != nil {
+			return err
+		} else if exists {
+			return nil
+		} else if policy == containerspec.PullNever {
+			return errors.New("image " + image + " is not on the device and its pull policy is " + policy)
+		}
+	}
+
+	auth, err := registryAuth(c.registryCreds, image)
+	if err != nil {
+		return err
+	}
+
+	log.Println(logPrefix, "pull image :", image)
+	var notified time.Time
+	return c.ceImplIns.ImagePull(image, auth, func(progress string) {
+		if time.Since(notified) < pullProgressInterval {
+			return
+		}
+		notified = time.Now()
+		if err := c.NotifyPulling(c.ServiceExecutionInfo, progress); err != nil {
+			log.Println(logPrefix, err.Error())
+		}
+	})
+}
+
+// notifyFailure notifies the requester that the service is failed before running
+func (c ContainerExecutor) notifyFailure(reason string, startTime time.Time) {
+	servicelog.GetInstance().Close(c.NotificationTargetURL, c.ServiceID)
+	c.NotifyResult(c.ServiceExecutionInfo, executor.Ended(notification.ExecutionResult{
+		ServiceID: c.ServiceID,
+		Status:    servicemgr.ConstServiceStatusFailed,
+		Reason:    reason,
+	}, startTime))
+}
+
+func (c ContainerExecutor) removeContainer(id string) {
+	if err := c.ceImplIns.Remove(id); err != nil {
+		log.Println(logPrefix, err.Error())
+	}
+}
+
+// findContainer returns the container of serviceID started by Execute or found by the label of service ID
+func (c ContainerExecutor) findContainer(serviceID uint64) (string, error) {
+	containerMtx.Lock()
+	running, ok := runningContainers[serviceID]
+	containerMtx.Unlock()
+	if ok && len(running.containerID) != 0 {
+		return running.containerID, nil
+	}
+
+	services, err := c.List()
+	if err != nil {
+		return "", err
+	}
+	for _, service := range services {
+		if service.ServiceID == serviceID {
+			return service.ContainerID, nil
+		}
+	}
+	return "", errors.New("container of service " + strconv.FormatUint(serviceID, 10) + " is not running")
+}
+
+// watchEvents starts to receive events of containers if not started or stopped by error
+func (c ContainerExecutor) watchEvents() {
+	containerMtx.Lock()
+	defer containerMtx.Unlock()
+
+	if watchingEvents {
+		return
+	}
+	watchingEvents = true
+
+	msgs, errs := c.ceImplIns.Events()
+	go func() {
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					stopWatchingEvents()
+					return
+				}
+				handleEvent(msg)
+			case err := <-errs:
+				if err != nil {
+					log.Println(logPrefix, "[watchEvents]", err.Error())
+				}
+				stopWatchingEvents()
+				return
+			}
+		}
+	}()
+}
+
+func stopWatchingEvents() {
+	containerMtx.Lock()
+	defer containerMtx.Unlock()
+
+	watchingEvents = false
+}
+
+// handleEvent marks the running container as killed if it is killed not by Cancel, its deadline or stopping orchestration
+func handleEvent(msg events.Message) {
+	if msg.Type != events.ContainerEventType {
+		return
+	}
+	if msg.Action != "kill" && msg.Action != "oom" {
+		return
+	}
+
+	containerMtx.Lock()
+	defer containerMtx.Unlock()
+
+	for serviceID, running := range runningContainers {
+		if running.containerID != msg.Actor.ID || running.canceled || running.timedOut || running.terminated {
+			continue
+		}
+		log.Println(logPrefix, "[handleEvent]", serviceID, msg.Actor.ID, msg.Action)
+		running.killed = true
+	}
+}
+
+func addRunningContainer(serviceID uint64, containerID string) {
+	containerMtx.Lock()
+	defer containerMtx.Unlock()
+
+	runningContainers[serviceID] = &runningContainer{containerID: containerID}
+}
+
+func removeRunningContainer(serviceID uint64) {
+	containerMtx.Lock()
+	defer containerMtx.Unlock()
+
+	delete(runningContainers, serviceID)
+}
+
+func getStoppedReason(serviceID uint64) (canceled bool, timedOut bool, terminated bool, killed bool) {
+	containerMtx.Lock()
+	defer containerMtx.Unlock()
+
+	if running, ok := runningContainers[serviceID]; ok {
+		canceled, timedOut, terminated, killed = running.canceled, running.timedOut, running.terminated, running.killed
+	}
+	return
+}
+
+// exitStatus gives the exit code of container and the signal if the exit code means
+// the container is terminated by a signal (128 + the signal number)
+func exitStatus(statusCode int64) (exitCode int, signal string) {
+	exitCode = int(statusCode)
+	if exitCode > 128 && exitCode < 128+65 {
+		signal = syscall.Signal(exitCode - 128).String()
+	}
+	return
+}
+
+// setServiceLabels labels the container with the service ID and name to find it by service
+func setServiceLabels(conf *container.Config, s executor.ServiceExecutionInfo) {
+	if conf == nil {
+		return
+	}
+	if conf.Labels == nil {
+		conf.Labels = make(map[string]string)
+	}
+	conf.Labels[ConstLabelServiceID] = strconv.FormatUint(s.ServiceID, 10)
+	conf.Labels[ConstLabelServiceName] = s.ServiceName
+}
+
 // SetCEImpl sets executor implementation
 func (c *ContainerExecutor) SetCEImpl(ce CEImpl) {
 	c.ceImplIns = ce
@@ -141,7 +519,11 @@ func convertConfig(paramStr []string) (
 	param := paramStr[2 : paramLen-1]
 	flags.Parse(param)
 
-	conf, _ := parse(flags, copts)
+	conf, err := parse(flags, copts)
+	if err != nil {
+		log.Println(logPrefix, "configuration parsing error :", err)
+		return
+	}
 
 	// @Note : Convert API is called with protect API (for panic handling)
 	protect(func() {
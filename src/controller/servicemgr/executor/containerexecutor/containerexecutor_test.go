@@ -18,12 +18,15 @@
 package containerexecutor
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"reflect"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -31,16 +34,22 @@ import (
 
 	"docker.io/go-docker/api/types/blkiodev"
 
+	"controller/servicemgr"
 	"controller/servicemgr/executor"
 	"controller/servicemgr/executor/containerexecutor/mocks"
+	"controller/servicemgr/executor/containerspec"
+	"controller/servicemgr/notification"
 	notificationMock "controller/servicemgr/notification/mocks"
+	"controller/servicemgr/servicelog"
 	clientApiMock "restinterface/client/mocks"
 
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-units"
 	gomock "github.com/golang/mock/gomock"
 
 	"docker.io/go-docker/api/types"
 	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/events"
 )
 
 var (
@@ -68,6 +77,9 @@ var (
 	statusChan = make(chan container.ContainerWaitOKBody)
 	errCh      = make(chan error)
 
+	eventCh    = make(chan events.Message)
+	eventErrCh = make(chan error)
+
 	reader     = strings.NewReader("Hello,World")
 	readCloser = ioutil.NopCloser(reader)
 )
@@ -104,13 +116,14 @@ func TestExecute(t *testing.T) {
 	cExecutor := GetInstance()
 	con, noti, _ := initializeMock(t)
 
+	con.EXPECT().Events().Return(eventCh, eventErrCh).AnyTimes()
+	con.EXPECT().Logs(containerID).Return(readCloser, nil)
 	gomock.InOrder(
-		con.EXPECT().ImagePull(gomock.Any()).Return(nil),
+		con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 		con.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(resp, nil),
 		con.EXPECT().Start(containerID).Return(nil),
 		con.EXPECT().Wait(containerID, container.WaitConditionNotRunning).Return(statusChan, errCh),
-		con.EXPECT().Logs(containerID).Return(readCloser, nil),
-		noti.EXPECT().InvokeNotification(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes(),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).AnyTimes(),
 		con.EXPECT().Remove(containerID),
 	)
 
@@ -135,10 +148,19 @@ func TestExecuteFailedStartInvokedError(t *testing.T) {
 	cExecutor := GetInstance()
 	con, noti, _ := initializeMock(t)
 
+	con.EXPECT().Events().Return(eventCh, eventErrCh).AnyTimes()
 	gomock.InOrder(
-		con.EXPECT().ImagePull(gomock.Any()).Return(nil),
+		con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 		con.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(resp, nil),
 		con.EXPECT().Start(containerID).Return(errors.New("invoked error")),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusFailed || result.Reason != "start failed : invoked error" {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			}),
+		con.EXPECT().Remove(containerID),
 	)
 
 	// cExecutor.SetClient(client)
@@ -164,13 +186,14 @@ func TestExecuteWaitInvokedError(t *testing.T) {
 	cExecutor := GetInstance()
 	con, noti, _ := initializeMock(t)
 
+	con.EXPECT().Events().Return(eventCh, eventErrCh).AnyTimes()
+	con.EXPECT().Logs(containerID).Return(readCloser, nil)
 	gomock.InOrder(
-		con.EXPECT().ImagePull(gomock.Any()).Return(nil),
+		con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 		con.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(resp, nil),
 		con.EXPECT().Start(containerID).Return(nil),
 		con.EXPECT().Wait(containerID, container.WaitConditionNotRunning).Return(statusChan, errCh),
-		con.EXPECT().Logs(containerID).Return(readCloser, nil),
-		noti.EXPECT().InvokeNotification(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes(),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).AnyTimes(),
 		con.EXPECT().Remove(containerID),
 	)
 
@@ -192,6 +215,406 @@ func TestExecuteWaitInvokedError(t *testing.T) {
 	wait.Wait()
 }
 
+func expectStatus(t *testing.T, status string) func(result notification.ExecutionResult) {
+	return func(result notification.ExecutionResult) {
+		if result.Status != status {
+			t.Error("unexpected status : ", result.Status)
+		}
+	}
+}
+
+func executeUntilWait(t *testing.T, con *mocks.MockCEImpl, noti *notificationMock.MockNotification, check func(result notification.ExecutionResult)) (chan<- container.ContainerWaitOKBody, <-chan struct{}, *sync.WaitGroup) {
+	t.Helper()
+
+	waitStatusCh := make(chan container.ContainerWaitOKBody, 1)
+	waitErrCh := make(chan error, 1)
+	waiting := make(chan struct{})
+
+	con.EXPECT().Events().Return(eventCh, eventErrCh).AnyTimes()
+	con.EXPECT().Logs(containerID).Return(ioutil.NopCloser(strings.NewReader("")), nil)
+	gomock.InOrder(
+		con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		con.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(conf *container.Config, hostConf *container.HostConfig, networkConf interface{}) (container.ContainerCreateCreatedBody, error) {
+				if conf.Labels[ConstLabelServiceID] != "1" || conf.Labels[ConstLabelServiceName] != serviceInfo.ServiceName {
+					t.Error("unexpected labels : ", conf.Labels)
+				}
+				return resp, nil
+			}),
+		con.EXPECT().Start(containerID).Return(nil),
+		con.EXPECT().Wait(containerID, container.WaitConditionNotRunning).DoAndReturn(
+			func(id string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+				close(waiting)
+				return waitStatusCh, waitErrCh
+			}),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.ServiceID != serviceInfo.ServiceID || result.StartTime.IsZero() || result.EndTime.Before(result.StartTime) {
+					t.Error("unexpected result : ", result)
+				}
+				check(result)
+				return nil
+			}),
+		con.EXPECT().Remove(containerID),
+	)
+
+	cExecutor := GetInstance()
+	cExecutor.SetCEImpl(con)
+	cExecutor.SetNotiImpl(noti)
+
+	var wait sync.WaitGroup
+	wait.Add(1)
+	go func() {
+		cExecutor.Execute(serviceInfo)
+		wait.Done()
+	}()
+
+	return waitStatusCh, waiting, &wait
+}
+
+func TestExecuteLogs(t *testing.T) {
+	con, noti, _ := initializeMock(t)
+
+	var out bytes.Buffer
+	fmt.Fprintln(stdcopy.NewStdWriter(&out, stdcopy.Stdout), "hello")
+	fmt.Fprint(stdcopy.NewStdWriter(&out, stdcopy.Stderr), "world")
+
+	waitStatusCh := make(chan container.ContainerWaitOKBody, 1)
+	waitStatusCh <- container.ContainerWaitOKBody{StatusCode: 0}
+
+	con.EXPECT().Events().Return(eventCh, eventErrCh).AnyTimes()
+	con.EXPECT().Logs(containerID).Return(ioutil.NopCloser(&out), nil)
+	gomock.InOrder(
+		con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		con.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(resp, nil),
+		con.EXPECT().Start(containerID).Return(nil),
+		con.EXPECT().Wait(containerID, container.WaitConditionNotRunning).Return(waitStatusCh, make(chan error)),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				// @Note : log is captured before the requester is notified
+				chunk, err := servicelog.GetInstance().Read(serviceInfo.NotificationTargetURL, serviceInfo.ServiceID, 0, 0)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if !chunk.Finished || len(chunk.Lines) != 2 ||
+					chunk.Lines[0].Stream != servicelog.StreamStdout || chunk.Lines[0].Text != "hello" ||
+					chunk.Lines[1].Stream != servicelog.StreamStderr || chunk.Lines[1].Text != "world" {
+					t.Error("unexpected log : ", chunk)
+				}
+				return nil
+			}),
+		con.EXPECT().Remove(containerID),
+	)
+
+	GetInstance().SetCEImpl(con)
+	GetInstance().SetNotiImpl(noti)
+	if err := GetInstance().Execute(serviceInfo); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Run("Running", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+
+		statusCh, waiting, wait := executeUntilWait(t, con, noti, expectStatus(t, servicemgr.ConstServiceStatusCanceled))
+		<-waiting
+
+		con.EXPECT().Stop(containerID, gomock.Any()).DoAndReturn(
+			func(id string, timeout *time.Duration) error {
+				if timeout == nil || *timeout != stopTimeout {
+					t.Error("unexpected timeout")
+				}
+				// @Note : docker sends kill event on stopping container
+				eventCh <- events.Message{Type: events.ContainerEventType, Action: "kill", Actor: events.Actor{ID: containerID}}
+				statusCh <- container.ContainerWaitOKBody{StatusCode: 137}
+				return nil
+			})
+
+		if err := GetInstance().Cancel(serviceInfo.ServiceID); err != nil {
+			t.Error(err.Error())
+		}
+		wait.Wait()
+	})
+	t.Run("FoundByLabel", func(t *testing.T) {
+		con, _, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+
+		con.EXPECT().PS().Return([]types.Container{
+			{ID: "other", Labels: map[string]string{ConstLabelServiceID: "6"}},
+			{ID: containerID, Labels: map[string]string{ConstLabelServiceID: "7"}},
+		}, nil)
+		con.EXPECT().Stop(containerID, gomock.Any()).Return(nil)
+
+		if err := GetInstance().Cancel(7); err != nil {
+			t.Error(err.Error())
+		}
+	})
+	t.Run("NotRunning", func(t *testing.T) {
+		con, _, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+
+		con.EXPECT().PS().Return(containerList, nil)
+
+		if err := GetInstance().Cancel(7); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestList(t *testing.T) {
+	con, _, _ := initializeMock(t)
+	GetInstance().SetCEImpl(con)
+
+	t.Run("Success", func(t *testing.T) {
+		con.EXPECT().PS().Return([]types.Container{
+			{ID: containerID, Image: "alpine", State: "running", Status: "Up 1 second",
+				Labels: map[string]string{ConstLabelServiceID: "3", ConstLabelServiceName: "alpine"}},
+			{ID: "unmanaged", Image: "alpine"},
+		}, nil)
+
+		services, err := GetInstance().List()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		expected := []ContainerService{
+			{ServiceID: 3, ServiceName: "alpine", ContainerID: containerID, Image: "alpine", State: "running", Status: "Up 1 second"},
+		}
+		if !reflect.DeepEqual(services, expected) {
+			t.Error("unexpected services : ", services)
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		con.EXPECT().PS().Return(nil, errors.New("ps error"))
+
+		if _, err := GetInstance().List(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestKilledOutside(t *testing.T) {
+	con, noti, _ := initializeMock(t)
+
+	statusCh, waiting, wait := executeUntilWait(t, con, noti, expectStatus(t, servicemgr.ConstServiceStatusKilled))
+	<-waiting
+
+	eventCh <- events.Message{Type: events.ContainerEventType, Action: "kill", Actor: events.Actor{ID: containerID}}
+	for i := 0; i < 100; i++ {
+		if _, _, _, killed := getStoppedReason(serviceInfo.ServiceID); killed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	statusCh <- container.ContainerWaitOKBody{StatusCode: 137}
+	wait.Wait()
+}
+
+func TestExecuteTimedOut(t *testing.T) {
+	con, noti, _ := initializeMock(t)
+
+	serviceInfo.Timeout = 10 * time.Millisecond
+	defer func() {
+		serviceInfo.Timeout = 0
+	}()
+
+	stopping := make(chan struct{})
+	con.EXPECT().Stop(containerID, gomock.Any()).DoAndReturn(
+		func(id string, timeout *time.Duration) error {
+			if timeout == nil || *timeout != executor.TerminationGracePeriod {
+				t.Error("unexpected timeout")
+			}
+			close(stopping)
+			return nil
+		})
+
+	statusCh, _, wait := executeUntilWait(t, con, noti, func(result notification.ExecutionResult) {
+		if result.Status != servicemgr.ConstServiceStatusTimedOut || result.Signal != "terminated" {
+			t.Error("unexpected result : ", result)
+		}
+		if !strings.HasPrefix(result.Reason, executor.ReasonTimedOut) {
+			t.Error("unexpected reason : ", result.Reason)
+		}
+	})
+	<-stopping
+
+	// @Note : docker sends kill event on stopping container
+	eventCh <- events.Message{Type: events.ContainerEventType, Action: "kill", Actor: events.Actor{ID: containerID}}
+	statusCh <- container.ContainerWaitOKBody{StatusCode: 143}
+	wait.Wait()
+}
+
+func TestExecuteTerminated(t *testing.T) {
+	con, noti, _ := initializeMock(t)
+
+	stopping := make(chan struct{})
+	con.EXPECT().Stop(containerID, gomock.Any()).DoAndReturn(
+		func(id string, timeout *time.Duration) error {
+			close(stopping)
+			return nil
+		})
+
+	statusCh, waiting, wait := executeUntilWait(t, con, noti, func(result notification.ExecutionResult) {
+		if result.Status != servicemgr.ConstServiceStatusTerminated || result.Reason != executor.ReasonTerminated {
+			t.Error("unexpected result : ", result)
+		}
+	})
+	<-waiting
+
+	executor.StopServices()
+	<-stopping
+
+	eventCh <- events.Message{Type: events.ContainerEventType, Action: "kill", Actor: events.Actor{ID: containerID}}
+	statusCh <- container.ContainerWaitOKBody{StatusCode: 143}
+	wait.Wait()
+}
+
+func TestExecuteResult(t *testing.T) {
+	t.Run("Finished", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+
+		statusCh, _, wait := executeUntilWait(t, con, noti, func(result notification.ExecutionResult) {
+			if result.Status != servicemgr.ConstServiceStatusFinished || result.ExitCode != 0 || result.Signal != "" {
+				t.Error("unexpected result : ", result)
+			}
+		})
+
+		statusCh <- container.ContainerWaitOKBody{StatusCode: 0}
+		wait.Wait()
+	})
+	t.Run("ExitCode", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+
+		statusCh, _, wait := executeUntilWait(t, con, noti, func(result notification.ExecutionResult) {
+			if result.Status != servicemgr.ConstServiceStatusFailed || result.ExitCode != 2 || result.Signal != "" {
+				t.Error("unexpected result : ", result)
+			}
+		})
+
+		statusCh <- container.ContainerWaitOKBody{StatusCode: 2}
+		wait.Wait()
+	})
+	t.Run("Signal", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+
+		statusCh, _, wait := executeUntilWait(t, con, noti, func(result notification.ExecutionResult) {
+			if result.Status != servicemgr.ConstServiceStatusFailed || result.ExitCode != 143 || result.Signal != syscall.SIGTERM.String() {
+				t.Error("unexpected result : ", result)
+			}
+		})
+
+		statusCh <- container.ContainerWaitOKBody{StatusCode: 143}
+		wait.Wait()
+	})
+	t.Run("CreateFailed", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+		GetInstance().SetNotiImpl(noti)
+
+		expectReason := func(reason string) {
+			noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(target string, result notification.ExecutionResult) error {
+					if result.Status != servicemgr.ConstServiceStatusFailed || result.Reason != reason {
+						t.Error("unexpected result : ", result)
+					}
+					return nil
+				})
+		}
+
+		gomock.InOrder(
+			con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+			con.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(container.ContainerCreateCreatedBody{}, errors.New("no such image")),
+		)
+		expectReason("create failed : no such image")
+		if err := GetInstance().Execute(serviceInfo); err == nil {
+			t.Error("expected error")
+		}
+
+		con.EXPECT().ImagePull(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("unauthorized"))
+		expectReason("pull failed : unauthorized")
+		if err := GetInstance().Execute(serviceInfo); err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("InvalidConfig", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+		GetInstance().SetNotiImpl(noti)
+
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusFailed || !strings.HasPrefix(result.Reason, executor.ReasonInvalidConfig) {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			})
+
+		invalid := serviceInfo
+		invalid.ParamStr = []string{`{"Image": ""}`}
+		if err := GetInstance().Execute(invalid); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestObtainImage(t *testing.T) {
+	t.Run("IfNotPresent", func(t *testing.T) {
+		con, _, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+
+		con.EXPECT().ImageExists("nginx:1.17").Return(true, nil)
+		if err := GetInstance().obtainImage("nginx:1.17", containerspec.PullIfNotPresent); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+	})
+	t.Run("Pulling", func(t *testing.T) {
+		con, noti, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+		GetInstance().SetNotiImpl(noti)
+
+		gomock.InOrder(
+			con.EXPECT().ImageExists("nginx:1.17").Return(false, nil),
+			con.EXPECT().ImagePull("nginx:1.17", "", gomock.Any()).DoAndReturn(
+				func(image string, auth string, progress func(string)) error {
+					progress("Pulling fs layer")
+					progress("Downloading")
+					return nil
+				}),
+		)
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusPulling || result.Progress != "Pulling fs layer" {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			}).Times(1)
+
+		if err := GetInstance().obtainImage("nginx:1.17", containerspec.PullIfNotPresent); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+	})
+	t.Run("Never", func(t *testing.T) {
+		con, _, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+
+		con.EXPECT().ImageExists("nginx:1.17").Return(false, nil)
+		if err := GetInstance().obtainImage("nginx:1.17", containerspec.PullNever); err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("Always", func(t *testing.T) {
+		con, _, _ := initializeMock(t)
+		GetInstance().SetCEImpl(con)
+
+		con.EXPECT().ImagePull("nginx", "", gomock.Any()).Return(errors.New("manifest unknown"))
+		if err := GetInstance().obtainImage("nginx", containerspec.PullAlways); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
 func TestSuccessConvertConfigWithAttach(t *testing.T) {
 	validStr := []string{"docker", "run", "-a", "stdin", "-a", "stdout", "-a", "stderr", imageName}
 	container, _, _ := convertConfig(validStr)
@@ -5,11 +5,14 @@
 package mocks
 
 import (
+	types "docker.io/go-docker/api/types"
 	container "docker.io/go-docker/api/types/container"
+	events "docker.io/go-docker/api/types/events"
 	network "docker.io/go-docker/api/types/network"
 	gomock "github.com/golang/mock/gomock"
 	io "io"
 	reflect "reflect"
+	time "time"
 )
 
 // MockCEImpl is a mock of CEImpl interface
@@ -109,15 +112,88 @@ func (mr *MockCEImplMockRecorder) Logs(id interface{}) *gomock.Call {
 }
 
 // ImagePull mocks base method
-func (m *MockCEImpl) ImagePull(image string) error {
+func (m *MockCEImpl) ImagePull(image, registryAuth string, progress func(string)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ImagePull", image)
+	ret := m.ctrl.Call(m, "ImagePull", image, registryAuth, progress)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ImagePull indicates an expected call of ImagePull
-func (mr *MockCEImplMockRecorder) ImagePull(image interface{}) *gomock.Call {
+func (mr *MockCEImplMockRecorder) ImagePull(image, registryAuth, progress interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePull", reflect.TypeOf((*MockCEImpl)(nil).ImagePull), image)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePull", reflect.TypeOf((*MockCEImpl)(nil).ImagePull), image, registryAuth, progress)
+}
+
+// ImageExists mocks base method
+func (m *MockCEImpl) ImageExists(image string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageExists", image)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageExists indicates an expected call of ImageExists
+func (mr *MockCEImplMockRecorder) ImageExists(image interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageExists", reflect.TypeOf((*MockCEImpl)(nil).ImageExists), image)
+}
+
+// PS mocks base method
+func (m *MockCEImpl) PS() ([]types.Container, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PS")
+	ret0, _ := ret[0].([]types.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PS indicates an expected call of PS
+func (mr *MockCEImplMockRecorder) PS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PS", reflect.TypeOf((*MockCEImpl)(nil).PS))
+}
+
+// Stop mocks base method
+func (m *MockCEImpl) Stop(id string, timeout *time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", id, timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockCEImplMockRecorder) Stop(id, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockCEImpl)(nil).Stop), id, timeout)
+}
+
+// Events mocks base method
+func (m *MockCEImpl) Events() (<-chan events.Message, <-chan error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].(<-chan events.Message)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events
+func (mr *MockCEImplMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockCEImpl)(nil).Events))
+}
+
+// ImageTag mocks base method
+func (m *MockCEImpl) ImageTag(source, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageTag", source, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImageTag indicates an expected call of ImageTag
+func (mr *MockCEImplMockRecorder) ImageTag(source, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageTag", reflect.TypeOf((*MockCEImpl)(nil).ImageTag), source, target)
 }
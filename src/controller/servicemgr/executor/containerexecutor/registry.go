@@ -0,0 +1,139 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package containerexecutor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+)
+
+// dockerHub is the registry of images without the registry in their name
+const dockerHub = "docker.io"
+
+// RegistryCredential is the credential of a registry, Auth is base64 encoded "username:password"
+type RegistryCredential struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// registryCredentials is the credentials file in the format of docker config.json
+type registryCredentials struct {
+	Auths map[string]RegistryCredential `json:"auths"`
+}
+
+// encodedAuth is the auth given to docker to pull an image
+type encodedAuth struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	ServerAddress string `json:"serveraddress,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// ReadRegistryCredentials reads the credentials of registries from the file in the format of docker config.json,
+// the credentials are keyed by the registry host
+func ReadRegistryCredentials(path string) (map[string]RegistryCredential, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds registryCredentials
+	if err = json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]RegistryCredential)
+	for registry, cred := range creds.Auths {
+		ret[registryHost(registry)] = cred
+	}
+	return ret, nil
+}
+
+// SetRegistryCredentials sets the credentials used to pull images from private registries
+func (c *ContainerExecutor) SetRegistryCredentials(creds map[string]RegistryCredential) {
+	c.registryCreds = creds
+}
+
+// registryAuth gives the encoded auth of the registry of image, it is empty if there is no credential
+func registryAuth(creds map[string]RegistryCredential, image string) (string, error) {
+	registry := imageRegistry(image)
+	cred, ok := creds[registry]
+	if !ok {
+		return "", nil
+	}
+
+	auth := encodedAuth{
+		Username:      cred.Username,
+		Password:      cred.Password,
+		ServerAddress: registry,
+		IdentityToken: cred.IdentityToken,
+	}
+	if len(cred.Auth) != 0 {
+		decoded, err := base64.StdEncoding.DecodeString(cred.Auth)
+		if err != nil {
+			return "", errors.New("invalid auth of registry " + registry)
+		}
+		pair := strings.SplitN(string(decoded), ":", 2)
+		if len(pair) != 2 {
+			return "", errors.New("invalid auth of registry " + registry)
+		}
+		auth.Username, auth.Password = pair[0], pair[1]
+	}
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// imageRegistry gives the registry host in the name of image, Docker Hub is the registry if it is not given
+func imageRegistry(image string) string {
+	idx := strings.Index(image, "/")
+	if idx < 0 {
+		return dockerHub
+	}
+	host := image[:idx]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return dockerHub
+	}
+	return registryHost(host)
+}
+
+// registryHost removes the scheme and the path from the registry address of credentials file,
+// the addresses of Docker Hub are given as docker.io
+func registryHost(registry string) string {
+	host := registry
+	if idx := strings.Index(host, "://"); idx >= 0 {
+		host = host[idx+3:]
+	}
+	if idx := strings.Index(host, "/"); idx >= 0 {
+		host = host[:idx]
+	}
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHub
+	}
+	return host
+}
@@ -0,0 +1,99 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package containerexecutor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadRegistryCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "auth.json")
+	content := `{"auths": {
+		"https://index.docker.io/v1/": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("hub:secret")) + `"},
+		"registry.example.com:5000": {"username": "edge", "password": "pass"}
+	}}`
+	if err = ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	creds, err := ReadRegistryCredentials(path)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	decode := func(encoded string) (auth encodedAuth) {
+		data, err := base64.URLEncoding.DecodeString(encoded)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if err = json.Unmarshal(data, &auth); err != nil {
+			t.Fatal(err.Error())
+		}
+		return
+	}
+
+	t.Run("DockerHub", func(t *testing.T) {
+		encoded, err := registryAuth(creds, "library/alpine")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if auth := decode(encoded); auth.Username != "hub" || auth.Password != "secret" || auth.ServerAddress != dockerHub {
+			t.Error("unexpected auth : ", auth)
+		}
+	})
+	t.Run("Private", func(t *testing.T) {
+		encoded, err := registryAuth(creds, "registry.example.com:5000/camera:v2")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if auth := decode(encoded); auth.Username != "edge" || auth.Password != "pass" {
+			t.Error("unexpected auth : ", auth)
+		}
+	})
+	t.Run("NoCredential", func(t *testing.T) {
+		if encoded, err := registryAuth(creds, "localhost:5000/camera"); err != nil || len(encoded) != 0 {
+			t.Error("unexpected auth : ", encoded, err)
+		}
+	})
+}
+
+func TestImageRegistry(t *testing.T) {
+	registries := map[string]string{
+		"alpine":                       dockerHub,
+		"edge/camera:v1":               dockerHub,
+		"localhost/camera":             "localhost",
+		"registry.example.com/a/b:1.0": "registry.example.com",
+		"10.0.0.1:5000/camera":         "10.0.0.1:5000",
+	}
+	for image, expected := range registries {
+		if registry := imageRegistry(image); registry != expected {
+			t.Error("unexpected registry of", image, ":", registry)
+		}
+	}
+}
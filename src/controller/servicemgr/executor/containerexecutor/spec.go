@@ -0,0 +1,114 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package containerexecutor
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/mount"
+	"docker.io/go-docker/api/types/network"
+	"docker.io/go-docker/api/types/strslice"
+	"github.com/docker/go-connections/nat"
+
+	"controller/servicemgr/executor/containerspec"
+)
+
+// containerRun has the configurations of container and the pull policy of its image
+type containerRun struct {
+	conf        *container.Config
+	hostConf    *container.HostConfig
+	networkConf *network.NetworkingConfig
+	pullPolicy  string
+}
+
+// parseContainerRun gives the configurations of container from the arguments of service,
+// the arguments are a container spec or docker run arguments which are kept for compatibility
+func parseContainerRun(paramStr []string) (run containerRun, err error) {
+	spec, ok, err := containerspec.Parse(paramStr)
+	if err != nil {
+		return
+	} else if ok {
+		run.conf, run.hostConf, run.networkConf = convertSpec(spec)
+		run.pullPolicy = spec.PullPolicy
+	} else if len(paramStr) < 3 || paramStr[0] != "docker" || paramStr[1] != "run" {
+		return run, errors.New("arguments are neither container spec nor docker run")
+	} else if run.conf, run.hostConf, run.networkConf = convertConfig(paramStr); run.conf == nil || run.hostConf == nil {
+		return run, errors.New("invalid docker run arguments")
+	}
+
+	if len(run.pullPolicy) == 0 {
+		run.pullPolicy = containerspec.DefaultPullPolicy(run.conf.Image)
+	}
+	return run, nil
+}
+
+// convertSpec converts the validated spec to the configurations of container
+func convertSpec(spec containerspec.Spec) (
+	containerConf *container.Config, hostConf *container.HostConfig, networkConf *network.NetworkingConfig) {
+
+	containerConf = &container.Config{
+		Image: spec.Image,
+		Cmd:   strslice.StrSlice(spec.Command),
+	}
+	hostConf = &container.HostConfig{}
+	networkConf = &network.NetworkingConfig{}
+
+	keys := make([]string, 0, len(spec.Env))
+	for key := range spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		containerConf.Env = append(containerConf.Env, key+"="+spec.Env[key])
+	}
+
+	for _, m := range spec.Mounts {
+		hostConf.Mounts = append(hostConf.Mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   m.Source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	if len(spec.Ports) != 0 {
+		containerConf.ExposedPorts = make(nat.PortSet)
+		hostConf.PortBindings = make(nat.PortMap)
+	}
+	for _, p := range spec.Ports {
+		protocol := p.Protocol
+		if len(protocol) == 0 {
+			protocol = containerspec.ProtocolTCP
+		}
+		port := nat.Port(strconv.Itoa(p.ContainerPort) + "/" + protocol)
+		binding := nat.PortBinding{}
+		if p.HostPort != 0 {
+			binding.HostPort = strconv.Itoa(p.HostPort)
+		}
+		containerConf.ExposedPorts[port] = struct{}{}
+		hostConf.PortBindings[port] = append(hostConf.PortBindings[port], binding)
+	}
+
+	hostConf.Resources.Memory = spec.Resources.Memory
+	hostConf.Resources.NanoCPUs = int64(spec.Resources.CPUs * 1e9)
+
+	return
+}
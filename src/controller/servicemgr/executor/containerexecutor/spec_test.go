@@ -0,0 +1,86 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package containerexecutor
+
+import (
+	"reflect"
+	"testing"
+
+	"docker.io/go-docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+
+	"controller/servicemgr/executor/containerspec"
+)
+
+func TestConvertSpec(t *testing.T) {
+	spec := containerspec.Spec{
+		Image:     "nginx",
+		Command:   []string{"nginx", "-g", "daemon off;"},
+		Env:       map[string]string{"B": "2", "A": "1"},
+		Mounts:    []containerspec.Mount{{Source: "/var/www", Target: "/www", ReadOnly: true}},
+		Ports:     []containerspec.Port{{ContainerPort: 80, HostPort: 8080}, {ContainerPort: 53, Protocol: containerspec.ProtocolUDP}},
+		Resources: containerspec.Resources{CPUs: 1.5, Memory: 1024 * 1024 * 64},
+	}
+
+	containerConf, hostConf, networkConf := convertSpec(spec)
+	if containerConf.Image != spec.Image || !reflect.DeepEqual([]string(containerConf.Cmd), spec.Command) {
+		t.Error("unexpected container config : ", containerConf)
+	}
+	if !reflect.DeepEqual(containerConf.Env, []string{"A=1", "B=2"}) {
+		t.Error("unexpected env : ", containerConf.Env)
+	}
+	if _, ok := containerConf.ExposedPorts["53/udp"]; !ok || len(containerConf.ExposedPorts) != 2 {
+		t.Error("unexpected exposed ports : ", containerConf.ExposedPorts)
+	}
+	if bindings := hostConf.PortBindings["80/tcp"]; !reflect.DeepEqual(bindings, []nat.PortBinding{{HostPort: "8080"}}) {
+		t.Error("unexpected port bindings : ", hostConf.PortBindings)
+	}
+	expectedMounts := []mount.Mount{{Type: mount.TypeBind, Source: "/var/www", Target: "/www", ReadOnly: true}}
+	if !reflect.DeepEqual(hostConf.Mounts, expectedMounts) {
+		t.Error("unexpected mounts : ", hostConf.Mounts)
+	}
+	if hostConf.Resources.NanoCPUs != 1500000000 || hostConf.Resources.Memory != spec.Resources.Memory {
+		t.Error("unexpected resources : ", hostConf.Resources)
+	}
+	if networkConf == nil {
+		t.Error("unexpected nil network config")
+	}
+}
+
+func TestContainerConfig(t *testing.T) {
+	t.Run("Spec", func(t *testing.T) {
+		args, _ := containerspec.Spec{Image: "hello-world:1.0", PullPolicy: containerspec.PullNever}.Args()
+		run, err := parseContainerRun(args)
+		if err != nil || run.conf.Image != "hello-world:1.0" || run.pullPolicy != containerspec.PullNever {
+			t.Error("unexpected result : ", run, err)
+		}
+	})
+	t.Run("DockerRun", func(t *testing.T) {
+		run, err := parseContainerRun([]string{"docker", "run", "hello-world"})
+		if err != nil || run.conf.Image != "hello-world" || run.pullPolicy != containerspec.PullAlways {
+			t.Error("unexpected result : ", run, err)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, args := range [][]string{nil, {"hello-world"}, {`{"Image": "hello world"}`}} {
+			if _, err := parseContainerRun(args); err == nil {
+				t.Error("unexpected success : ", args)
+			}
+		}
+	})
+}
@@ -0,0 +1,172 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package containerspec provides the structured specification of container service given by the request
+package containerspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	"common/errors"
+)
+
+// Protocols of published ports
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolUDP  = "udp"
+	ProtocolSCTP = "sctp"
+)
+
+// Pull policies of the image of container
+const (
+	// PullAlways pulls the image on every execution
+	PullAlways = "Always"
+	// PullIfNotPresent pulls the image only if it is not on the device
+	PullIfNotPresent = "IfNotPresent"
+	// PullNever uses the image on the device without pulling it
+	PullNever = "Never"
+)
+
+// Spec is the container of service, it is used instead of docker run arguments
+type Spec struct {
+	// Image is the image of container, it is pulled by the device executing the service
+	Image string
+	// PullPolicy is one of Always, IfNotPresent and Never, it is decided by the tag of Image if it is empty
+	PullPolicy string `json:",omitempty"`
+	// Command replaces the default command of image if it is not empty
+	Command []string `json:",omitempty"`
+	// Env is the environment variables of container
+	Env map[string]string `json:",omitempty"`
+	// Mounts are the paths of device bound to container
+	Mounts []Mount `json:",omitempty"`
+	// Ports are the ports of container published on the device
+	Ports []Port `json:",omitempty"`
+	// Resources limits the resources of device used by container
+	Resources Resources
+}
+
+// Mount binds Source of device to Target of container
+type Mount struct {
+	Source   string
+	Target   string
+	ReadOnly bool `json:",omitempty"`
+}
+
+// Port publishes ContainerPort on HostPort of device, a free port is given if HostPort is 0
+type Port struct {
+	ContainerPort int
+	HostPort      int    `json:",omitempty"`
+	Protocol      string `json:",omitempty"`
+}
+
+// Resources has the limits of container, there is no limit if it is 0
+type Resources struct {
+	// CPUs is the number of CPUs, it can be fractional
+	CPUs float64 `json:",omitempty"`
+	// Memory is the memory in bytes
+	Memory int64 `json:",omitempty"`
+}
+
+// Decode reads the spec from JSON, unknown fields are not allowed to catch mistyped fields
+func Decode(data []byte) (spec Spec, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&spec); err != nil {
+		return spec, errors.InvalidParam{Message: "container spec : " + err.Error()}
+	}
+	return spec, spec.Validate()
+}
+
+// Parse reads the spec from the arguments of service made by Args,
+// ok is false if the arguments are not a spec (e.g. docker run arguments)
+func Parse(args []string) (spec Spec, ok bool, err error) {
+	if len(args) != 1 || !strings.HasPrefix(strings.TrimSpace(args[0]), "{") {
+		return spec, false, nil
+	}
+	spec, err = Decode([]byte(args[0]))
+	return spec, true, err
+}
+
+// Args gives the arguments of service carrying the spec to the device executing it
+func (s Spec) Args() ([]string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(data)}, nil
+}
+
+// Validate checks the spec can be run as a container
+func (s Spec) Validate() error {
+	if len(s.Image) == 0 || strings.ContainsAny(s.Image, " \t\n") {
+		return errors.InvalidParam{Message: "invalid image of container spec : " + s.Image}
+	}
+
+	switch s.PullPolicy {
+	case "", PullAlways, PullIfNotPresent, PullNever:
+	default:
+		return errors.InvalidParam{Message: "unknown pull policy of container spec : " + s.PullPolicy}
+	}
+
+	for key := range s.Env {
+		if len(key) == 0 || strings.Contains(key, "=") {
+			return errors.InvalidParam{Message: "invalid environment variable of container spec : " + key}
+		}
+	}
+
+	for _, mount := range s.Mounts {
+		if !path.IsAbs(mount.Source) || !path.IsAbs(mount.Target) {
+			return errors.InvalidParam{Message: "mount of container spec is not absolute : " + mount.Source + ":" + mount.Target}
+		}
+	}
+
+	for _, port := range s.Ports {
+		if port.ContainerPort < 1 || port.ContainerPort > 65535 || port.HostPort < 0 || port.HostPort > 65535 {
+			return errors.InvalidParam{Message: "invalid port of container spec : " + strconv.Itoa(port.ContainerPort)}
+		}
+		switch port.Protocol {
+		case "", ProtocolTCP, ProtocolUDP, ProtocolSCTP:
+		default:
+			return errors.InvalidParam{Message: "unknown protocol of container spec : " + port.Protocol}
+		}
+	}
+
+	if s.Resources.CPUs < 0 || s.Resources.Memory < 0 {
+		return errors.InvalidParam{Message: "negative resources of container spec"}
+	}
+	return nil
+}
+
+// DefaultPullPolicy gives the pull policy of image without the policy, the image tagged latest or without tag
+// is always pulled to be updated and the other images are pulled only if they are not on the device
+func DefaultPullPolicy(image string) string {
+	name := image
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if strings.Contains(name, "@") {
+		return PullIfNotPresent
+	}
+	if idx := strings.LastIndex(name, ":"); idx < 0 || name[idx+1:] == "latest" {
+		return PullAlways
+	}
+	return PullIfNotPresent
+}
@@ -0,0 +1,100 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package containerspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+var validSpec = Spec{
+	Image:      "nginx:1.17",
+	PullPolicy: PullIfNotPresent,
+	Command:    []string{"nginx", "-g", "daemon off;"},
+	Env:        map[string]string{"MODE": "edge"},
+	Mounts:     []Mount{{Source: "/var/www", Target: "/usr/share/nginx/html", ReadOnly: true}},
+	Ports:      []Port{{ContainerPort: 80, HostPort: 8080}, {ContainerPort: 53, Protocol: ProtocolUDP}},
+	Resources:  Resources{CPUs: 0.5, Memory: 64 * 1024 * 1024},
+}
+
+func TestValidate(t *testing.T) {
+	if err := validSpec.Validate(); err != nil {
+		t.Error("unexpected error : ", err.Error())
+	}
+
+	invalids := map[string]func(s *Spec){
+		"EmptyImage":      func(s *Spec) { s.Image = "" },
+		"UnknownPull":     func(s *Spec) { s.PullPolicy = "Sometimes" },
+		"InvalidEnv":      func(s *Spec) { s.Env = map[string]string{"A=B": "C"} },
+		"RelativeMount":   func(s *Spec) { s.Mounts = []Mount{{Source: "www", Target: "/www"}} },
+		"InvalidPort":     func(s *Spec) { s.Ports = []Port{{ContainerPort: 0}} },
+		"UnknownProtocol": func(s *Spec) { s.Ports = []Port{{ContainerPort: 80, Protocol: "http"}} },
+		"NegativeMemory":  func(s *Spec) { s.Resources.Memory = -1 },
+	}
+	for name, modify := range invalids {
+		t.Run(name, func(t *testing.T) {
+			spec := validSpec
+			modify(&spec)
+			if err := spec.Validate(); err == nil {
+				t.Error("unexpected success")
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Spec", func(t *testing.T) {
+		args, err := validSpec.Args()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		spec, ok, err := Parse(args)
+		if !ok || err != nil {
+			t.Fatal("unexpected result : ", ok, err)
+		}
+		if !reflect.DeepEqual(spec, validSpec) {
+			t.Error("unexpected spec : ", spec)
+		}
+	})
+	t.Run("DockerRun", func(t *testing.T) {
+		if _, ok, err := Parse([]string{"docker", "run", "hello-world"}); ok || err != nil {
+			t.Error("unexpected result : ", ok, err)
+		}
+	})
+	t.Run("UnknownField", func(t *testing.T) {
+		if _, ok, err := Parse([]string{`{"Image": "hello-world", "Volumes": ["/tmp"]}`}); !ok || err == nil {
+			t.Error("unexpected result : ", ok, err)
+		}
+	})
+}
+
+func TestDefaultPullPolicy(t *testing.T) {
+	policies := map[string]string{
+		"hello-world":                         PullAlways,
+		"nginx:latest":                        PullAlways,
+		"localhost:5000/camera":               PullAlways,
+		"nginx:1.17":                          PullIfNotPresent,
+		"localhost:5000/camera:v2":            PullIfNotPresent,
+		"alpine@sha256:0123456789abcdef01234": PullIfNotPresent,
+	}
+	for image, expected := range policies {
+		if policy := DefaultPullPolicy(image); policy != expected {
+			t.Error("unexpected pull policy of", image, ":", policy)
+		}
+	}
+}
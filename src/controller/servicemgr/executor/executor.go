@@ -19,10 +19,52 @@
 package executor
 
 import (
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"common/metrics"
+	"common/types/servicemgrtypes"
 	"controller/servicemgr/notification"
 	"restinterface/client"
 )
 
+const (
+	// ReasonInvalidConfig is the reason of failure when the configuration of container is not valid
+	ReasonInvalidConfig = "invalid configuration"
+	// ReasonPullFailed is the reason of failure when the image of service is not pulled
+	ReasonPullFailed = "pull failed"
+	// ReasonCreateFailed is the reason of failure when the container of service is not created
+	ReasonCreateFailed = "create failed"
+	// ReasonStartFailed is the reason of failure when the service application is not started
+	ReasonStartFailed = "start failed"
+	// ReasonWaitFailed is the reason of failure when the end of service application is not known
+	ReasonWaitFailed = "wait failed"
+	// ReasonTimedOut is the reason of termination when the service application is not ended by its deadline
+	ReasonTimedOut = "timed out"
+	// ReasonTerminated is the reason of termination when orchestration is stopped
+	ReasonTerminated = "orchestration is stopped"
+)
+
+var (
+	logPrefix = "[executor]"
+
+	// TerminationGracePeriod is the time given to a service application to exit after SIGTERM before it is killed
+	TerminationGracePeriod = 10 * time.Second
+
+	// stopping is closed by StopServices and replaced for the services executed after it
+	stoppingMtx sync.Mutex
+	stopping    = make(chan struct{})
+)
+
+var executionsTotal = metrics.NewCounter(
+	"edge_orchestration_executions_total",
+	"Number of service executions by service and outcome status",
+	"service", "status")
+
 // ServiceExecutor interface
 type ServiceExecutor interface {
 	Execute(ServiceExecutionInfo) (err error)
@@ -36,6 +78,10 @@ type ServiceExecutionInfo struct {
 	ServiceName           string
 	ParamStr              []string
 	NotificationTargetURL string
+	// Stdin is the payload given to the standard input of service application
+	Stdin string
+	// Timeout is the deadline of execution, there is no deadline if it is 0
+	Timeout time.Duration
 }
 
 // HasClientNotification struct
@@ -49,3 +95,131 @@ func (c *HasClientNotification) SetClient(clientAPI client.Clienter) {
 	c.Clienter = clientAPI
 	c.NotiImplIns.SetClient(clientAPI)
 }
+
+// CountExecution records status as the outcome of an execution of serviceName
+func CountExecution(serviceName string, status string) {
+	executionsTotal.Inc(serviceName, status)
+}
+
+// NotifyResult records and sends the result of the execution of s to the requester
+func (c *HasClientNotification) NotifyResult(s ServiceExecutionInfo, result notification.ExecutionResult) error {
+	CountExecution(s.ServiceName, result.Status)
+	return c.NotiImplIns.InvokeResultNotification(s.NotificationTargetURL, result)
+}
+
+// NotifyStarted sends Started status with the process ID of the execution of s to the requester,
+// it is not counted as the outcome of execution
+func (c *HasClientNotification) NotifyStarted(s ServiceExecutionInfo, pid int, startTime time.Time) error {
+	return c.NotiImplIns.InvokeResultNotification(s.NotificationTargetURL, notification.ExecutionResult{
+		ServiceID: s.ServiceID,
+		Status:    servicemgrtypes.ConstServiceStatusStarted,
+		StartTime: startTime,
+		PID:       pid,
+	})
+}
+
+// NotifyPulling sends Pulling status with the progress of pulling the image of s to the requester,
+// it is not counted as the outcome of execution
+func (c *HasClientNotification) NotifyPulling(s ServiceExecutionInfo, progress string) error {
+	return c.NotiImplIns.InvokeResultNotification(s.NotificationTargetURL, notification.ExecutionResult{
+		ServiceID: s.ServiceID,
+		Status:    servicemgrtypes.ConstServiceStatusPulling,
+		Progress:  progress,
+	})
+}
+
+// Reason describes the failure of step with err
+func Reason(step string, err error) string {
+	return step + " : " + err.Error()
+}
+
+// Ended sets the start time, the end time and the duration of result of the execution started at startTime
+func Ended(result notification.ExecutionResult, startTime time.Time) notification.ExecutionResult {
+	result.StartTime = startTime
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(startTime)
+	return result
+}
+
+// ExitStatus gives the exit code and the signal of the process from the error of exec.Cmd.Wait,
+// the exit code of the process terminated by a signal is 128 + the signal number like shells
+func ExitStatus(err error) (exitCode int, signal string) {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return
+	}
+
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok {
+		return
+	}
+
+	if status.Signaled() {
+		return 128 + int(status.Signal()), status.Signal().String()
+	}
+	return status.ExitStatus(), ""
+}
+
+// Deadline returns the channel receiving the time when timeout is elapsed, it never receives if timeout is 0
+func Deadline(timeout time.Duration) <-chan time.Time {
+	if timeout <= 0 {
+		return nil
+	}
+	return time.After(timeout)
+}
+
+// TimedOut sets TimedOut status and the reason to result of the execution terminated by timeout
+func TimedOut(result notification.ExecutionResult, timeout time.Duration) notification.ExecutionResult {
+	result.Status = servicemgrtypes.ConstServiceStatusTimedOut
+	result.Reason = ReasonTimedOut + " : " + timeout.String()
+	return result
+}
+
+// Stopping returns the channel which is closed when the services running now are stopped by StopServices
+func Stopping() <-chan struct{} {
+	stoppingMtx.Lock()
+	defer stoppingMtx.Unlock()
+
+	return stopping
+}
+
+// StopServices makes executors terminate every running service, they are notified with Terminated
+func StopServices() {
+	stoppingMtx.Lock()
+	defer stoppingMtx.Unlock()
+
+	close(stopping)
+	stopping = make(chan struct{})
+}
+
+// Terminated sets Terminated status and the reason to result of the execution terminated by StopServices
+func Terminated(result notification.ExecutionResult) notification.ExecutionResult {
+	result.Status = servicemgrtypes.ConstServiceStatusTerminated
+	result.Reason = ReasonTerminated
+	return result
+}
+
+// ProcessGroup gives the attributes starting the process in its own process group to terminate it with its children
+func ProcessGroup() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// Terminate sends SIGTERM to the process group of process and SIGKILL if it is not exited in TerminationGracePeriod,
+// it returns the error of exec.Cmd.Wait received from waitCh
+func Terminate(process *os.Process, waitCh <-chan error) error {
+	if err := syscall.Kill(-process.Pid, syscall.SIGTERM); err != nil {
+		log.Println(logPrefix, "[Terminate]", err.Error())
+	}
+
+	select {
+	case err := <-waitCh:
+		return err
+	case <-time.After(TerminationGracePeriod):
+	}
+
+	log.Println(logPrefix, "[Terminate]", process.Pid, "is not exited, it is killed")
+	if err := syscall.Kill(-process.Pid, syscall.SIGKILL); err != nil {
+		log.Println(logPrefix, "[Terminate]", err.Error())
+	}
+	return <-waitCh
+}
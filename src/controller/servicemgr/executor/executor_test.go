@@ -1 +1,65 @@
 package executor
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"common/metrics"
+	"controller/servicemgr/notification"
+)
+
+func TestCountExecution(t *testing.T) {
+	CountExecution("counted", "Finished")
+	CountExecution("counted", "Finished")
+	CountExecution("counted", "Failed")
+
+	var buf bytes.Buffer
+	if err := metrics.WriteText(&buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, expected := range []string{
+		`edge_orchestration_executions_total{service="counted",status="Failed"} 1`,
+		`edge_orchestration_executions_total{service="counted",status="Finished"} 2`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected %q in\n%s", expected, buf.String())
+		}
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	t.Run("Exited", func(t *testing.T) {
+		err := exec.Command("sh", "-c", "exit 3").Run()
+		if exitCode, signal := ExitStatus(err); exitCode != 3 || signal != "" {
+			t.Error("unexpected exit status : ", exitCode, signal)
+		}
+	})
+	t.Run("Signaled", func(t *testing.T) {
+		err := exec.Command("sh", "-c", "kill -9 $$").Run()
+		if exitCode, signal := ExitStatus(err); exitCode != 137 || signal != syscall.SIGKILL.String() {
+			t.Error("unexpected exit status : ", exitCode, signal)
+		}
+	})
+	t.Run("Success", func(t *testing.T) {
+		if exitCode, signal := ExitStatus(nil); exitCode != 0 || signal != "" {
+			t.Error("unexpected exit status : ", exitCode, signal)
+		}
+	})
+}
+
+func TestEnded(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	result := Ended(notification.ExecutionResult{ServiceID: 1, Status: "Finished"}, start)
+
+	if !result.StartTime.Equal(start) || result.EndTime.Before(start) {
+		t.Error("unexpected times : ", result.StartTime, result.EndTime)
+	}
+	if result.Duration != result.EndTime.Sub(start) || result.Duration < time.Second {
+		t.Error("unexpected duration : ", result.Duration)
+	}
+}
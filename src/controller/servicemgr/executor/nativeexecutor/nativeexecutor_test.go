@@ -18,10 +18,15 @@
 package nativeexecutor
 
 import (
+	"strings"
 	"testing"
+	"time"
 
+	"controller/servicemgr"
 	"controller/servicemgr/executor"
+	"controller/servicemgr/notification"
 	notificationMock "controller/servicemgr/notification/mocks"
+	"controller/servicemgr/servicelog"
 	clientApiMock "restinterface/client/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -37,6 +42,35 @@ func initializeMock(t *testing.T) (*notificationMock.MockNotification, *clientAp
 	return noti, client
 }
 
+func expectResult(t *testing.T, noti *notificationMock.MockNotification, check func(result notification.ExecutionResult)) {
+	t.Helper()
+
+	noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(target string, result notification.ExecutionResult) error {
+			if result.ServiceID != 1 {
+				t.Error("unexpected service id : ", result.ServiceID)
+			}
+			if result.StartTime.IsZero() || result.EndTime.Before(result.StartTime) {
+				t.Error("unexpected times : ", result.StartTime, result.EndTime)
+			}
+			check(result)
+			return nil
+		})
+}
+
+func expectStarted(t *testing.T, noti *notificationMock.MockNotification, check func(result notification.ExecutionResult)) *gomock.Call {
+	t.Helper()
+
+	return noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(target string, result notification.ExecutionResult) error {
+			if result.ServiceID != 1 || result.Status != servicemgr.ConstServiceStatusStarted || result.PID <= 0 || result.StartTime.IsZero() {
+				t.Error("unexpected started result : ", result)
+			}
+			check(result)
+			return nil
+		})
+}
+
 func TestClient(t *testing.T) {
 	tExecutor := GetInstance()
 
@@ -58,9 +92,12 @@ func TestExecute(t *testing.T) {
 	tExecutor := GetInstance()
 	noti, _ := initializeMock(t)
 
-	gomock.InOrder(
-		noti.EXPECT().InvokeNotification(gomock.Any(), gomock.Any(), gomock.Any()),
-	)
+	expectStarted(t, noti, func(notification.ExecutionResult) {})
+	expectResult(t, noti, func(result notification.ExecutionResult) {
+		if result.Status != servicemgr.ConstServiceStatusFinished || result.ExitCode != 0 {
+			t.Error("unexpected result : ", result)
+		}
+	})
 
 	s := executor.ServiceExecutionInfo{ServiceID: uint64(1), ServiceName: "ls_service", ParamStr: []string{"ls", "-ail"}, NotificationTargetURL: ""}
 
@@ -76,9 +113,11 @@ func TestExecuteFailWithEmptyServiceCmd(t *testing.T) {
 	tExecutor := GetInstance()
 
 	noti, _ := initializeMock(t)
-	gomock.InOrder(
-		noti.EXPECT().InvokeNotification(gomock.Any(), gomock.Any(), gomock.Any()),
-	)
+	expectResult(t, noti, func(result notification.ExecutionResult) {
+		if result.Status != servicemgr.ConstServiceStatusFailed || !strings.HasPrefix(result.Reason, executor.ReasonStartFailed) {
+			t.Error("unexpected result : ", result)
+		}
+	})
 
 	s := executor.ServiceExecutionInfo{ServiceID: uint64(1), ServiceName: "ls_service", NotificationTargetURL: ""}
 
@@ -97,9 +136,11 @@ func TestExecuteFailWithInvalidServiceName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	noti := notificationMock.NewMockNotification(ctrl)
 
-	gomock.InOrder(
-		noti.EXPECT().InvokeNotification(gomock.Any(), gomock.Any(), gomock.Any()),
-	)
+	expectResult(t, noti, func(result notification.ExecutionResult) {
+		if result.Status != servicemgr.ConstServiceStatusFailed || !strings.HasPrefix(result.Reason, executor.ReasonStartFailed) {
+			t.Error("unexpected result : ", result)
+		}
+	})
 
 	s := executor.ServiceExecutionInfo{ServiceID: uint64(1), ServiceName: "InvalidService", ParamStr: []string{"invalid", "-ail"}, NotificationTargetURL: ""}
 
@@ -117,9 +158,12 @@ func TestExecuteFailWithInvalidServiceArgs(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	noti := notificationMock.NewMockNotification(ctrl)
 
-	gomock.InOrder(
-		noti.EXPECT().InvokeNotification(gomock.Any(), gomock.Any(), gomock.Any()),
-	)
+	expectStarted(t, noti, func(notification.ExecutionResult) {})
+	expectResult(t, noti, func(result notification.ExecutionResult) {
+		if result.Status != servicemgr.ConstServiceStatusFailed || result.ExitCode == 0 || len(result.Reason) == 0 {
+			t.Error("unexpected result : ", result)
+		}
+	})
 
 	s := executor.ServiceExecutionInfo{ServiceID: uint64(1), ServiceName: "ls", ParamStr: []string{"ls", "InvalidArgs"}, NotificationTargetURL: ""}
 
@@ -130,3 +174,135 @@ func TestExecuteFailWithInvalidServiceArgs(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestExecuteNotBlocked(t *testing.T) {
+	tExecutor := GetInstance()
+	noti, _ := initializeMock(t)
+
+	s := executor.ServiceExecutionInfo{
+		ServiceID:   uint64(1),
+		ServiceName: "sh_service",
+		ParamStr:    []string{"sh", "-c", "echo out; echo err >&2; sleep 0.3; cat"},
+		Stdin:       "payload",
+	}
+
+	gomock.InOrder(
+		expectStarted(t, noti, func(result notification.ExecutionResult) {
+			// @Note : Started is notified while the process is running
+			if time.Since(result.StartTime) >= 300*time.Millisecond {
+				t.Error("started is notified after the end of output")
+			}
+		}),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusFinished {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			}),
+	)
+
+	tExecutor.SetNotiImpl(noti)
+	if err := tExecutor.Execute(s); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	chunk, err := servicelog.GetInstance().Read(s.NotificationTargetURL, s.ServiceID, 0, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lines := make(map[string]string)
+	for _, line := range chunk.Lines {
+		lines[line.Text] = line.Stream
+	}
+	if lines["out"] != servicelog.StreamStdout || lines["err"] != servicelog.StreamStderr || lines["payload"] != servicelog.StreamStdout {
+		t.Error("unexpected output : ", chunk.Lines)
+	}
+}
+
+func TestExecuteTimedOut(t *testing.T) {
+	defaultGracePeriod := executor.TerminationGracePeriod
+	executor.TerminationGracePeriod = 100 * time.Millisecond
+	defer func() {
+		executor.TerminationGracePeriod = defaultGracePeriod
+	}()
+
+	tests := map[string]struct {
+		paramStr []string
+		signal   string
+	}{
+		"Terminated": {[]string{"sleep", "10"}, "terminated"},
+		"Killed":     {[]string{"sh", "-c", "trap '' TERM; sleep 10"}, "killed"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tExecutor := GetInstance()
+			noti, _ := initializeMock(t)
+
+			s := executor.ServiceExecutionInfo{
+				ServiceID:   uint64(1),
+				ServiceName: "sleep_service",
+				ParamStr:    test.paramStr,
+				Timeout:     100 * time.Millisecond,
+			}
+
+			gomock.InOrder(
+				expectStarted(t, noti, func(result notification.ExecutionResult) {}),
+				noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(target string, result notification.ExecutionResult) error {
+						if result.Status != servicemgr.ConstServiceStatusTimedOut || result.Signal != test.signal {
+							t.Error("unexpected result : ", result)
+						}
+						if !strings.HasPrefix(result.Reason, executor.ReasonTimedOut) {
+							t.Error("unexpected reason : ", result.Reason)
+						}
+						return nil
+					}),
+			)
+
+			tExecutor.SetNotiImpl(noti)
+			startTime := time.Now()
+			tExecutor.Execute(s)
+			if elapsed := time.Since(startTime); elapsed > 5*time.Second {
+				t.Error("service is not terminated by its deadline : ", elapsed)
+			}
+		})
+	}
+}
+
+func TestExecuteTerminated(t *testing.T) {
+	tExecutor := GetInstance()
+	noti, _ := initializeMock(t)
+
+	s := executor.ServiceExecutionInfo{
+		ServiceID:   uint64(1),
+		ServiceName: "sleep_service",
+		ParamStr:    []string{"sleep", "10"},
+	}
+
+	gomock.InOrder(
+		expectStarted(t, noti, func(result notification.ExecutionResult) {
+			// @Note : orchestration is stopped after the service waits for its end
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				executor.StopServices()
+			}()
+		}),
+		noti.EXPECT().InvokeResultNotification(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, result notification.ExecutionResult) error {
+				if result.Status != servicemgr.ConstServiceStatusTerminated || result.Reason != executor.ReasonTerminated {
+					t.Error("unexpected result : ", result)
+				}
+				return nil
+			}),
+	)
+
+	tExecutor.SetNotiImpl(noti)
+	startTime := time.Now()
+	tExecutor.Execute(s)
+	if elapsed := time.Since(startTime); elapsed > 5*time.Second {
+		t.Error("service is not terminated by stopping orchestration : ", elapsed)
+	}
+}
@@ -5,10 +5,13 @@
 package mocks
 
 import (
+	servicemgr "controller/servicemgr"
 	executor "controller/servicemgr/executor"
+	servicelog "controller/servicemgr/servicelog"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 	client "restinterface/client"
+	time "time"
 )
 
 // MockServiceMgr is a mock of ServiceMgr interface
@@ -35,17 +38,18 @@ func (m *MockServiceMgr) EXPECT() *MockServiceMgrMockRecorder {
 }
 
 // Execute mocks base method
-func (m *MockServiceMgr) Execute(target, name string, args []interface{}, notiChan chan string) error {
+func (m *MockServiceMgr) Execute(target, name string, args []interface{}, opts servicemgr.ExecutionOptions, notiChan chan string) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Execute", target, name, args, notiChan)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Execute", target, name, args, opts, notiChan)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Execute indicates an expected call of Execute
-func (mr *MockServiceMgrMockRecorder) Execute(target, name, args, notiChan interface{}) *gomock.Call {
+func (mr *MockServiceMgrMockRecorder) Execute(target, name, args, opts, notiChan interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockServiceMgr)(nil).Execute), target, name, args, notiChan)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockServiceMgr)(nil).Execute), target, name, args, opts, notiChan)
 }
 
 // SetLocalServiceExecutor mocks base method
@@ -60,10 +64,39 @@ func (mr *MockServiceMgrMockRecorder) SetLocalServiceExecutor(s interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocalServiceExecutor", reflect.TypeOf((*MockServiceMgr)(nil).SetLocalServiceExecutor), s)
 }
 
+// AddLocalServiceExecutor mocks base method
+func (m *MockServiceMgr) AddLocalServiceExecutor(execType string, s executor.ServiceExecutor) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddLocalServiceExecutor", execType, s)
+}
+
+// AddLocalServiceExecutor indicates an expected call of AddLocalServiceExecutor
+func (mr *MockServiceMgrMockRecorder) AddLocalServiceExecutor(execType, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLocalServiceExecutor", reflect.TypeOf((*MockServiceMgr)(nil).AddLocalServiceExecutor), execType, s)
+}
+
+// GetServiceLog mocks base method
+func (m *MockServiceMgr) GetServiceLog(serviceID, since uint64, wait time.Duration) (servicelog.Chunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceLog", serviceID, since, wait)
+	ret0, _ := ret[0].(servicelog.Chunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceLog indicates an expected call of GetServiceLog
+func (mr *MockServiceMgrMockRecorder) GetServiceLog(serviceID, since, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceLog", reflect.TypeOf((*MockServiceMgr)(nil).GetServiceLog), serviceID, since, wait)
+}
+
 // ExecuteAppOnLocal mocks base method
-func (m *MockServiceMgr) ExecuteAppOnLocal(appInfo map[string]interface{}) {
+func (m *MockServiceMgr) ExecuteAppOnLocal(appInfo map[string]interface{}) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "ExecuteAppOnLocal", appInfo)
+	ret := m.ctrl.Call(m, "ExecuteAppOnLocal", appInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // ExecuteAppOnLocal indicates an expected call of ExecuteAppOnLocal
@@ -72,6 +105,44 @@ func (mr *MockServiceMgrMockRecorder) ExecuteAppOnLocal(appInfo interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAppOnLocal", reflect.TypeOf((*MockServiceMgr)(nil).ExecuteAppOnLocal), appInfo)
 }
 
+// SetLimits mocks base method
+func (m *MockServiceMgr) SetLimits(l servicemgr.Limits) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLimits", l)
+}
+
+// SetLimits indicates an expected call of SetLimits
+func (mr *MockServiceMgrMockRecorder) SetLimits(l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimits", reflect.TypeOf((*MockServiceMgr)(nil).SetLimits), l)
+}
+
+// GetCapacity mocks base method
+func (m *MockServiceMgr) GetCapacity() servicemgr.Capacity {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapacity")
+	ret0, _ := ret[0].(servicemgr.Capacity)
+	return ret0
+}
+
+// GetCapacity indicates an expected call of GetCapacity
+func (mr *MockServiceMgrMockRecorder) GetCapacity() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapacity", reflect.TypeOf((*MockServiceMgr)(nil).GetCapacity))
+}
+
+// NotifyTermination mocks base method
+func (m *MockServiceMgr) NotifyTermination() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyTermination")
+}
+
+// NotifyTermination indicates an expected call of NotifyTermination
+func (mr *MockServiceMgrMockRecorder) NotifyTermination() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyTermination", reflect.TypeOf((*MockServiceMgr)(nil).NotifyTermination))
+}
+
 // SetClient mocks base method
 func (m *MockServiceMgr) SetClient(clientAPI client.Clienter) {
 	m.ctrl.T.Helper()
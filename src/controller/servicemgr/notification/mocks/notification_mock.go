@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	notification "controller/servicemgr/notification"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 	client "restinterface/client"
@@ -47,6 +48,20 @@ func (mr *MockNotificationMockRecorder) InvokeNotification(target, serviceID, st
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeNotification", reflect.TypeOf((*MockNotification)(nil).InvokeNotification), target, serviceID, status)
 }
 
+// InvokeResultNotification mocks base method
+func (m *MockNotification) InvokeResultNotification(target string, result notification.ExecutionResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvokeResultNotification", target, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvokeResultNotification indicates an expected call of InvokeResultNotification
+func (mr *MockNotificationMockRecorder) InvokeResultNotification(target, result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeResultNotification", reflect.TypeOf((*MockNotification)(nil).InvokeResultNotification), target, result)
+}
+
 // AddNotificationChan mocks base method
 func (m *MockNotification) AddNotificationChan(serviceID uint64, notiChan chan string) {
 	m.ctrl.T.Helper()
@@ -73,6 +88,32 @@ func (mr *MockNotificationMockRecorder) HandleNotificationOnLocal(serviceID, sta
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNotificationOnLocal", reflect.TypeOf((*MockNotification)(nil).HandleNotificationOnLocal), serviceID, status)
 }
 
+// HandleResultOnLocal mocks base method
+func (m *MockNotification) HandleResultOnLocal(result notification.ExecutionResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleResultOnLocal", result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleResultOnLocal indicates an expected call of HandleResultOnLocal
+func (mr *MockNotificationMockRecorder) HandleResultOnLocal(result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleResultOnLocal", reflect.TypeOf((*MockNotification)(nil).HandleResultOnLocal), result)
+}
+
+// HandleAllNotificationOnLocal mocks base method
+func (m *MockNotification) HandleAllNotificationOnLocal(status string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleAllNotificationOnLocal", status)
+}
+
+// HandleAllNotificationOnLocal indicates an expected call of HandleAllNotificationOnLocal
+func (mr *MockNotificationMockRecorder) HandleAllNotificationOnLocal(status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleAllNotificationOnLocal", reflect.TypeOf((*MockNotification)(nil).HandleAllNotificationOnLocal), status)
+}
+
 // SetClient mocks base method
 func (m *MockNotification) SetClient(clientAPI client.Clienter) {
 	m.ctrl.T.Helper()
@@ -23,15 +23,20 @@ import (
 	"log"
 	"strings"
 
+	"common/eventbus"
 	"common/networkhelper"
+	"common/types/servicemgrtypes"
 	"restinterface/client"
 )
 
 // Notification is the interface for notification
 type Notification interface {
 	InvokeNotification(target string, serviceID float64, status string) error
+	InvokeResultNotification(target string, result ExecutionResult) error
 	AddNotificationChan(serviceID uint64, notiChan chan string)
 	HandleNotificationOnLocal(serviceID float64, status string) (err error)
+	HandleResultOnLocal(result ExecutionResult) (err error)
+	HandleAllNotificationOnLocal(status string)
 
 	// for client
 	client.Setter
@@ -76,15 +81,33 @@ func (NotiImpl) AddNotificationChan(serviceID uint64, notiChan chan string) {
 
 // InvokeNotification is processing notification
 func (n NotiImpl) InvokeNotification(target string, serviceID float64, status string) (err error) {
+	return n.InvokeResultNotification(target, ExecutionResult{ServiceID: uint64(serviceID), Status: status})
+}
+
+// InvokeResultNotification notifies the requester on target of the result of execution
+func (n NotiImpl) InvokeResultNotification(target string, result ExecutionResult) (err error) {
 	outboundIP, outboundIPErr := networkhelper.GetInstance().GetOutboundIP()
 	if outboundIPErr != nil {
 		outboundIP = ""
 	}
 
 	if strings.Compare(target, outboundIP) == 0 {
-		return n.HandleNotificationOnLocal(serviceID, status)
+		return n.HandleResultOnLocal(result)
 	}
-	return n.handleNotificationOnRemote(target, serviceID, status)
+	return n.handleNotificationOnRemote(target, result)
+}
+
+// HandleResultOnLocal publishes the result of execution and delivers its status to the notification channel,
+// Pulling and Started status are only published because the notification channel waits the end of execution
+func (n NotiImpl) HandleResultOnLocal(result ExecutionResult) (err error) {
+	log.Println(logPrefix, "[HandleResultOnLocal]", result.String())
+	eventbus.GetInstance().Publish(ExecutionResultTopic, result)
+
+	switch result.Status {
+	case servicemgrtypes.ConstServiceStatusPulling, servicemgrtypes.ConstServiceStatusStarted:
+		return
+	}
+	return n.HandleNotificationOnLocal(float64(result.ServiceID), result.Status)
 }
 
 // HandleNotificationOnLocal is invoking notification on local
@@ -101,12 +124,30 @@ func (NotiImpl) HandleNotificationOnLocal(serviceID float64, status string) (err
 	return
 }
 
-func (n NotiImpl) handleNotificationOnRemote(target string, serviceID float64, status string) (err error) {
-	statusNotificationInfo := make(map[string]interface{})
-	statusNotificationInfo["ServiceID"] = serviceID
-	statusNotificationInfo["Status"] = status
+// HandleAllNotificationOnLocal delivers status to every notification channel waiting on local
+func (NotiImpl) HandleAllNotificationOnLocal(status string) {
+	ids := make([]uint64, 0)
+	for item := range notificationMap.Iter() {
+		ids = append(ids, item.Key)
+	}
+
+	for _, id := range ids {
+		notiChan, _ := getNotiChan(id)
+		if notiChan == nil {
+			continue
+		}
+
+		select {
+		case notiChan <- status:
+		default:
+			log.Println(logPrefix, "notiChan is not receiving", id)
+		}
+		notificationMap.Remove(id)
+	}
+}
 
-	err = n.Clienter.DoNotifyAppStatusRemoteDevice(statusNotificationInfo, uint64(serviceID), target)
+func (n NotiImpl) handleNotificationOnRemote(target string, result ExecutionResult) (err error) {
+	err = n.Clienter.DoNotifyAppStatusRemoteDevice(result.ToMap(), result.ServiceID, target)
 
 	if err != nil {
 		log.Println(logPrefix, err.Error())
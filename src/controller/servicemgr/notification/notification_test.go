@@ -23,9 +23,12 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"common/eventbus"
 	"common/networkhelper"
 
 	"github.com/golang/mock/gomock"
@@ -89,3 +92,189 @@ func TestInvokeNotificationOnRemote(t *testing.T) {
 
 	server.Close()
 }
+
+func TestInvokeResultNotificationOnLocal(t *testing.T) {
+	notiChan := make(chan string, 1)
+	sub := eventbus.GetInstance().Subscribe(ExecutionResultTopic)
+	defer eventbus.GetInstance().Unsubscribe(sub)
+
+	result := ExecutionResult{ServiceID: id, Status: "Failed", ExitCode: 2}
+
+	GetInstance().AddNotificationChan(id, notiChan)
+	if err := GetInstance().InvokeResultNotification(targetLocalAddr, result); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if str := <-notiChan; str != "Failed" {
+		t.Error("unexpected status : ", str)
+	}
+	select {
+	case event := <-sub.C:
+		if !reflect.DeepEqual(event, result) {
+			t.Error("unexpected result : ", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("result is not published")
+	}
+}
+
+func TestInvokeStartedNotificationOnLocal(t *testing.T) {
+	notiChan := make(chan string, 1)
+	sub := eventbus.GetInstance().Subscribe(ExecutionResultTopic)
+	defer eventbus.GetInstance().Unsubscribe(sub)
+
+	result := ExecutionResult{ServiceID: id, Status: "Started", PID: 1234}
+
+	GetInstance().AddNotificationChan(id, notiChan)
+	if err := GetInstance().InvokeResultNotification(targetLocalAddr, result); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	select {
+	case event := <-sub.C:
+		if !reflect.DeepEqual(event, result) {
+			t.Error("unexpected result : ", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("result is not published")
+	}
+	select {
+	case str := <-notiChan:
+		t.Error("started status is delivered : ", str)
+	default:
+	}
+
+	// @Note : the notification channel still waits the end of execution
+	if err := GetInstance().HandleNotificationOnLocal(float64(id), "Finished"); err != nil || <-notiChan != "Finished" {
+		t.Error("end of execution is not delivered")
+	}
+}
+
+func TestInvokePullingNotificationOnLocal(t *testing.T) {
+	notiChan := make(chan string, 1)
+
+	GetInstance().AddNotificationChan(id, notiChan)
+	result := ExecutionResult{ServiceID: id, Status: "Pulling", Progress: "Pulling fs layer"}
+	if err := GetInstance().InvokeResultNotification(targetLocalAddr, result); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	select {
+	case str := <-notiChan:
+		t.Error("pulling status is delivered : ", str)
+	default:
+	}
+
+	if err := GetInstance().HandleNotificationOnLocal(float64(id), "Finished"); err != nil || <-notiChan != "Finished" {
+		t.Error("end of execution is not delivered")
+	}
+}
+
+func TestInvokeResultNotificationOnRemote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := clientMocks.NewMockClienter(ctrl)
+
+	start := time.Date(2019, 7, 1, 10, 0, 0, 0, time.UTC)
+	result := ExecutionResult{
+		ServiceID: id,
+		Status:    "Failed",
+		ExitCode:  137,
+		Signal:    "killed",
+		StartTime: start,
+		EndTime:   start.Add(1500 * time.Millisecond),
+		Duration:  1500 * time.Millisecond,
+	}
+
+	GetInstance().Clienter = mockClient
+	mockClient.EXPECT().DoNotifyAppStatusRemoteDevice(gomock.Any(), gomock.Eq(id), gomock.Eq(targetRemoteAddr)).DoAndReturn(
+		func(info map[string]interface{}, appID uint64, target string) error {
+			expected := map[string]interface{}{
+				"ServiceID": float64(id),
+				"Status":    "Failed",
+				"ExitCode":  float64(137),
+				"Signal":    "killed",
+				"StartTime": "2019-07-01T10:00:00Z",
+				"EndTime":   "2019-07-01T10:00:01.5Z",
+				"Duration":  1.5,
+			}
+			if !reflect.DeepEqual(info, expected) {
+				t.Error("unexpected notification : ", info)
+			}
+			return nil
+		})
+
+	if err := GetInstance().InvokeResultNotification(targetRemoteAddr, result); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestParseExecutionResult(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		start := time.Date(2019, 7, 1, 10, 0, 0, 0, time.UTC)
+		expected := ExecutionResult{
+			ServiceID: id,
+			Status:    "Failed",
+			ExitCode:  1,
+			Reason:    "start failed : exec: not found",
+			StartTime: start,
+			EndTime:   start.Add(time.Second),
+			Duration:  time.Second,
+		}
+
+		result, err := ParseExecutionResult(expected.ToMap())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !result.StartTime.Equal(expected.StartTime) || !result.EndTime.Equal(expected.EndTime) {
+			t.Error("unexpected times : ", result.StartTime, result.EndTime)
+		}
+		result.StartTime, result.EndTime = expected.StartTime, expected.EndTime
+		if !reflect.DeepEqual(result, expected) {
+			t.Error("unexpected result : ", result)
+		}
+	})
+	t.Run("Started", func(t *testing.T) {
+		expected := ExecutionResult{ServiceID: id, Status: "Started", PID: 1234}
+
+		result, err := ParseExecutionResult(expected.ToMap())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Error("unexpected result : ", result)
+		}
+	})
+	t.Run("Pulling", func(t *testing.T) {
+		expected := ExecutionResult{ServiceID: id, Status: "Pulling", Progress: "a3ed95caeb02: Downloading 1MB/4MB"}
+
+		result, err := ParseExecutionResult(expected.ToMap())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Error("unexpected result : ", result)
+		}
+	})
+	t.Run("StatusOnly", func(t *testing.T) {
+		result, err := ParseExecutionResult(map[string]interface{}{"ServiceID": float64(id), "Status": "Finished"})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !reflect.DeepEqual(result, ExecutionResult{ServiceID: id, Status: "Finished"}) {
+			t.Error("unexpected result : ", result)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		invalids := []map[string]interface{}{
+			{"Status": "Finished"},
+			{"ServiceID": float64(id)},
+			{"ServiceID": float64(id), "Status": "Finished", "StartTime": "yesterday"},
+		}
+		for _, info := range invalids {
+			if _, err := ParseExecutionResult(info); err == nil {
+				t.Error("expected error : ", info)
+			}
+		}
+	})
+}
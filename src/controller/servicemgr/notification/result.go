@@ -0,0 +1,167 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// ExecutionResultTopic is the topic of event bus publishing execution results notified to local device
+	ExecutionResultTopic = "notification/result"
+
+	keyServiceID = "ServiceID"
+	keyStatus    = "Status"
+	keyExitCode  = "ExitCode"
+	keySignal    = "Signal"
+	keyReason    = "Reason"
+	keyStartTime = "StartTime"
+	keyEndTime   = "EndTime"
+	keyDuration  = "Duration"
+	keyPID       = "PID"
+	keyProgress  = "Progress"
+)
+
+// ExecutionResult is the result of a service execution notified to the requester
+type ExecutionResult struct {
+	ServiceID uint64
+	Status    string
+
+	// ExitCode, EndTime and Duration are set if the service application is ended
+	ExitCode int
+	// Signal is the name of the signal terminated the service application
+	Signal string
+	// Reason describes why the service application is failed or not executed
+	Reason string
+
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+
+	// PID is the process ID of native service application, it is notified with Started status
+	PID int
+	// Progress is the progress of pulling the image of container, it is notified with Pulling status
+	Progress string
+}
+
+// ToMap converts the result to the body of status notification, times are written in RFC3339 and duration in seconds
+func (r ExecutionResult) ToMap() map[string]interface{} {
+	info := make(map[string]interface{})
+	info[keyServiceID] = float64(r.ServiceID)
+	info[keyStatus] = r.Status
+
+	if len(r.Signal) != 0 {
+		info[keySignal] = r.Signal
+	}
+	if len(r.Reason) != 0 {
+		info[keyReason] = r.Reason
+	}
+	if !r.StartTime.IsZero() {
+		info[keyStartTime] = r.StartTime.Format(time.RFC3339Nano)
+	}
+	if !r.EndTime.IsZero() {
+		info[keyExitCode] = float64(r.ExitCode)
+		info[keyEndTime] = r.EndTime.Format(time.RFC3339Nano)
+		info[keyDuration] = r.Duration.Seconds()
+	}
+	if r.PID != 0 {
+		info[keyPID] = float64(r.PID)
+	}
+	if len(r.Progress) != 0 {
+		info[keyProgress] = r.Progress
+	}
+
+	return info
+}
+
+// ParseExecutionResult converts the body of status notification to the result
+func ParseExecutionResult(info map[string]interface{}) (result ExecutionResult, err error) {
+	serviceID, ok := info[keyServiceID].(float64)
+	if !ok {
+		return result, errors.New("invalid " + keyServiceID)
+	}
+	status, ok := info[keyStatus].(string)
+	if !ok {
+		return result, errors.New("invalid " + keyStatus)
+	}
+	result.ServiceID = uint64(serviceID)
+	result.Status = status
+
+	if exitCode, ok := info[keyExitCode].(float64); ok {
+		result.ExitCode = int(exitCode)
+	}
+	if signal, ok := info[keySignal].(string); ok {
+		result.Signal = signal
+	}
+	if reason, ok := info[keyReason].(string); ok {
+		result.Reason = reason
+	}
+	if result.StartTime, err = parseTime(info, keyStartTime); err != nil {
+		return
+	}
+	if result.EndTime, err = parseTime(info, keyEndTime); err != nil {
+		return
+	}
+	if duration, ok := info[keyDuration].(float64); ok {
+		result.Duration = time.Duration(duration * float64(time.Second))
+	}
+	if pid, ok := info[keyPID].(float64); ok {
+		result.PID = int(pid)
+	}
+	if progress, ok := info[keyProgress].(string); ok {
+		result.Progress = progress
+	}
+
+	return
+}
+
+// String gives the result in a line of log
+func (r ExecutionResult) String() string {
+	str := fmt.Sprintf("[serviceID:%d][status:%s]", r.ServiceID, r.Status)
+	if r.PID != 0 {
+		str += fmt.Sprintf("[pid:%d]", r.PID)
+	}
+	if !r.EndTime.IsZero() {
+		str += fmt.Sprintf("[exitCode:%d][duration:%s]", r.ExitCode, r.Duration)
+	}
+	if len(r.Signal) != 0 {
+		str += "[signal:" + r.Signal + "]"
+	}
+	if len(r.Reason) != 0 {
+		str += "[reason:" + r.Reason + "]"
+	}
+	if len(r.Progress) != 0 {
+		str += "[progress:" + r.Progress + "]"
+	}
+	return str
+}
+
+func parseTime(info map[string]interface{}, key string) (time.Time, error) {
+	value, exist := info[key]
+	if !exist {
+		return time.Time{}, nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, errors.New("invalid " + key)
+	}
+	return time.Parse(time.RFC3339Nano, str)
+}
@@ -63,3 +63,20 @@ func (cm *ConcurrentMap) Remove(key uint64) {
 
 	delete(cm.items, key)
 }
+
+// Iter is for iterating map item
+func (cm *ConcurrentMap) Iter() <-chan ConcurrentMapItem {
+	c := make(chan ConcurrentMapItem)
+
+	go func() {
+		cm.Lock()
+		defer cm.Unlock()
+
+		for k, v := range cm.items {
+			c <- ConcurrentMapItem{k, v}
+		}
+		close(c)
+	}()
+
+	return c
+}
@@ -0,0 +1,404 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package servicelog captures the output of service applications to give it back to the requester
+package servicelog
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"common/errors"
+)
+
+const (
+	// StreamStdout is the stream of standard output
+	StreamStdout = "stdout"
+	// StreamStderr is the stream of standard error
+	StreamStderr = "stderr"
+
+	// DefaultBufferSize is the bytes of output kept for a service
+	DefaultBufferSize = 64 * 1024
+	// DefaultRetention is the number of ended services whose output is kept
+	DefaultRetention = 32
+	// MaxWait limits the time a read waits for new output
+	MaxWait = 5 * time.Second
+
+	// maxLineSize splits a line longer than it
+	maxLineSize = 4 * 1024
+
+	keyServiceID = "ServiceID"
+	keyLines     = "Lines"
+	keyNext      = "Next"
+	keyDropped   = "Dropped"
+	keyFinished  = "Finished"
+	keySeq       = "Seq"
+	keyTime      = "Time"
+	keyStream    = "Stream"
+	keyText      = "Text"
+)
+
+// Line is a line of service output
+type Line struct {
+	Seq    uint64
+	Time   time.Time
+	Stream string
+	Text   string
+}
+
+// Chunk is the output of a service read from a sequence number
+type Chunk struct {
+	ServiceID uint64
+	Lines     []Line
+	// Next is the sequence number to read the following output
+	Next uint64
+	// Dropped is set if lines from the requested sequence number are discarded by the size limit
+	Dropped bool
+	// Finished is set if the service is ended and no more output is given
+	Finished bool
+}
+
+// Store keeps the output of services by requester and service ID
+type Store struct {
+	sync.Mutex
+	buffers    map[key]*buffer
+	ended      []key
+	bufferSize int
+	retention  int
+}
+
+type key struct {
+	requester string
+	serviceID uint64
+}
+
+type buffer struct {
+	writers  []*writer
+	lines    []Line
+	size     int
+	next     uint64
+	finished bool
+	updated  chan struct{}
+}
+
+var store *Store
+
+func init() {
+	store = &Store{
+		buffers:    make(map[key]*buffer),
+		bufferSize: DefaultBufferSize,
+		retention:  DefaultRetention,
+	}
+}
+
+// GetInstance returns the singleton Store instance
+func GetInstance() *Store {
+	return store
+}
+
+// SetLimits sets the bytes of output kept for a service and the number of ended services kept
+func (s *Store) SetLimits(bufferSize int, retention int) error {
+	if bufferSize < maxLineSize {
+		return errors.InvalidParam{Message: "buffer size should be at least " + strconv.Itoa(maxLineSize)}
+	}
+	if retention < 0 {
+		return errors.InvalidParam{Message: "retention should not be negative"}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.bufferSize = bufferSize
+	s.retention = retention
+	for _, b := range s.buffers {
+		b.trim(bufferSize)
+	}
+	s.evict()
+
+	return nil
+}
+
+// Open starts capturing the output of a service, the output of previous execution with same ID is discarded
+func (s *Store) Open(requester string, serviceID uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	k := key{requester, serviceID}
+	if b, exist := s.buffers[k]; exist {
+		s.removeEnded(k)
+		close(b.updated)
+	}
+	s.buffers[k] = &buffer{updated: make(chan struct{})}
+}
+
+// Writer returns the writer capturing the output of a stream line by line,
+// the last line without newline is captured when the writer or the service output is closed
+func (s *Store) Writer(requester string, serviceID uint64, stream string) io.WriteCloser {
+	w := &writer{store: s, key: key{requester, serviceID}, stream: stream}
+
+	s.Lock()
+	if b, exist := s.buffers[w.key]; exist {
+		b.writers = append(b.writers, w)
+	}
+	s.Unlock()
+
+	return w
+}
+
+// Append captures a line of the output of a service
+func (s *Store) Append(requester string, serviceID uint64, stream string, text string) {
+	s.append(key{requester, serviceID}, stream, text)
+}
+
+// Close marks the service is ended, its output is kept until more services than the retention are ended
+func (s *Store) Close(requester string, serviceID uint64) {
+	k := key{requester, serviceID}
+
+	s.Lock()
+	b, exist := s.buffers[k]
+	if !exist || b.finished {
+		s.Unlock()
+		return
+	}
+	writers := b.writers
+	b.writers = nil
+	s.Unlock()
+
+	for _, w := range writers {
+		w.Close()
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.buffers[k] != b || b.finished {
+		return
+	}
+	b.finished = true
+	b.notify()
+	s.ended = append(s.ended, k)
+	s.evict()
+}
+
+// Read returns the output of a service from the sequence number,
+// it waits up to MaxWait for new output if there is nothing to read
+func (s *Store) Read(requester string, serviceID uint64, since uint64, wait time.Duration) (chunk Chunk, err error) {
+	if wait > MaxWait {
+		wait = MaxWait
+	}
+
+	k := key{requester, serviceID}
+
+	s.Lock()
+	b, exist := s.buffers[k]
+	if exist && wait > 0 && since >= b.next && !b.finished {
+		updated := b.updated
+		s.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-updated:
+		case <-timer.C:
+		}
+		timer.Stop()
+
+		s.Lock()
+		b, exist = s.buffers[k]
+	}
+	defer s.Unlock()
+
+	if !exist {
+		err = errors.NotFound{Message: "output of service " + strconv.FormatUint(serviceID, 10)}
+		return
+	}
+
+	chunk = b.read(since)
+	chunk.ServiceID = serviceID
+
+	return
+}
+
+func (s *Store) append(k key, stream string, text string) {
+	s.Lock()
+	defer s.Unlock()
+
+	b, exist := s.buffers[k]
+	if !exist || b.finished {
+		return
+	}
+
+	b.lines = append(b.lines, Line{Seq: b.next, Time: time.Now(), Stream: stream, Text: text})
+	b.size += len(text)
+	b.next++
+	b.trim(s.bufferSize)
+	b.notify()
+}
+
+func (s *Store) evict() {
+	for len(s.ended) > s.retention {
+		delete(s.buffers, s.ended[0])
+		s.ended = s.ended[1:]
+	}
+}
+
+func (s *Store) removeEnded(k key) {
+	for idx, ended := range s.ended {
+		if ended == k {
+			s.ended = append(s.ended[:idx], s.ended[idx+1:]...)
+			return
+		}
+	}
+}
+
+func (b *buffer) trim(bufferSize int) {
+	dropped := 0
+	for b.size > bufferSize && dropped < len(b.lines) {
+		b.size -= len(b.lines[dropped].Text)
+		dropped++
+	}
+	if dropped > 0 {
+		b.lines = append([]Line(nil), b.lines[dropped:]...)
+	}
+}
+
+func (b *buffer) notify() {
+	close(b.updated)
+	b.updated = make(chan struct{})
+}
+
+func (b *buffer) read(since uint64) (chunk Chunk) {
+	first := b.next - uint64(len(b.lines))
+	if since < first {
+		chunk.Dropped = true
+		since = first
+	}
+	if since < b.next {
+		chunk.Lines = append([]Line(nil), b.lines[since-first:]...)
+	}
+	chunk.Next = b.next
+	chunk.Finished = b.finished
+
+	return
+}
+
+// ToMap converts the chunk to the body of REST response, times are written in RFC3339
+func (c Chunk) ToMap() map[string]interface{} {
+	lines := make([]interface{}, len(c.Lines))
+	for idx, line := range c.Lines {
+		lines[idx] = line.ToMap()
+	}
+
+	info := make(map[string]interface{})
+	info[keyServiceID] = float64(c.ServiceID)
+	info[keyLines] = lines
+	info[keyNext] = float64(c.Next)
+	info[keyDropped] = c.Dropped
+	info[keyFinished] = c.Finished
+
+	return info
+}
+
+// ToMap converts the line to the body of REST response
+func (l Line) ToMap() map[string]interface{} {
+	info := make(map[string]interface{})
+	info[keySeq] = float64(l.Seq)
+	info[keyTime] = l.Time.Format(time.RFC3339Nano)
+	info[keyStream] = l.Stream
+	info[keyText] = l.Text
+
+	return info
+}
+
+// ParseChunk converts the body of REST response to the chunk
+func ParseChunk(info map[string]interface{}) (chunk Chunk, err error) {
+	serviceID, ok := info[keyServiceID].(float64)
+	if !ok {
+		return chunk, errors.InvalidJSON{Message: "invalid " + keyServiceID}
+	}
+	next, ok := info[keyNext].(float64)
+	if !ok {
+		return chunk, errors.InvalidJSON{Message: "invalid " + keyNext}
+	}
+	chunk.ServiceID = uint64(serviceID)
+	chunk.Next = uint64(next)
+	chunk.Dropped, _ = info[keyDropped].(bool)
+	chunk.Finished, _ = info[keyFinished].(bool)
+
+	lines, _ := info[keyLines].([]interface{})
+	for _, value := range lines {
+		line, ok := value.(map[string]interface{})
+		if !ok {
+			return chunk, errors.InvalidJSON{Message: "invalid " + keyLines}
+		}
+		seq, _ := line[keySeq].(float64)
+		stream, _ := line[keyStream].(string)
+		text, _ := line[keyText].(string)
+		str, _ := line[keyTime].(string)
+		t, timeErr := time.Parse(time.RFC3339Nano, str)
+		if timeErr != nil {
+			return chunk, errors.InvalidJSON{Message: "invalid " + keyTime}
+		}
+		chunk.Lines = append(chunk.Lines, Line{Seq: uint64(seq), Time: t, Stream: stream, Text: text})
+	}
+
+	return
+}
+
+type writer struct {
+	sync.Mutex
+	store   *Store
+	key     key
+	stream  string
+	partial []byte
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		w.store.append(w.key, w.stream, string(bytes.TrimSuffix(w.partial[:idx], []byte("\r"))))
+		w.partial = w.partial[idx+1:]
+	}
+	for len(w.partial) >= maxLineSize {
+		w.store.append(w.key, w.stream, string(w.partial[:maxLineSize]))
+		w.partial = w.partial[maxLineSize:]
+	}
+	w.partial = append([]byte(nil), w.partial...)
+
+	return len(p), nil
+}
+
+func (w *writer) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.partial) != 0 {
+		w.store.append(w.key, w.stream, string(w.partial))
+		w.partial = nil
+	}
+
+	return nil
+}
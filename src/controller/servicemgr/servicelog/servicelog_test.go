@@ -0,0 +1,217 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package servicelog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const requester = "127.0.0.1"
+
+func newStore(bufferSize int, retention int) *Store {
+	return &Store{buffers: make(map[key]*buffer), bufferSize: bufferSize, retention: retention}
+}
+
+func texts(chunk Chunk) (lines []string) {
+	for _, line := range chunk.Lines {
+		lines = append(lines, line.Stream+":"+line.Text)
+	}
+	return
+}
+
+func TestWriter(t *testing.T) {
+	s := newStore(DefaultBufferSize, DefaultRetention)
+	s.Open(requester, 1)
+
+	stdout := s.Writer(requester, 1, StreamStdout)
+	stderr := s.Writer(requester, 1, StreamStderr)
+	fmt.Fprint(stdout, "first\r\nsec")
+	fmt.Fprint(stderr, "error\n")
+	fmt.Fprint(stdout, "ond\nlast")
+	stdout.Close()
+	stderr.Close()
+
+	chunk, err := s.Read(requester, 1, 0, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []string{"stdout:first", "stderr:error", "stdout:second", "stdout:last"}
+	if !reflect.DeepEqual(texts(chunk), expected) {
+		t.Error("unexpected lines :", texts(chunk))
+	}
+	if chunk.ServiceID != 1 || chunk.Next != 4 || chunk.Dropped || chunk.Finished {
+		t.Error("unexpected chunk :", chunk)
+	}
+
+	t.Run("LongLine", func(t *testing.T) {
+		s.Open(requester, 1)
+		stdout := s.Writer(requester, 1, StreamStdout)
+		fmt.Fprint(stdout, strings.Repeat("a", maxLineSize+1))
+		stdout.Close()
+
+		chunk, _ := s.Read(requester, 1, 0, 0)
+		if len(chunk.Lines) != 2 || len(chunk.Lines[0].Text) != maxLineSize || chunk.Lines[1].Text != "a" {
+			t.Error("long line is not split")
+		}
+	})
+
+	t.Run("ClosedService", func(t *testing.T) {
+		s.Open(requester, 1)
+		fmt.Fprint(s.Writer(requester, 1, StreamStderr), "tail")
+		s.Close(requester, 1)
+
+		chunk, _ := s.Read(requester, 1, 0, 0)
+		if !reflect.DeepEqual(texts(chunk), []string{"stderr:tail"}) || !chunk.Finished {
+			t.Error("unexpected chunk :", chunk)
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	s := newStore(DefaultBufferSize, DefaultRetention)
+	s.Open(requester, 1)
+	for idx := 0; idx < 3; idx++ {
+		s.Append(requester, 1, StreamStdout, fmt.Sprint(idx))
+	}
+
+	t.Run("Since", func(t *testing.T) {
+		chunk, _ := s.Read(requester, 1, 2, 0)
+		if !reflect.DeepEqual(texts(chunk), []string{"stdout:2"}) || chunk.Next != 3 {
+			t.Error("unexpected chunk :", chunk)
+		}
+
+		chunk, _ = s.Read(requester, 1, 3, 0)
+		if len(chunk.Lines) != 0 || chunk.Next != 3 {
+			t.Error("unexpected chunk :", chunk)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			s.Append(requester, 1, StreamStdout, "3")
+		}()
+
+		chunk, _ := s.Read(requester, 1, 3, time.Second)
+		if !reflect.DeepEqual(texts(chunk), []string{"stdout:3"}) {
+			t.Error("unexpected chunk :", chunk)
+		}
+	})
+
+	t.Run("WaitTimeout", func(t *testing.T) {
+		start := time.Now()
+		chunk, _ := s.Read(requester, 1, 4, 50*time.Millisecond)
+		if len(chunk.Lines) != 0 || time.Since(start) < 50*time.Millisecond {
+			t.Error("read does not wait")
+		}
+	})
+
+	t.Run("Finished", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			s.Close(requester, 1)
+		}()
+
+		chunk, _ := s.Read(requester, 1, 4, time.Second)
+		if !chunk.Finished || len(chunk.Lines) != 0 {
+			t.Error("unexpected chunk :", chunk)
+		}
+
+		s.Append(requester, 1, StreamStdout, "ignored")
+		if chunk, _ = s.Read(requester, 1, 4, 0); len(chunk.Lines) != 0 {
+			t.Error("output is captured after closed")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		if _, err := s.Read("127.0.0.2", 1, 0, 0); err == nil {
+			t.Error("output of other requester is read")
+		}
+		if _, err := s.Read(requester, 2, 0, 0); err == nil {
+			t.Error("output of unknown service is read")
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	t.Run("BufferSize", func(t *testing.T) {
+		s := newStore(DefaultBufferSize, DefaultRetention)
+		if err := s.SetLimits(maxLineSize-1, DefaultRetention); err == nil {
+			t.Error("too small buffer size is set")
+		}
+		if err := s.SetLimits(maxLineSize, DefaultRetention); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		s.Open(requester, 1)
+		line := strings.Repeat("a", maxLineSize/2)
+		for idx := 0; idx < 3; idx++ {
+			s.Append(requester, 1, StreamStdout, line)
+		}
+
+		chunk, _ := s.Read(requester, 1, 0, 0)
+		if !chunk.Dropped || len(chunk.Lines) != 2 || chunk.Lines[0].Seq != 1 || chunk.Next != 3 {
+			t.Error("unexpected chunk :", chunk)
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		s := newStore(DefaultBufferSize, 1)
+		s.Open(requester, 1)
+		s.Open(requester, 2)
+		s.Close(requester, 1)
+		s.Close(requester, 2)
+
+		if _, err := s.Read(requester, 1, 0, 0); err == nil {
+			t.Error("output of service 1 is kept over the retention")
+		}
+		if _, err := s.Read(requester, 2, 0, 0); err != nil {
+			t.Error("output of service 2 is evicted")
+		}
+	})
+}
+
+func TestChunkMap(t *testing.T) {
+	chunk := Chunk{
+		ServiceID: 1,
+		Lines:     []Line{{Seq: 2, Time: time.Now().Round(0), Stream: StreamStderr, Text: "error"}},
+		Next:      3,
+		Dropped:   true,
+	}
+
+	parsed, err := ParseChunk(chunk.ToMap())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !parsed.Lines[0].Time.Equal(chunk.Lines[0].Time) {
+		t.Error("unexpected time :", parsed.Lines[0].Time)
+	}
+	parsed.Lines[0].Time = chunk.Lines[0].Time
+	if !reflect.DeepEqual(parsed, chunk) {
+		t.Error("unexpected chunk :", parsed)
+	}
+
+	if _, err := ParseChunk(map[string]interface{}{}); err == nil {
+		t.Error("invalid chunk is parsed")
+	}
+}
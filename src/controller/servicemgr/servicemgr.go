@@ -19,39 +19,80 @@
 package servicemgr
 
 import (
+	"log"
+	"strconv"
 	"strings"
+	"time"
 
+	"common/errors"
 	"common/networkhelper"
 	"controller/servicemgr/executor"
 	"controller/servicemgr/notification"
+	"controller/servicemgr/servicelog"
 	"restinterface/client"
 )
 
 // ServiceMgr is the interface to execute service application
 type ServiceMgr interface {
-	Execute(target string, name string, args []interface{}, notiChan chan string) (err error)
+	Execute(target string, name string, args []interface{}, opts ExecutionOptions, notiChan chan string) (serviceID uint64, err error)
 	SetLocalServiceExecutor(s executor.ServiceExecutor)
+	AddLocalServiceExecutor(execType string, s executor.ServiceExecutor)
+
+	// for requester to get output of service
+	GetServiceLog(serviceID uint64, since uint64, wait time.Duration) (servicelog.Chunk, error)
 
 	// for internal api
-	ExecuteAppOnLocal(appInfo map[string]interface{})
+	ExecuteAppOnLocal(appInfo map[string]interface{}) error
+
+	// for admission control
+	SetLimits(l Limits)
+	GetCapacity() Capacity
+
+	// for stopping orchestration
+	NotifyTermination()
 
 	// for client
 	client.Setter
 }
 
+// ExecutionOptions has the options of an execution given by the request of service
+type ExecutionOptions struct {
+	// Stdin is the payload given to the standard input of service
+	Stdin string
+	// Timeout is the deadline of execution, there is no deadline if it is 0
+	Timeout time.Duration
+	// ExecType is the execution type chosen by scheduling, the default executor runs the service if it is empty
+	ExecType string
+	// Restart is the policy executing the service again after it is ended
+	Restart RestartPolicy
+	// DeviceID is the ID of target device, the supervised service is relocated by Relocate when the device leaves
+	DeviceID string
+	// Relocate gives another device executing the supervised service
+	Relocate Relocator
+}
+
 // SMMgrImpl Structure
 type SMMgrImpl struct {
-	serviceExecutor executor.ServiceExecutor
+	serviceExecutor  executor.ServiceExecutor
+	serviceExecutors map[string]executor.ServiceExecutor
 	client.HasClient
 }
 
+const (
+	// terminationMargin is the time given to executors to notify the services terminated by NotifyTermination
+	terminationMargin = time.Second
+	// terminationPollInterval is the period checking whether the terminated services are ended
+	terminationPollInterval = 100 * time.Millisecond
+)
+
 var (
 	serviceMgr *SMMgrImpl
 )
 
 func init() {
 	ServiceMap = ConcurrentMap{items: make(map[uint64]interface{})}
-	serviceMgr = &SMMgrImpl{}
+	runningServiceMap = ConcurrentMap{items: make(map[uint64]interface{})}
+	serviceMgr = &SMMgrImpl{serviceExecutors: make(map[string]executor.ServiceExecutor)}
 
 }
 
@@ -66,40 +107,151 @@ func (sm *SMMgrImpl) SetLocalServiceExecutor(s executor.ServiceExecutor) {
 	sm.serviceExecutor = s
 }
 
-// Execute selects local execution and remote execution
-func (sm SMMgrImpl) Execute(target string, name string, args []interface{}, notiChan chan string) (err error) {
-	serviceID := createServiceMap(name)
-	appInfo := makeAppInfo(target, name, args, float64(serviceID))
+// AddLocalServiceExecutor adds executor running the services of execType and sets client
+func (sm *SMMgrImpl) AddLocalServiceExecutor(execType string, s executor.ServiceExecutor) {
+	s.SetClient(sm.Clienter)
+	sm.serviceExecutors[execType] = s
+}
+
+// getServiceExecutor returns executor of execType,
+// the default executor is returned for the services without a registered execution type
+func (sm SMMgrImpl) getServiceExecutor(execType string) (executor.ServiceExecutor, error) {
+	if s, ok := sm.serviceExecutors[execType]; ok {
+		return s, nil
+	}
+	if sm.serviceExecutor == nil {
+		return nil, errors.NotSupport{Message: "execution type " + execType}
+	}
+	return sm.serviceExecutor, nil
+}
+
+// Execute selects local execution and remote execution, serviceID is given to get output of service,
+// the service with restart policy is supervised and only its last status is delivered to notiChan
+func (sm SMMgrImpl) Execute(target string, name string, args []interface{}, opts ExecutionOptions, notiChan chan string) (serviceID uint64, err error) {
+	serviceID = createServiceMap(name, target)
+
+	if opts.Restart.supervised() {
+		err = sm.supervise(serviceID, name, Placement{DeviceID: opts.DeviceID, Target: target, ExecType: opts.ExecType, Args: args}, opts, notiChan)
+		return
+	}
 
 	notification.GetInstance().AddNotificationChan(serviceID, notiChan)
+	err = sm.executeOn(target, makeAppInfo(target, name, args, opts, float64(serviceID)))
 
+	return
+}
+
+// executeOn executes the service of appInfo on local if target is local device, otherwise on remote
+func (sm SMMgrImpl) executeOn(target string, appInfo map[string]interface{}) error {
 	outboundIP, outboundIPErr := networkhelper.GetInstance().GetOutboundIP()
 	if outboundIPErr != nil {
 		outboundIP = ""
 	}
 
 	if strings.Compare(target, outboundIP) == 0 {
-		sm.ExecuteAppOnLocal(appInfo)
-	} else {
-		err = sm.executeAppOnRemote(target, appInfo)
+		return sm.ExecuteAppOnLocal(appInfo)
 	}
+	return sm.executeAppOnRemote(target, appInfo)
+}
 
-	return
+// GetServiceLog gives output of service requested by this device from the device executing it,
+// it waits up to wait for new output if there is nothing to read
+func (sm SMMgrImpl) GetServiceLog(serviceID uint64, since uint64, wait time.Duration) (chunk servicelog.Chunk, err error) {
+	value, ok := ServiceMap.Get(serviceID)
+	if !ok {
+		return chunk, errors.NotFound{Message: "service " + strconv.FormatUint(serviceID, 10)}
+	}
+	target, _ := value.(map[string]interface{})[ConstKeyTarget].(string)
+
+	outboundIP, outboundIPErr := networkhelper.GetInstance().GetOutboundIP()
+	if outboundIPErr != nil {
+		outboundIP = ""
+	}
+
+	if strings.Compare(target, outboundIP) == 0 {
+		return servicelog.GetInstance().Read(target, serviceID, since, wait)
+	}
+
+	logs, err := sm.Clienter.DoGetServiceLogRemoteDevice(serviceID, since, wait, target)
+	if err != nil {
+		return
+	}
+	return servicelog.ParseChunk(logs)
 }
 
-// ExecuteAppOnLocal fills out service execution info and deliver it to excutor
-func (sm SMMgrImpl) ExecuteAppOnLocal(appInfo map[string]interface{}) {
+// ExecuteAppOnLocal fills out service execution info and deliver it to the excutor of its execution type
+// if the service does not exceed the limits of local device, otherwise the requester is notified with Rejected
+func (sm SMMgrImpl) ExecuteAppOnLocal(appInfo map[string]interface{}) (err error) {
 	var serviceExecutionInfo executor.ServiceExecutionInfo
 
 	serviceID, serviceName, args, notitargetURL := parseAppInfo(appInfo)
+	stdin, _ := appInfo[ConstKeyStdin].(string)
+	timeout, _ := appInfo[ConstKeyTimeout].(float64)
+	execType, _ := appInfo[ConstKeyExecType].(string)
 
 	serviceExecutionInfo = executor.ServiceExecutionInfo{
 		ServiceID:             serviceID,
 		ServiceName:           serviceName,
 		ParamStr:              args,
-		NotificationTargetURL: notitargetURL}
+		NotificationTargetURL: notitargetURL,
+		Stdin:                 stdin,
+		Timeout:               executionTimeout(time.Duration(timeout * float64(time.Second)))}
+
+	serviceExecutor, err := sm.getServiceExecutor(execType)
+	if err == nil {
+		err = admitService(serviceExecutionInfo)
+	}
+	if err != nil {
+		log.Println(logPrefix, "[ExecuteAppOnLocal]", serviceName, "is rejected :", err.Error())
+		executor.CountExecution(serviceName, ConstServiceStatusRejected)
+		result := notification.ExecutionResult{ServiceID: serviceID, Status: ConstServiceStatusRejected, Reason: err.Error()}
+		go func() {
+			noti := notification.GetInstance()
+			if notiErr := noti.InvokeResultNotification(notitargetURL, result); notiErr != nil {
+				log.Println(logPrefix, notiErr.Error())
+			}
+		}()
+		return
+	}
+
+	go func() {
+		serviceExecutor.Execute(serviceExecutionInfo)
+		runningServiceMap.Remove(serviceID)
+	}()
+
+	return
+}
+
+// NotifyTermination terminates the services running on local device and notifies their requesters that orchestration is stopped,
+// executors notify Terminated for the services which are ended in the grace period and the rest are notified here
+func (sm SMMgrImpl) NotifyTermination() {
+	executor.StopServices()
+	waitRunningServices(executor.TerminationGracePeriod + terminationMargin)
+
+	infos := make([]executor.ServiceExecutionInfo, 0)
+	for item := range runningServiceMap.Iter() {
+		infos = append(infos, item.Value.(executor.ServiceExecutionInfo))
+	}
+
+	noti := notification.GetInstance()
+	for _, info := range infos {
+		log.Println(logPrefix, "[NotifyTermination]", info.ServiceName, info.ServiceID)
+		executor.CountExecution(info.ServiceName, ConstServiceStatusTerminated)
+		err := noti.InvokeNotification(info.NotificationTargetURL, float64(info.ServiceID), ConstServiceStatusTerminated)
+		if err != nil {
+			log.Println(logPrefix, err.Error())
+		}
+	}
 
-	go sm.serviceExecutor.Execute(serviceExecutionInfo)
+	noti.HandleAllNotificationOnLocal(ConstServiceStatusTerminated)
+}
+
+// waitRunningServices waits until every service running on local device is ended or timeout is elapsed
+func waitRunningServices(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for running, _ := countRunningServices(); running != 0 && time.Now().Before(deadline); running, _ = countRunningServices() {
+		time.Sleep(terminationPollInterval)
+	}
 }
 
 func (sm SMMgrImpl) executeAppOnRemote(target string, appInfo map[string]interface{}) (err error) {
@@ -107,7 +259,7 @@ func (sm SMMgrImpl) executeAppOnRemote(target string, appInfo map[string]interfa
 	return
 }
 
-func makeAppInfo(target string, name string, args []interface{}, serviceID float64) (appInfo map[string]interface{}) {
+func makeAppInfo(target string, name string, args []interface{}, opts ExecutionOptions, serviceID float64) (appInfo map[string]interface{}) {
 	appInfo = make(map[string]interface{})
 
 	appInfo[ConstKeyServiceID] = serviceID
@@ -117,6 +269,15 @@ func makeAppInfo(target string, name string, args []interface{}, serviceID float
 	if args != nil {
 		appInfo[ConstKeyUserArgs] = args
 	}
+	if len(opts.Stdin) != 0 {
+		appInfo[ConstKeyStdin] = opts.Stdin
+	}
+	if opts.Timeout > 0 {
+		appInfo[ConstKeyTimeout] = opts.Timeout.Seconds()
+	}
+	if len(opts.ExecType) != 0 {
+		appInfo[ConstKeyExecType] = opts.ExecType
+	}
 
 	return
 }
@@ -24,7 +24,9 @@ import (
 	"time"
 
 	"common/networkhelper"
+	"controller/servicemgr/executor"
 	executorMock "controller/servicemgr/executor/mocks"
+	"controller/servicemgr/servicelog"
 	clientApiMock "restinterface/client/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -68,12 +70,46 @@ func TestExecuteAppOnLocal(t *testing.T) {
 		ifArgs[i] = v
 	}
 
-	err := serviceIns.Execute(targetLocalAddr, serviceName, ifArgs, notiChan)
+	_, err := serviceIns.Execute(targetLocalAddr, serviceName, ifArgs, ExecutionOptions{}, notiChan)
 	checkError(t, err)
 
 	time.Sleep(time.Millisecond * 10)
 }
 
+func TestExecuteAppOnLocalWithExecType(t *testing.T) {
+	serviceIns := GetInstance()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	defaultExec := executorMock.NewMockServiceExecutor(ctrl)
+	containerExec := executorMock.NewMockServiceExecutor(ctrl)
+
+	executed := make(chan struct{})
+	defaultExec.EXPECT().SetClient(gomock.Any())
+	gomock.InOrder(
+		containerExec.EXPECT().SetClient(gomock.Any()),
+		containerExec.EXPECT().Execute(gomock.Any()).DoAndReturn(
+			func(interface{}) error {
+				close(executed)
+				return nil
+			},
+		),
+	)
+
+	serviceIns.SetLocalServiceExecutor(defaultExec)
+	serviceIns.AddLocalServiceExecutor("container", containerExec)
+	defer delete(serviceIns.serviceExecutors, "container")
+
+	appInfo := makeAppInfo(targetLocalAddr, serviceName, paramStr, ExecutionOptions{ExecType: "container"}, float64(0))
+	checkError(t, serviceIns.ExecuteAppOnLocal(appInfo))
+
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Error("service is not executed by the executor of its execution type")
+	}
+}
+
 func TestExecuteAppOnRemote(t *testing.T) {
 	serviceIns := GetInstance()
 
@@ -96,10 +132,53 @@ func TestExecuteAppOnRemote(t *testing.T) {
 	serviceIns.SetLocalServiceExecutor(exec)
 	notiChan := make(chan string)
 
-	err := serviceIns.Execute(targetRemoteAddr, serviceName, paramStrWithArgs, notiChan)
+	_, err := serviceIns.Execute(targetRemoteAddr, serviceName, paramStrWithArgs, ExecutionOptions{}, notiChan)
 	checkError(t, err)
 }
 
+func TestGetServiceLog(t *testing.T) {
+	serviceIns := GetInstance()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := clientApiMock.NewMockClienter(ctrl)
+	serviceIns.Clienter = client
+
+	t.Run("Local", func(t *testing.T) {
+		serviceID := createServiceMap(serviceName, targetLocalAddr)
+		defer deleteServiceMap(serviceID)
+
+		logs := servicelog.GetInstance()
+		logs.Open(targetLocalAddr, serviceID)
+		logs.Append(targetLocalAddr, serviceID, servicelog.StreamStdout, "hello")
+
+		chunk, err := serviceIns.GetServiceLog(serviceID, 0, 0)
+		checkError(t, err)
+		if len(chunk.Lines) != 1 || chunk.Lines[0].Text != "hello" {
+			t.Error("unexpected chunk : ", chunk)
+		}
+	})
+	t.Run("Remote", func(t *testing.T) {
+		serviceID := createServiceMap(serviceName, targetRemoteAddr)
+		defer deleteServiceMap(serviceID)
+
+		chunk := servicelog.Chunk{ServiceID: serviceID, Next: 5, Finished: true}
+		client.EXPECT().DoGetServiceLogRemoteDevice(serviceID, uint64(5), time.Second, targetRemoteAddr).Return(chunk.ToMap(), nil)
+
+		remoteChunk, err := serviceIns.GetServiceLog(serviceID, 5, time.Second)
+		checkError(t, err)
+		if remoteChunk.ServiceID != serviceID || !remoteChunk.Finished {
+			t.Error("unexpected chunk : ", remoteChunk)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		if _, err := serviceIns.GetServiceLog(0, 0, 0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
 /**************** SERVICEMGR REST INIT TEST ***********************/
 //func TestRestInit(t *testing.T) {
 //	//for coverage
@@ -249,3 +328,25 @@ func checkError(t *testing.T, err error) {
 		t.Error(err.Error())
 	}
 }
+
+func TestNotifyTermination(t *testing.T) {
+	info := executor.ServiceExecutionInfo{ServiceID: 1024, ServiceName: serviceName}
+	runningServiceMap.Set(info.ServiceID, info)
+	defer runningServiceMap.Remove(info.ServiceID)
+
+	// @Note : the executor ends the service when orchestration is stopped
+	stopping := executor.Stopping()
+	go func() {
+		<-stopping
+		runningServiceMap.Remove(info.ServiceID)
+	}()
+
+	startTime := time.Now()
+	GetInstance().NotifyTermination()
+	if elapsed := time.Since(startTime); elapsed >= executor.TerminationGracePeriod {
+		t.Error("terminated service is not waited : ", elapsed)
+	}
+	if running, _ := countRunningServices(); running != 0 {
+		t.Error("unexpected running services : ", running)
+	}
+}
@@ -0,0 +1,254 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package servicemgr
+
+import (
+	"log"
+	"time"
+
+	"common/errors"
+	"common/eventbus"
+	"common/metrics"
+	"controller/discoverymgr"
+	"controller/servicemgr/notification"
+)
+
+// Restart policies of services
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+const (
+	// DefaultRestartBackoff is the delay of the first restart if the policy does not give it
+	DefaultRestartBackoff = time.Second
+
+	// MaxRestartBackoff is the maximum delay of restart
+	MaxRestartBackoff = 5 * time.Minute
+
+	// minRestartBackoff keeps the restarted execution apart from the end of the previous one on the device
+	minRestartBackoff = 100 * time.Millisecond
+)
+
+var restartsTotal = metrics.NewCounter(
+	"edge_orchestration_service_restarts_total",
+	"Number of restarts of supervised services by service",
+	"service")
+
+// RestartPolicy decides whether an ended service is executed again
+type RestartPolicy struct {
+	// Policy is one of never (default), on-failure and always
+	Policy string
+	// MaxRetries is the maximum number of restarts with on-failure, there is no maximum if it is 0
+	MaxRetries int
+	// Backoff is the delay of the first restart, it is doubled on every restart up to MaxRestartBackoff
+	Backoff time.Duration
+}
+
+// Placement is the device executing a service, the execution type and the arguments of the service on it
+type Placement struct {
+	DeviceID string
+	Target   string
+	ExecType string
+	Args     []interface{}
+}
+
+// Relocator gives the placement of a supervised service on another device than the lost one
+type Relocator func(lost Placement) (Placement, error)
+
+// supervisor executes a service again by its restart policy, only the last status is delivered to the requester
+type supervisor struct {
+	sm        SMMgrImpl
+	serviceID uint64
+	name      string
+	placement Placement
+	opts      ExecutionOptions
+
+	notiChan chan string
+	statusCh chan string
+
+	restarts int
+	lost     bool
+}
+
+// Validate checks the policy and its parameters
+func (p RestartPolicy) Validate() error {
+	switch p.Policy {
+	case "", RestartNever, RestartOnFailure, RestartAlways:
+	default:
+		return errors.InvalidParam{Message: "unknown restart policy " + p.Policy}
+	}
+
+	if p.MaxRetries < 0 || p.Backoff < 0 {
+		return errors.InvalidParam{Message: "negative restart parameter"}
+	}
+	return nil
+}
+
+func (p RestartPolicy) supervised() bool {
+	return p.Policy == RestartOnFailure || p.Policy == RestartAlways
+}
+
+// shouldRestart tells whether the service ended with status is restarted after restarts,
+// the service canceled by orchestration or terminated by stopping orchestration is not restarted
+func (p RestartPolicy) shouldRestart(status string, restarts int) bool {
+	switch status {
+	case ConstServiceStatusCanceled, ConstServiceStatusTerminated:
+		return false
+	case ConstServiceStatusFinished:
+		return p.Policy == RestartAlways
+	}
+
+	switch p.Policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return p.MaxRetries == 0 || restarts < p.MaxRetries
+	}
+	return false
+}
+
+// backoff gives the delay of the restart after restarts
+func (p RestartPolicy) backoff(restarts int) time.Duration {
+	delay := p.Backoff
+	if delay <= 0 {
+		delay = DefaultRestartBackoff
+	} else if delay < minRestartBackoff {
+		delay = minRestartBackoff
+	}
+
+	for i := 0; i < restarts && delay < MaxRestartBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRestartBackoff {
+		delay = MaxRestartBackoff
+	}
+	return delay
+}
+
+// supervise executes the service and restarts it until it is ended for good,
+// it is relocated by the relocator of opts if its device leaves
+func (sm SMMgrImpl) supervise(serviceID uint64, name string, placement Placement, opts ExecutionOptions, notiChan chan string) error {
+	s := &supervisor{
+		sm:        sm,
+		serviceID: serviceID,
+		name:      name,
+		placement: placement,
+		opts:      opts,
+		notiChan:  notiChan,
+		// @Note : status of stopping orchestration is not blocked while waiting restart
+		statusCh: make(chan string, 1),
+	}
+
+	bus := eventbus.GetInstance()
+	sub := bus.Subscribe(discoverymgr.DeviceEventTopic)
+
+	notification.GetInstance().AddNotificationChan(serviceID, s.statusCh)
+	if err := s.execute(); err != nil {
+		bus.Unsubscribe(sub)
+		return err
+	}
+
+	go s.run(sub)
+	return nil
+}
+
+func (s *supervisor) run(sub *eventbus.Subscriber) {
+	bus := eventbus.GetInstance()
+	defer bus.Unsubscribe(sub)
+
+	var restart <-chan time.Time
+	ended := false
+	for !ended {
+		select {
+		case status := <-s.statusCh:
+			restart, ended = s.handle(status)
+		case <-restart:
+			restart, ended = s.restart()
+		case data := <-sub.C:
+			event, ok := data.(discoverymgr.DeviceEvent)
+			if !ok || event.Type != discoverymgr.DeviceLeft || event.DeviceID != s.placement.DeviceID || s.lost {
+				continue
+			}
+			log.Println(logPrefix, "[supervisor]", "device of", s.name, s.serviceID, "is left :", event.DeviceID)
+			s.lost = true
+			if restart == nil {
+				restart, ended = s.handle(ConstServiceStatusFailed)
+			}
+		}
+	}
+}
+
+// handle gives the timer of restart of the service ended with status,
+// status is delivered to the requester if the service is not restarted
+func (s *supervisor) handle(status string) (restart <-chan time.Time, ended bool) {
+	if !s.opts.Restart.shouldRestart(status, s.restarts) {
+		log.Println(logPrefix, "[supervisor]", s.name, s.serviceID, "is ended :", status)
+		s.notiChan <- status
+		return nil, true
+	}
+
+	delay := s.opts.Restart.backoff(s.restarts)
+	log.Println(logPrefix, "[supervisor]", s.name, s.serviceID, "is", status, ", restarted in", delay)
+
+	// @Note : notification is kept to know stopping orchestration while waiting restart
+	notification.GetInstance().AddNotificationChan(s.serviceID, s.statusCh)
+	return time.After(delay), false
+}
+
+// restart executes the service again, it is relocated first if its device is left
+func (s *supervisor) restart() (restart <-chan time.Time, ended bool) {
+	s.restarts++
+	restartsTotal.Inc(s.name)
+
+	if s.lost {
+		if err := s.relocate(); err != nil {
+			log.Println(logPrefix, "[supervisor]", s.name, s.serviceID, "is not relocated :", err.Error())
+			return s.handle(ConstServiceStatusFailed)
+		}
+	}
+
+	if err := s.execute(); err != nil {
+		log.Println(logPrefix, "[supervisor]", s.name, s.serviceID, "is not restarted :", err.Error())
+		return s.handle(ConstServiceStatusFailed)
+	}
+	return nil, false
+}
+
+func (s *supervisor) relocate() error {
+	if s.opts.Relocate == nil {
+		return errors.NotSupport{Message: "relocation of " + s.name}
+	}
+
+	placement, err := s.opts.Relocate(s.placement)
+	if err != nil {
+		return err
+	}
+
+	log.Println(logPrefix, "[supervisor]", s.name, s.serviceID, "is relocated to", placement.DeviceID, placement.Target)
+	s.placement, s.lost = placement, false
+	s.opts.ExecType = placement.ExecType
+	setServiceMap(s.serviceID, s.name, placement.Target)
+	return nil
+}
+
+func (s *supervisor) execute() error {
+	appInfo := makeAppInfo(s.placement.Target, s.name, s.placement.Args, s.opts, float64(s.serviceID))
+	return s.sm.executeOn(s.placement.Target, appInfo)
+}
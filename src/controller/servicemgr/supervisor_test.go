@@ -0,0 +1,161 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package servicemgr
+
+import (
+	"testing"
+	"time"
+
+	"common/eventbus"
+	"controller/discoverymgr"
+	"controller/servicemgr/notification"
+	clientApiMock "restinterface/client/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestRestartPolicy(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		valid := []RestartPolicy{{}, {Policy: RestartNever}, {Policy: RestartOnFailure, MaxRetries: 3}, {Policy: RestartAlways, Backoff: time.Second}}
+		for _, policy := range valid {
+			if err := policy.Validate(); err != nil {
+				t.Error("unexpected error : ", err.Error())
+			}
+		}
+
+		invalid := []RestartPolicy{{Policy: "unless-stopped"}, {Policy: RestartOnFailure, MaxRetries: -1}, {Policy: RestartAlways, Backoff: -time.Second}}
+		for _, policy := range invalid {
+			if err := policy.Validate(); err == nil {
+				t.Error("expected error : ", policy)
+			}
+		}
+	})
+	t.Run("ShouldRestart", func(t *testing.T) {
+		onFailure := RestartPolicy{Policy: RestartOnFailure, MaxRetries: 2}
+		always := RestartPolicy{Policy: RestartAlways}
+
+		tests := []struct {
+			policy   RestartPolicy
+			status   string
+			restarts int
+			expected bool
+		}{
+			{RestartPolicy{}, ConstServiceStatusFailed, 0, false},
+			{onFailure, ConstServiceStatusFailed, 1, true},
+			{onFailure, ConstServiceStatusTimedOut, 2, false},
+			{onFailure, ConstServiceStatusFinished, 0, false},
+			{always, ConstServiceStatusFinished, 10, true},
+			{always, ConstServiceStatusCanceled, 0, false},
+			{always, ConstServiceStatusTerminated, 0, false},
+		}
+		for _, test := range tests {
+			if restart := test.policy.shouldRestart(test.status, test.restarts); restart != test.expected {
+				t.Error("unexpected restart : ", test)
+			}
+		}
+	})
+	t.Run("Backoff", func(t *testing.T) {
+		policy := RestartPolicy{Policy: RestartAlways}
+		if delay := policy.backoff(2); delay != 4*DefaultRestartBackoff {
+			t.Error("unexpected backoff : ", delay)
+		}
+		if delay := policy.backoff(100); delay != MaxRestartBackoff {
+			t.Error("unexpected backoff : ", delay)
+		}
+
+		policy.Backoff = time.Millisecond
+		if delay := policy.backoff(0); delay != minRestartBackoff {
+			t.Error("unexpected backoff : ", delay)
+		}
+	})
+}
+
+func TestSupervise(t *testing.T) {
+	serviceIns := GetInstance()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := clientApiMock.NewMockClienter(ctrl)
+	serviceIns.Clienter = client
+
+	notify := func(status string) func(appInfo map[string]interface{}, target string) error {
+		return func(appInfo map[string]interface{}, target string) error {
+			serviceID, _ := appInfo[ConstKeyServiceID].(float64)
+			go notification.GetInstance().HandleNotificationOnLocal(serviceID, status)
+			return nil
+		}
+	}
+
+	t.Run("OnFailure", func(t *testing.T) {
+		client.EXPECT().DoExecuteRemoteDevice(gomock.Any(), targetRemoteAddr).DoAndReturn(notify(ConstServiceStatusFailed)).Times(3)
+
+		notiChan := make(chan string)
+		opts := ExecutionOptions{Restart: RestartPolicy{Policy: RestartOnFailure, MaxRetries: 2, Backoff: minRestartBackoff}}
+		serviceID, err := serviceIns.Execute(targetRemoteAddr, serviceName, paramStr, opts, notiChan)
+		checkError(t, err)
+		defer deleteServiceMap(serviceID)
+
+		select {
+		case status := <-notiChan:
+			if status != ConstServiceStatusFailed {
+				t.Error("unexpected status : ", status)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("last status is not delivered")
+		}
+	})
+	t.Run("Relocate", func(t *testing.T) {
+		relocatedAddr := "127.0.0.2"
+		gomock.InOrder(
+			client.EXPECT().DoExecuteRemoteDevice(gomock.Any(), targetRemoteAddr).Return(nil),
+			client.EXPECT().DoExecuteRemoteDevice(gomock.Any(), relocatedAddr).DoAndReturn(notify(ConstServiceStatusFinished)),
+		)
+
+		notiChan := make(chan string)
+		opts := ExecutionOptions{
+			Restart:  RestartPolicy{Policy: RestartOnFailure, Backoff: minRestartBackoff},
+			DeviceID: "device1",
+			Relocate: func(lost Placement) (Placement, error) {
+				if lost.DeviceID != "device1" || lost.Target != targetRemoteAddr {
+					t.Error("unexpected lost placement : ", lost)
+				}
+				return Placement{DeviceID: "device2", Target: relocatedAddr, Args: lost.Args}, nil
+			},
+		}
+		serviceID, err := serviceIns.Execute(targetRemoteAddr, serviceName, paramStr, opts, notiChan)
+		checkError(t, err)
+		defer deleteServiceMap(serviceID)
+
+		eventbus.GetInstance().Publish(discoverymgr.DeviceEventTopic, discoverymgr.DeviceEvent{Type: discoverymgr.DeviceLeft, DeviceID: "device1"})
+
+		select {
+		case status := <-notiChan:
+			if status != ConstServiceStatusFinished {
+				t.Error("unexpected status : ", status)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("last status is not delivered")
+		}
+
+		value, _ := ServiceMap.Get(serviceID)
+		if target := value.(map[string]interface{})[ConstKeyTarget]; target != relocatedAddr {
+			t.Error("unexpected target : ", target)
+		}
+	})
+}
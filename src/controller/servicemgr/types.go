@@ -44,15 +44,45 @@ const (
 	// ConstKeyNotiTargetURL is key of notification target URL
 	ConstKeyNotiTargetURL = "NotificationTargetURL"
 
+	// ConstKeyTarget is key of the device executing service
+	ConstKeyTarget = "Target"
+
+	// ConstKeyStdin is key of the payload of standard input
+	ConstKeyStdin = "Stdin"
+
+	// ConstKeyTimeout is key of the deadline of execution in seconds
+	ConstKeyTimeout = "Timeout"
+
+	// ConstKeyExecType is key of the execution type chosen by scheduling
+	ConstKeyExecType = "ExecutionType"
+
 	// ConstServiceStatusFailed is service status is failed
 	ConstServiceStatusFailed = "Failed"
 
 	// ConstServiceStatusStarted is service status is started
 	ConstServiceStatusStarted = "Started"
 
+	// ConstServiceStatusPulling is service status is pulling the image of container
+	ConstServiceStatusPulling = "Pulling"
+
 	// ConstServiceStatusFinished is service status is finished
 	ConstServiceStatusFinished = "Finished"
 
+	// ConstServiceStatusTerminated is service status is terminated by stopping orchestration
+	ConstServiceStatusTerminated = "Terminated"
+
+	// ConstServiceStatusRejected is service status is rejected by the limits of the device
+	ConstServiceStatusRejected = "Rejected"
+
+	// ConstServiceStatusCanceled is service status is canceled by orchestration
+	ConstServiceStatusCanceled = "Canceled"
+
+	// ConstServiceStatusKilled is service status is killed outside of orchestration
+	ConstServiceStatusKilled = "Killed"
+
+	// ConstServiceStatusTimedOut is service status is terminated by the deadline of execution
+	ConstServiceStatusTimedOut = "TimedOut"
+
 	// ConstServiceFound is service status is found
 	ConstServiceFound = "Found"
 
@@ -98,6 +128,9 @@ var (
 	// ServiceMap is service map
 	ServiceMap ConcurrentMap
 
+	// runningServiceMap is map of services executing on local (serviceID / ServiceExecutionInfo)
+	runningServiceMap ConcurrentMap
+
 	// ServiceIdx is for unique service ID (process id)
 	ServiceIdx uint64
 )
@@ -145,16 +178,20 @@ func (cm *ConcurrentMap) Iter() <-chan ConcurrentMapItem {
 	return c
 }
 
-func createServiceMap(name string) uint64 {
+func createServiceMap(name string, target string) uint64 {
 	serviceID := getServiceIdx()
+	setServiceMap(serviceID, name, target)
+
+	return serviceID
+}
 
+func setServiceMap(serviceID uint64, name string, target string) {
 	value := make(map[string]interface{})
 
 	value[ConstKeyServiceName] = name
+	value[ConstKeyTarget] = target
 
 	ServiceMap.Set(serviceID, value)
-
-	return serviceID
 }
 
 func deleteServiceMap(serviceID uint64) {
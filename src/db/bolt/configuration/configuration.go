@@ -26,9 +26,11 @@ import (
 const bucketName = "configuration"
 
 type Configuration struct {
-	ID       string `json:"id"`
-	Platform string `json:"platform"`
-	ExecType string `json:"executionType"`
+	ID        string            `json:"id"`
+	Platform  string            `json:"platform"`
+	ExecType  string            `json:"executionType"`
+	ExecTypes []string          `json:"executionTypes,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
 }
 
 type DBInterface interface {
@@ -107,6 +109,8 @@ func (Query) Update(conf Configuration) error {
 
 	stored.Platform = conf.Platform
 	stored.ExecType = conf.ExecType
+	stored.ExecTypes = conf.ExecTypes
+	stored.Labels = conf.Labels
 
 	encoded, err := stored.encode()
 	if err != nil {
@@ -120,11 +124,25 @@ func (Query) Delete(id string) error {
 	return db.Delete([]byte(id))
 }
 
+// SupportedExecTypes returns every execution type supported by the device,
+// the device known before supporting several execution types supports ExecType only
+func (conf Configuration) SupportedExecTypes() []string {
+	if len(conf.ExecTypes) != 0 {
+		return conf.ExecTypes
+	}
+	if len(conf.ExecType) != 0 {
+		return []string{conf.ExecType}
+	}
+	return nil
+}
+
 func (conf Configuration) convertToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"id":            conf.ID,
-		"platform":      conf.Platform,
-		"executionType": conf.ExecType,
+		"id":             conf.ID,
+		"platform":       conf.Platform,
+		"executionType":  conf.ExecType,
+		"executionTypes": conf.SupportedExecTypes(),
+		"labels":         conf.Labels,
 	}
 }
 
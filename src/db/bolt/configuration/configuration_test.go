@@ -299,3 +299,15 @@ func TestDelete_WithInvalidID_ExpectedErrorReturn(t *testing.T) {
 	case errors.NotFound:
 	}
 }
+
+func TestSupportedExecTypes(t *testing.T) {
+	if execTypes := confStruct.SupportedExecTypes(); !reflect.DeepEqual(execTypes, []string{executionType}) {
+		t.Errorf("Unexpected exec types: %v", execTypes)
+	}
+
+	multiple := confStruct
+	multiple.ExecTypes = []string{executionType, executionType2}
+	if execTypes := multiple.SupportedExecTypes(); !reflect.DeepEqual(execTypes, multiple.ExecTypes) {
+		t.Errorf("Unexpected exec types: %v", execTypes)
+	}
+}
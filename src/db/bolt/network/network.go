@@ -27,9 +27,22 @@ import (
 const bucketName = "network"
 
 type NetworkInfo struct {
-	ID   string   `json:"id"`
-	IPv4 []string `json:"IPv4"`
-	RTT  float64  `json:"RTT"`
+	ID          string                 `json:"id"`
+	IPv4        []string               `json:"IPv4"`
+	RTT         float64                `json:"RTT"`
+	Jitter      float64                `json:"jitter"`
+	Loss        float64                `json:"loss"`
+	Unreachable bool                   `json:"unreachable"`
+	Probes      map[string]ProbeResult `json:"probes,omitempty"`
+	Bandwidth   float64                `json:"bandwidth"`
+}
+
+// ProbeResult is the statistics of round trip time probes to an address,
+// RTT and Jitter are seconds and Loss is the ratio of failed probes
+type ProbeResult struct {
+	RTT    float64 `json:"RTT"`
+	Jitter float64 `json:"jitter"`
+	Loss   float64 `json:"loss"`
 }
 
 type DBInterface interface {
@@ -130,6 +143,16 @@ func (Query) Update(info NetworkInfo) error {
 	if info.RTT != 0.0 {
 		stored.RTT = info.RTT
 	}
+	if info.Probes != nil {
+		stored.RTT = info.RTT
+		stored.Jitter = info.Jitter
+		stored.Loss = info.Loss
+		stored.Unreachable = info.Unreachable
+		stored.Probes = info.Probes
+	}
+	if info.Bandwidth != 0.0 {
+		stored.Bandwidth = info.Bandwidth
+	}
 
 	encoded, err := stored.encode()
 	if err != nil {
@@ -145,9 +168,14 @@ func (Query) Delete(id string) error {
 
 func (info NetworkInfo) convertToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"id":   info.ID,
-		"IPv4": info.IPv4,
-		"RTT":  info.RTT,
+		"id":          info.ID,
+		"IPv4":        info.IPv4,
+		"RTT":         info.RTT,
+		"jitter":      info.Jitter,
+		"loss":        info.Loss,
+		"unreachable": info.Unreachable,
+		"probes":      info.Probes,
+		"bandwidth":   info.Bandwidth,
 	}
 }
 
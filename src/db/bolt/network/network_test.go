@@ -231,6 +231,44 @@ func TestUpdate_ExpectedSuccess(t *testing.T) {
 	}
 }
 
+func TestUpdate_WithProbes_ExpectedSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wrapperMockObj := wrapperMock.NewMockDatabase(ctrl)
+
+	stored := netStruct2
+	stored.Bandwidth = 100.0
+	storedByte, _ := json.Marshal(stored)
+
+	probed := NetworkInfo{
+		ID:          validID,
+		Unreachable: true,
+		Loss:        1.0,
+		Probes:      map[string]ProbeResult{"192.168.0.1": {Loss: 1.0}},
+	}
+
+	expected := stored
+	expected.RTT = 0.0
+	expected.Loss = 1.0
+	expected.Unreachable = true
+	expected.Probes = probed.Probes
+	expectedByte, _ := json.Marshal(expected)
+
+	gomock.InOrder(
+		wrapperMockObj.EXPECT().Get([]byte(validID)).Return(storedByte, nil),
+		wrapperMockObj.EXPECT().Put([]byte(validID), expectedByte).Return(nil),
+	)
+
+	db = wrapperMockObj
+	query := Query{}
+
+	err := query.Update(probed)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
 func TestUpdate_WhenNotfoundMatchedConfWithID_ExpectedErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
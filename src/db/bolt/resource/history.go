@@ -0,0 +1,189 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"common/errors"
+	bolt "db/bolt/wrapper"
+)
+
+const (
+	historyBucketName = "resourcehistory"
+
+	// DefaultRetention is the number of samples kept for each resource by default
+	DefaultRetention = 120
+)
+
+// Sample is a value of resource at a point of time
+type Sample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// History has the samples of a resource in a window with their statistics
+type History struct {
+	Name    string
+	Window  time.Duration
+	Samples []Sample
+	Count   int
+	Latest  float64
+	Avg     float64
+	Min     float64
+	Max     float64
+	P95     float64
+}
+
+// ring keeps the last samples of a resource, Next is the oldest sample once it is full
+type ring struct {
+	Samples []Sample `json:"samples"`
+	Next    int      `json:"next"`
+}
+
+var (
+	historyDB bolt.Database
+
+	retentionMtx sync.Mutex
+	retention    = DefaultRetention
+
+	now = time.Now
+)
+
+func init() {
+	historyDB = bolt.NewBoltDB(historyBucketName)
+}
+
+// SetRetention sets the number of samples kept for each resource
+func SetRetention(size int) error {
+	if size < 1 {
+		return errors.InvalidParam{Message: "retention should be positive"}
+	}
+
+	retentionMtx.Lock()
+	defer retentionMtx.Unlock()
+
+	retention = size
+	return nil
+}
+
+func getRetention() int {
+	retentionMtx.Lock()
+	defer retentionMtx.Unlock()
+
+	return retention
+}
+
+// GetHistory returns the samples of the resource in the last window with their statistics,
+// every sample is returned if window is not positive
+func (Query) GetHistory(name string, window time.Duration) (History, error) {
+	history := History{Name: name, Window: window}
+
+	value, err := historyDB.Get([]byte(name))
+	if err != nil {
+		return history, err
+	}
+
+	var r ring
+	if err = json.Unmarshal(value, &r); err != nil {
+		return history, errors.InvalidJSON{Message: err.Error()}
+	}
+
+	from := now().Add(-window)
+	for _, sample := range r.ordered() {
+		if window <= 0 || sample.Time.After(from) {
+			history.Samples = append(history.Samples, sample)
+		}
+	}
+	history.summarize()
+
+	return history, nil
+}
+
+func appendHistory(info ResourceInfo) error {
+	var r ring
+
+	value, err := historyDB.Get([]byte(info.Name))
+	switch err.(type) {
+	case nil:
+		if err = json.Unmarshal(value, &r); err != nil {
+			return errors.InvalidJSON{Message: err.Error()}
+		}
+	case errors.NotFound:
+	default:
+		return err
+	}
+
+	r.add(Sample{Time: now(), Value: info.Value}, getRetention())
+
+	encoded, err := json.Marshal(r)
+	if err != nil {
+		return errors.InvalidJSON{Message: err.Error()}
+	}
+	return historyDB.Put([]byte(info.Name), encoded)
+}
+
+func (r *ring) add(sample Sample, size int) {
+	if len(r.Samples) > size || (len(r.Samples) < size && r.Next != 0) {
+		ordered := r.ordered()
+		if len(ordered) > size {
+			ordered = ordered[len(ordered)-size:]
+		}
+		r.Samples, r.Next = ordered, 0
+	}
+
+	if len(r.Samples) < size {
+		r.Samples = append(r.Samples, sample)
+		return
+	}
+	r.Samples[r.Next] = sample
+	r.Next = (r.Next + 1) % size
+}
+
+// ordered returns the samples from the oldest one
+func (r ring) ordered() []Sample {
+	if r.Next <= 0 || r.Next >= len(r.Samples) {
+		return append([]Sample{}, r.Samples...)
+	}
+	return append(append([]Sample{}, r.Samples[r.Next:]...), r.Samples[:r.Next]...)
+}
+
+func (h *History) summarize() {
+	h.Count = len(h.Samples)
+	if h.Count == 0 {
+		return
+	}
+
+	values := make([]float64, h.Count)
+	var total float64
+	for idx, sample := range h.Samples {
+		values[idx] = sample.Value
+		total += sample.Value
+	}
+	sort.Float64s(values)
+
+	h.Latest = h.Samples[h.Count-1].Value
+	h.Avg = total / float64(h.Count)
+	h.Min = values[0]
+	h.Max = values[h.Count-1]
+	h.P95 = values[int(math.Ceil(0.95*float64(h.Count)))-1]
+}
@@ -0,0 +1,136 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	wrapperMock "db/bolt/wrapper/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestRingAdd(t *testing.T) {
+	sample := func(value float64) Sample {
+		return Sample{Time: time.Unix(int64(value), 0), Value: value}
+	}
+	values := func(r ring) (out []float64) {
+		for _, s := range r.ordered() {
+			out = append(out, s.Value)
+		}
+		return
+	}
+	equal := func(a, b []float64) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for idx := range a {
+			if a[idx] != b[idx] {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("Wrap", func(t *testing.T) {
+		var r ring
+		for value := 1.0; value <= 5; value++ {
+			r.add(sample(value), 3)
+		}
+		if len(r.Samples) != 3 || !equal(values(r), []float64{3, 4, 5}) {
+			t.Error("unexpected samples : ", values(r))
+		}
+	})
+	t.Run("Shrink", func(t *testing.T) {
+		var r ring
+		for value := 1.0; value <= 4; value++ {
+			r.add(sample(value), 3)
+		}
+		r.add(sample(5), 2)
+		if !equal(values(r), []float64{4, 5}) {
+			t.Error("unexpected samples : ", values(r))
+		}
+	})
+	t.Run("Grow", func(t *testing.T) {
+		var r ring
+		for value := 1.0; value <= 4; value++ {
+			r.add(sample(value), 3)
+		}
+		r.add(sample(5), 5)
+		r.add(sample(6), 5)
+		if !equal(values(r), []float64{2, 3, 4, 5, 6}) {
+			t.Error("unexpected samples : ", values(r))
+		}
+	})
+}
+
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyMockObj := wrapperMock.NewMockDatabase(ctrl)
+	historyDB = historyMockObj
+
+	base := time.Now()
+	now = func() time.Time { return base }
+	defer func() { now = time.Now }()
+
+	var r ring
+	for idx := 1; idx <= 20; idx++ {
+		r.add(Sample{Time: base.Add(time.Duration(idx-20) * time.Second), Value: float64(idx)}, 10)
+	}
+	encoded, _ := json.Marshal(r)
+
+	t.Run("Window", func(t *testing.T) {
+		historyMockObj.EXPECT().Get([]byte(validName)).Return(encoded, nil)
+
+		history, err := Query{}.GetHistory(validName, 5*time.Second)
+		if err != nil {
+			t.Fatal("unexpected error : ", err.Error())
+		}
+		if history.Count != 5 || history.Latest != 20 || history.Min != 16 ||
+			history.Max != 20 || history.Avg != 18 || history.P95 != 20 {
+			t.Error("unexpected history : ", history)
+		}
+	})
+	t.Run("All", func(t *testing.T) {
+		historyMockObj.EXPECT().Get([]byte(validName)).Return(encoded, nil)
+
+		history, err := Query{}.GetHistory(validName, 0)
+		if err != nil {
+			t.Fatal("unexpected error : ", err.Error())
+		}
+		if history.Count != 10 || history.Min != 11 || history.P95 != 20 || history.Samples[0].Value != 11 {
+			t.Error("unexpected history : ", history)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		historyMockObj.EXPECT().Get([]byte(invalidName)).Return(nil, notFoundErr)
+
+		if _, err := (Query{}).GetHistory(invalidName, 0); err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("InvalidRetention", func(t *testing.T) {
+		if err := SetRetention(0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -23,6 +23,7 @@ package mock_resource
 import (
 	resource "db/bolt/resource"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -92,3 +93,18 @@ func (mr *MockDBInterfaceMockRecorder) Delete(name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDBInterface)(nil).Delete), name)
 }
+
+// GetHistory mocks base method
+func (m *MockDBInterface) GetHistory(name string, window time.Duration) (resource.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", name, window)
+	ret0, _ := ret[0].(resource.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory
+func (mr *MockDBInterfaceMockRecorder) GetHistory(name, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockDBInterface)(nil).GetHistory), name, window)
+}
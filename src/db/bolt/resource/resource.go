@@ -19,6 +19,7 @@ package resource
 
 import (
 	"encoding/json"
+	"time"
 
 	"common/errors"
 	bolt "db/bolt/wrapper"
@@ -35,6 +36,7 @@ type DBInterface interface {
 	Get(id string) (ResourceInfo, error)
 	Set(info ResourceInfo) error
 	Delete(name string) error
+	GetHistory(name string, window time.Duration) (History, error)
 }
 
 type Query struct {
@@ -72,7 +74,7 @@ func (Query) Set(info ResourceInfo) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return appendHistory(info)
 }
 
 func (Query) Delete(name string) error {
@@ -112,14 +112,18 @@ func TestSet_ExpectedSuccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	wrapperMockObj := wrapperMock.NewMockDatabase(ctrl)
+	historyMockObj := wrapperMock.NewMockDatabase(ctrl)
 
 	resourceByte, _ := json.Marshal(resourceStruct)
 
 	gomock.InOrder(
 		wrapperMockObj.EXPECT().Put([]byte(resourceStruct.Name), resourceByte).Return(nil),
+		historyMockObj.EXPECT().Get([]byte(resourceStruct.Name)).Return(nil, notFoundErr),
+		historyMockObj.EXPECT().Put([]byte(resourceStruct.Name), gomock.Any()).Return(nil),
 	)
 
 	db = wrapperMockObj
+	historyDB = historyMockObj
 	query := Query{}
 
 	err := query.Set(resourceStruct)
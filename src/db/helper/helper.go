@@ -1,8 +1,6 @@
 package helper
 
 import (
-	"strings"
-
 	errormsg "common/errormsg"
 	errors "common/errors"
 	"db/bolt/common"
@@ -24,13 +22,40 @@ func init() {
 }
 
 type MultipleBucketQuery interface {
-	GetDeviceInfoWithService(serviceName string, executionTypes []string) ([]ExecutionCandidate, error)
+	GetDeviceInfoWithService(serviceName string, executionTypes []string, selector LabelSelector) ([]ExecutionCandidate, error)
+	ExplainDeviceInfoWithService(serviceName string, executionTypes []string, selector LabelSelector) ([]ExecutionCandidate, []ExcludedDevice, error)
 }
 
 type ExecutionCandidate struct {
 	Id       string
 	ExecType string
 	Endpoint []string
+	Labels   map[string]string
+}
+
+// ExcludedDevice is a device which was not chosen as a candidate with the reason
+type ExcludedDevice struct {
+	Id       string
+	ExecType string
+	Labels   map[string]string
+	Reason   string
+}
+
+// Reasons why a device is excluded from the candidates
+const (
+	ExcludedByExecType        = "execution type is not requested"
+	ExcludedByRequiredLabels  = "required labels are not matched"
+	ExcludedByPreferredLabels = "preferred labels are not matched"
+	ExcludedByService         = "service is not installed"
+	ExcludedByEndpoint        = "no endpoint is known"
+)
+
+// LabelSelector has the device labels which candidates are filtered with.
+// A device should have every Required label and devices having every Preferred label
+// are chosen if there is any. An empty label value matches any value of the key.
+type LabelSelector struct {
+	Required  map[string]string
+	Preferred map[string]string
 }
 
 type multipleBucketQuery struct{}
@@ -41,66 +66,152 @@ func GetInstance() MultipleBucketQuery {
 	return query
 }
 
-func (multipleBucketQuery) GetDeviceInfoWithService(serviceName string, executionTypes []string) ([]ExecutionCandidate, error) {
+func (multipleBucketQuery) GetDeviceInfoWithService(serviceName string, executionTypes []string, selector LabelSelector) ([]ExecutionCandidate, error) {
+	ret, _, err := evaluateDevices(serviceName, executionTypes, selector)
+	return ret, err
+}
+
+// ExplainDeviceInfoWithService returns the candidates like GetDeviceInfoWithService
+// together with every device which was excluded and the reason of it
+func (multipleBucketQuery) ExplainDeviceInfoWithService(serviceName string, executionTypes []string, selector LabelSelector) ([]ExecutionCandidate, []ExcludedDevice, error) {
+	return evaluateDevices(serviceName, executionTypes, selector)
+}
+
+func evaluateDevices(serviceName string, executionTypes []string, selector LabelSelector) ([]ExecutionCandidate, []ExcludedDevice, error) {
 	ret := make([]ExecutionCandidate, 0)
+	excluded := make([]ExcludedDevice, 0)
 
 	confItems, err := confQuery.GetList()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, confItem := range confItems {
-		hasExecType := common.HasElem(executionTypes, confItem.ExecType)
-		if hasExecType == false {
-			continue
+		exclude := func(reason string) {
+			excluded = append(excluded, ExcludedDevice{
+				Id:       confItem.ID,
+				ExecType: confItem.ExecType,
+				Labels:   confItem.Labels,
+				Reason:   reason,
+			})
 		}
 
-		if confItem.ExecType == "container" {
-			endpoints, err := getEndpoints(confItem.ID)
-			if err != nil {
-				continue
-			}
+		execTypes := sharedExecTypes(executionTypes, confItem.SupportedExecTypes())
+		if len(execTypes) == 0 {
+			exclude(ExcludedByExecType)
+			continue
+		}
 
-			info := ExecutionCandidate{
-				Id:       confItem.ID,
-				ExecType: confItem.ExecType,
-				Endpoint: endpoints,
-			}
+		if matchLabels(confItem.Labels, selector.Required) == false {
+			exclude(ExcludedByRequiredLabels)
+			continue
+		}
 
-			ret = append(ret, info)
+		execType, err := selectExecType(serviceName, confItem.ID, execTypes)
+		if err != nil {
+			return nil, nil, err
+		} else if len(execType) == 0 {
+			exclude(ExcludedByService)
 			continue
 		}
 
-		serviceItem, err := serviceQuery.Get(confItem.ID)
+		endpoints, err := getEndpoints(confItem.ID)
 		if err != nil {
-			return nil, err
+			exclude(ExcludedByEndpoint)
+			continue
 		}
 
-		for _, service := range serviceItem.Services {
-			if strings.Compare(serviceName, service) == 0 {
-				endpoints, err := getEndpoints(serviceItem.ID)
-				if err != nil {
-					continue
-				}
-
-				info := ExecutionCandidate{
-					Id:       serviceItem.ID,
-					ExecType: confItem.ExecType,
-					Endpoint: endpoints,
-				}
-
-				ret = append(ret, info)
-				break
-			}
+		info := ExecutionCandidate{
+			Id:       confItem.ID,
+			ExecType: execType,
+			Endpoint: endpoints,
+			Labels:   confItem.Labels,
 		}
+
+		ret = append(ret, info)
 	}
 
 	if len(ret) == 0 {
 		err = errors.NotFound{Message: errormsg.ToString(errormsg.ErrorNoDeviceReturn)}
-		return nil, err
+		return nil, excluded, err
+	}
+
+	ret, dropped := splitPreferred(ret, selector.Preferred)
+	for _, candidate := range dropped {
+		excluded = append(excluded, ExcludedDevice{
+			Id:       candidate.Id,
+			ExecType: candidate.ExecType,
+			Labels:   candidate.Labels,
+			Reason:   ExcludedByPreferredLabels,
+		})
+	}
+
+	return ret, excluded, nil
+}
+
+// sharedExecTypes returns the requested execution types supported by the device in the requested order
+func sharedExecTypes(requested []string, supported []string) []string {
+	ret := make([]string, 0)
+	for _, execType := range requested {
+		if common.HasElem(supported, execType) && !common.HasElem(ret, execType) {
+			ret = append(ret, execType)
+		}
 	}
+	return ret
+}
+
+// selectExecType returns the first execution type which can run the service on the device,
+// a container is always runnable while the other types need the service to be installed
+func selectExecType(serviceName string, id string, execTypes []string) (string, error) {
+	var services []string
+	for _, execType := range execTypes {
+		if execType == "container" {
+			return execType, nil
+		}
+
+		if services == nil {
+			serviceItem, err := serviceQuery.Get(id)
+			if err != nil {
+				return "", err
+			}
+			services = serviceItem.Services
+		}
 
-	return ret, nil
+		if common.HasElem(services, serviceName) {
+			return execType, nil
+		}
+	}
+	return "", nil
+}
+
+func splitPreferred(candidates []ExecutionCandidate, preferred map[string]string) (ret []ExecutionCandidate, dropped []ExecutionCandidate) {
+	if len(preferred) == 0 {
+		return candidates, nil
+	}
+
+	ret = make([]ExecutionCandidate, 0)
+	dropped = make([]ExecutionCandidate, 0)
+	for _, candidate := range candidates {
+		if matchLabels(candidate.Labels, preferred) {
+			ret = append(ret, candidate)
+		} else {
+			dropped = append(dropped, candidate)
+		}
+	}
+
+	if len(ret) == 0 {
+		return candidates, nil
+	}
+	return ret, dropped
+}
+func matchLabels(labels map[string]string, selector map[string]string) bool {
+	for key, value := range selector {
+		label, ok := labels[key]
+		if !ok || (len(value) != 0 && label != value) {
+			return false
+		}
+	}
+	return true
 }
 
 func getEndpoints(id string) ([]string, error) {
@@ -16,3 +16,76 @@
  *******************************************************************************/
 
 package helper
+
+import (
+	"testing"
+)
+
+var (
+	livingRoomCamera = ExecutionCandidate{
+		Id:     "ID1",
+		Labels: map[string]string{"room": "living", "camera": "true"},
+	}
+	kitchenDevice = ExecutionCandidate{
+		Id:     "ID2",
+		Labels: map[string]string{"room": "kitchen"},
+	}
+)
+
+func TestMatchLabels(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		if matchLabels(livingRoomCamera.Labels, nil) == false {
+			t.Error("empty selector should match every device")
+		}
+		if matchLabels(livingRoomCamera.Labels, map[string]string{"room": "living"}) == false {
+			t.Error("unexpected mismatch with same label")
+		}
+		if matchLabels(livingRoomCamera.Labels, map[string]string{"camera": ""}) == false {
+			t.Error("unexpected mismatch with empty label value")
+		}
+	})
+	t.Run("Fail", func(t *testing.T) {
+		if matchLabels(kitchenDevice.Labels, map[string]string{"room": "living"}) == true {
+			t.Error("unexpected match with different label value")
+		}
+		if matchLabels(kitchenDevice.Labels, map[string]string{"camera": ""}) == true {
+			t.Error("unexpected match without label key")
+		}
+	})
+}
+
+func TestSplitPreferred(t *testing.T) {
+	candidates := []ExecutionCandidate{livingRoomCamera, kitchenDevice}
+
+	t.Run("Matched", func(t *testing.T) {
+		ret, dropped := splitPreferred(candidates, map[string]string{"room": "kitchen"})
+		if len(ret) != 1 || ret[0].Id != kitchenDevice.Id {
+			t.Error("unexpected candidates : ", ret)
+		}
+		if len(dropped) != 1 || dropped[0].Id != livingRoomCamera.Id {
+			t.Error("unexpected dropped candidates : ", dropped)
+		}
+	})
+	t.Run("NotMatched", func(t *testing.T) {
+		ret, dropped := splitPreferred(candidates, map[string]string{"room": "bedroom"})
+		if len(ret) != len(candidates) || len(dropped) != 0 {
+			t.Error("unexpected candidates : ", ret, dropped)
+		}
+	})
+}
+
+func TestSharedExecTypes(t *testing.T) {
+	supported := []string{"native", "container"}
+
+	t.Run("RequestedOrder", func(t *testing.T) {
+		ret := sharedExecTypes([]string{"container", "android", "native"}, supported)
+		if len(ret) != 2 || ret[0] != "container" || ret[1] != "native" {
+			t.Error("unexpected exec types : ", ret)
+		}
+	})
+	t.Run("NotShared", func(t *testing.T) {
+		if ret := sharedExecTypes([]string{"android"}, supported); len(ret) != 0 {
+			t.Error("unexpected exec types : ", ret)
+		}
+	})
+}
@@ -52,16 +52,32 @@ func (m *MockMultipleBucketQuery) EXPECT() *MockMultipleBucketQueryMockRecorder
 }
 
 // GetDeviceInfoWithService mocks base method
-func (m *MockMultipleBucketQuery) GetDeviceInfoWithService(serviceName string, executionTypes []string) ([]helper.ExecutionCandidate, error) {
+func (m *MockMultipleBucketQuery) GetDeviceInfoWithService(serviceName string, executionTypes []string, selector helper.LabelSelector) ([]helper.ExecutionCandidate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDeviceInfoWithService", serviceName, executionTypes)
+	ret := m.ctrl.Call(m, "GetDeviceInfoWithService", serviceName, executionTypes, selector)
 	ret0, _ := ret[0].([]helper.ExecutionCandidate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDeviceInfoWithService indicates an expected call of GetDeviceInfoWithService
-func (mr *MockMultipleBucketQueryMockRecorder) GetDeviceInfoWithService(serviceName, executionTypes interface{}) *gomock.Call {
+func (mr *MockMultipleBucketQueryMockRecorder) GetDeviceInfoWithService(serviceName, executionTypes, selector interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceInfoWithService", reflect.TypeOf((*MockMultipleBucketQuery)(nil).GetDeviceInfoWithService), serviceName, executionTypes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceInfoWithService", reflect.TypeOf((*MockMultipleBucketQuery)(nil).GetDeviceInfoWithService), serviceName, executionTypes, selector)
+}
+
+// ExplainDeviceInfoWithService mocks base method
+func (m *MockMultipleBucketQuery) ExplainDeviceInfoWithService(serviceName string, executionTypes []string, selector helper.LabelSelector) ([]helper.ExecutionCandidate, []helper.ExcludedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainDeviceInfoWithService", serviceName, executionTypes, selector)
+	ret0, _ := ret[0].([]helper.ExecutionCandidate)
+	ret1, _ := ret[1].([]helper.ExcludedDevice)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExplainDeviceInfoWithService indicates an expected call of ExplainDeviceInfoWithService
+func (mr *MockMultipleBucketQueryMockRecorder) ExplainDeviceInfoWithService(serviceName, executionTypes, selector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainDeviceInfoWithService", reflect.TypeOf((*MockMultipleBucketQuery)(nil).ExplainDeviceInfoWithService), serviceName, executionTypes, selector)
 }
@@ -0,0 +1,52 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package main
+
+//#include <stdlib.h>
+//
+//typedef void (*DeviceEventCallback)(char* eventType, char* deviceID);
+//
+//static void invokeDeviceEventCallback(DeviceEventCallback cb, char* eventType, char* deviceID) {
+//	cb(eventType, deviceID);
+//}
+import "C"
+import (
+	"log"
+	"sync"
+	"unsafe"
+
+	"controller/discoverymgr"
+)
+
+var (
+	deviceEventMtx    sync.Mutex
+	deviceEventCancel func()
+)
+
+// deliverDeviceEvents calls C callback for every device event until events is closed
+func deliverDeviceEvents(cb C.DeviceEventCallback, events <-chan discoverymgr.DeviceEvent) {
+	for event := range events {
+		log.Printf("[%s] device event : %s %s", logPrefix, event.Type, event.DeviceID)
+
+		cEventType := C.CString(event.Type)
+		cDeviceID := C.CString(event.DeviceID)
+		C.invokeDeviceEventCallback(cb, cEventType, cDeviceID)
+		C.free(unsafe.Pointer(cEventType))
+		C.free(unsafe.Pointer(cDeviceID))
+	}
+}
@@ -55,23 +55,38 @@ package main
 //} TargetInfo;
 //
 //typedef struct {
+//	int   Replicas;
+//	char* SpreadPolicy;
+//	char* RequiredLabels;
+//	char* PreferredLabels;
+//} RequestServiceOptions;
+//
+//typedef struct {
 //	char*      Message;
 //	char*      ServiceName;
 //	TargetInfo RemoteTargetInfo;
+//	char*      GroupID;
 //} ResponseService;
+//
+//typedef void (*DeviceEventCallback)(char* eventType, char* deviceID);
 import "C"
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"log"
 	"math"
 	"strings"
 	"sync"
+	"time"
 	"unsafe"
 
 	"common/logmgr"
+	"common/resourceutil"
 
 	configuremgr "controller/configuremgr/native"
 	"controller/discoverymgr"
+	"controller/schedulermgr"
 	scoringmgr "controller/scoringmgr"
 	"controller/servicemgr"
 	"controller/servicemgr/executor/nativeexecutor"
@@ -99,21 +114,29 @@ const (
 
 	configPath = edgeDir + "apps"
 
-	cipherKeyFilePath = edgeDir + "orchestration_userID.txt"
-	deviceIDFilePath  = edgeDir + "orchestration_deviceID.txt"
+	cipherKeyFilePath        = edgeDir + "orchestration_userID.txt"
+	deviceIDFilePath         = edgeDir + "orchestration_deviceID.txt"
+	deviceLabelFilePath      = edgeDir + "orchestration_labels.txt"
+	deviceLimitFilePath      = edgeDir + "orchestration_limits.txt"
+	monitoringConfigFilePath = edgeDir + "orchestration_monitoring.txt"
+
+	shutdownTimeout = 10 * time.Second
 )
 
 var (
 	flagVersion                  bool
+	flagScheduler                string
 	commitID, version, buildTime string
 
-	orcheEngine orchestrationapi.Orche
+	orcheEngine    orchestrationapi.Orche
+	restEdgeRouter *route.RestRouter
 )
 
 //export OrchestrationInit
 func OrchestrationInit() (errCode C.int) {
 	flag.BoolVar(&flagVersion, "v", false, "if true, print version and exit")
 	flag.BoolVar(&flagVersion, "version", false, "if true, print version and exit")
+	flag.StringVar(&flagScheduler, "scheduler", schedulermgr.BestScore, "default scheduling policy of service requests")
 	flag.Parse()
 
 	logmgr.Init(logPath)
@@ -127,6 +150,22 @@ func OrchestrationInit() (errCode C.int) {
 	restIns.SetCipher(sha256.GetCipher(cipherKeyFilePath))
 
 	servicemgr.GetInstance().SetClient(restIns)
+	if limits, err := servicemgr.ReadLimits(deviceLimitFilePath); err == nil {
+		servicemgr.GetInstance().SetLimits(limits)
+	}
+
+	if intervals, err := resourceutil.ReadCollectorIntervals(monitoringConfigFilePath); err == nil {
+		for name, interval := range intervals {
+			if err := resourceutil.SetCollectorInterval(name, interval); err != nil {
+				log.Printf("[%s] %s", logPrefix, err.Error())
+			}
+		}
+	}
+
+	if err := schedulermgr.GetInstance().SetDefault(flagScheduler); err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		return -1
+	}
 
 	builder := orchestrationapi.OrchestrationBuilder{}
 	builder.SetWatcher(configuremgr.GetInstance(configPath))
@@ -134,6 +173,7 @@ func OrchestrationInit() (errCode C.int) {
 	builder.SetScoring(scoringmgr.GetInstance())
 	builder.SetService(servicemgr.GetInstance())
 	builder.SetExecutor(nativeexecutor.GetInstance())
+	builder.AddExecutor(executionType, nativeexecutor.GetInstance())
 	builder.SetClient(restIns)
 	orcheEngine = builder.Build()
 	if orcheEngine == nil {
@@ -141,9 +181,9 @@ func OrchestrationInit() (errCode C.int) {
 		return
 	}
 
-	orcheEngine.Start(deviceIDFilePath, platform, executionType)
+	orcheEngine.Start(deviceIDFilePath, platform, executionType, deviceLabelFilePath)
 
-	restEdgeRouter := route.NewRestRouter()
+	restEdgeRouter = route.NewRestRouter()
 
 	internalapi, err := orchestrationapi.GetInternalAPI()
 	if err != nil {
@@ -161,12 +201,149 @@ func OrchestrationInit() (errCode C.int) {
 	return
 }
 
+//export OrchestrationDeinit
+func OrchestrationDeinit() (errCode C.int) {
+	log.Printf("[%s] OrchestrationDeinit", logPrefix)
+	if orcheEngine == nil {
+		return -1
+	}
+
+	OrchestrationUnsubscribeDeviceEvent()
+
+	// every stage has its own deadline, an open stream should not expire the next stage
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	err := restEdgeRouter.Stop(ctx)
+	cancel()
+	if err != nil {
+		log.Printf("[%s] REST server shutdown : %s", logPrefix, err.Error())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := orcheEngine.Stop(ctx); err != nil {
+		log.Printf("[%s] Orchestaration deinitalize fail : %s", logPrefix, err.Error())
+		return -1
+	}
+
+	errCode = 0
+	log.Println(logPrefix, "orchestration deinit done")
+
+	return
+}
+
 //export OrchestrationRequestService
 func OrchestrationRequestService(cAppName *C.char, serviceInfo *C.RequestServiceInfo, count C.int) C.ResponseService {
 	log.Printf("[%s] OrchestrationRequestService", logPrefix)
 
+	return requestService(orchestrationapi.ReqeustService{
+		ServiceName: C.GoString(cAppName),
+		ServiceInfo: getRequestServiceInfos(serviceInfo, count),
+	})
+}
+
+// OrchestrationRequestServiceWithOptions requests the service with the options of RequestServiceOptions,
+// GroupID of the response is set if the service runs Replicas instances.
+// RequiredLabels and PreferredLabels are "key=value" pairs separated by ",", a key without value matches any value
+//
+//export OrchestrationRequestServiceWithOptions
+func OrchestrationRequestServiceWithOptions(cAppName *C.char, serviceInfo *C.RequestServiceInfo, count C.int, options *C.RequestServiceOptions) C.ResponseService {
+	log.Printf("[%s] OrchestrationRequestServiceWithOptions", logPrefix)
+
+	request := orchestrationapi.ReqeustService{
+		ServiceName: C.GoString(cAppName),
+		ServiceInfo: getRequestServiceInfos(serviceInfo, count),
+	}
+	if options != nil {
+		request.Replicas = int(options.Replicas)
+		request.SpreadPolicy = C.GoString(options.SpreadPolicy)
+		request.RequiredLabels = getLabels(options.RequiredLabels)
+		request.PreferredLabels = getLabels(options.PreferredLabels)
+	}
+
+	return requestService(request)
+}
+
+func requestService(request orchestrationapi.ReqeustService) C.ResponseService {
+	log.Println("appName:", request.ServiceName, "infos:", request.ServiceInfo)
+	externalAPI, err := orchestrationapi.GetExternalAPI()
+	if err != nil {
+		log.Fatalf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
+	}
+
+	res := externalAPI.RequestService(request)
+	log.Println("requestService handle : ", res)
+
+	ret := C.ResponseService{}
+	ret.Message = C.CString(res.Message)
+	ret.ServiceName = C.CString(res.ServiceName)
+	ret.RemoteTargetInfo.ExecutionType = C.CString(res.RemoteTargetInfo.ExecutionType)
+	ret.RemoteTargetInfo.Target = C.CString(res.RemoteTargetInfo.Target)
+	ret.GroupID = C.CString(res.GroupID)
+
+	return ret
+}
+
+//export OrchestrationRequestServiceDryRun
+func OrchestrationRequestServiceDryRun(cAppName *C.char, serviceInfo *C.RequestServiceInfo, count C.int, cPolicy *C.char) *C.char {
+	log.Printf("[%s] OrchestrationRequestServiceDryRun", logPrefix)
+
 	appName := C.GoString(cAppName)
+	request := orchestrationapi.ReqeustService{
+		ServiceName:      appName,
+		ServiceInfo:      getRequestServiceInfos(serviceInfo, count),
+		SchedulingPolicy: C.GoString(cPolicy),
+	}
 
+	res := orchestrationapi.DryRunResponse{
+		Message:     orchestrationapi.INTERNAL_SERVER_ERROR,
+		ServiceName: appName,
+	}
+	externalAPI, err := orchestrationapi.GetExternalAPI()
+	if err != nil {
+		log.Printf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
+	} else {
+		res = externalAPI.RequestServiceDryRun(request)
+	}
+
+	resBytes, err := json.Marshal(res)
+	if err != nil {
+		log.Printf("[%s] can not marshal dry run result : %s", logPrefix, err.Error())
+		return nil
+	}
+
+	return C.CString(string(resBytes))
+}
+
+// OrchestrationGetServiceGroup returns the service group as JSON string or NULL if it is not found,
+// the caller should free the returned string
+//
+//export OrchestrationGetServiceGroup
+func OrchestrationGetServiceGroup(cGroupID *C.char) *C.char {
+	log.Printf("[%s] OrchestrationGetServiceGroup", logPrefix)
+
+	externalAPI, err := orchestrationapi.GetExternalAPI()
+	if err != nil {
+		log.Printf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
+		return nil
+	}
+
+	group, err := externalAPI.GetServiceGroup(C.GoString(cGroupID))
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		return nil
+	}
+
+	groupBytes, err := json.Marshal(group)
+	if err != nil {
+		log.Printf("[%s] can not marshal service group : %s", logPrefix, err.Error())
+		return nil
+	}
+
+	return C.CString(string(groupBytes))
+}
+
+func getRequestServiceInfos(serviceInfo *C.RequestServiceInfo, count C.int) []orchestrationapi.RequestServiceInfo {
 	requestInfos := make([]orchestrationapi.RequestServiceInfo, count)
 	CServiceInfo := (*[(math.MaxInt16 - 1) / unsafe.Sizeof(serviceInfo)]C.RequestServiceInfo)(unsafe.Pointer(serviceInfo))[:count:count]
 
@@ -180,22 +357,72 @@ func OrchestrationRequestService(cAppName *C.char, serviceInfo *C.RequestService
 		requestInfos[idx].ExeCmd = append([]string{}, args...)
 	}
 
-	log.Println("appName:", appName, "infos:", requestInfos)
+	return requestInfos
+}
+
+func getLabels(cLabels *C.char) map[string]string {
+	if cLabels == nil {
+		return nil
+	}
+
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(C.GoString(cLabels), ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if len(key) == 0 {
+			continue
+		}
+		if len(kv) == 2 {
+			labels[key] = strings.TrimSpace(kv[1])
+		} else {
+			labels[key] = ""
+		}
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+//export OrchestrationSubscribeDeviceEvent
+func OrchestrationSubscribeDeviceEvent(cb C.DeviceEventCallback) (errCode C.int) {
+	log.Printf("[%s] OrchestrationSubscribeDeviceEvent", logPrefix)
+	if cb == nil {
+		return -1
+	}
+
 	externalAPI, err := orchestrationapi.GetExternalAPI()
 	if err != nil {
-		log.Fatalf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
+		log.Printf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
+		return -1
 	}
 
-	res := externalAPI.RequestService(orchestrationapi.ReqeustService{ServiceName: appName, ServiceInfo: requestInfos})
-	log.Println("requestService handle : ", res)
+	deviceEventMtx.Lock()
+	defer deviceEventMtx.Unlock()
 
-	ret := C.ResponseService{}
-	ret.Message = C.CString(res.Message)
-	ret.ServiceName = C.CString(res.ServiceName)
-	ret.RemoteTargetInfo.ExecutionType = C.CString(res.RemoteTargetInfo.ExecutionType)
-	ret.RemoteTargetInfo.Target = C.CString(res.RemoteTargetInfo.Target)
+	if deviceEventCancel != nil {
+		deviceEventCancel()
+	}
 
-	return ret
+	events, cancel := externalAPI.SubscribeDeviceEvent()
+	deviceEventCancel = cancel
+	go deliverDeviceEvents(cb, events)
+
+	return 0
+}
+
+//export OrchestrationUnsubscribeDeviceEvent
+func OrchestrationUnsubscribeDeviceEvent() {
+	log.Printf("[%s] OrchestrationUnsubscribeDeviceEvent", logPrefix)
+
+	deviceEventMtx.Lock()
+	defer deviceEventMtx.Unlock()
+
+	if deviceEventCancel != nil {
+		deviceEventCancel()
+		deviceEventCancel = nil
+	}
 }
 
 var count int
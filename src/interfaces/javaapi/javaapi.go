@@ -19,15 +19,20 @@
 package javaapi
 
 import (
+	"context"
 	"db/bolt/wrapper"
+	"encoding/json"
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"common/logmgr"
+	"common/resourceutil"
 
 	configuremgr "controller/configuremgr/native"
 	"controller/discoverymgr"
+	"controller/schedulermgr"
 	scoringmgr "controller/scoringmgr"
 	"controller/servicemgr"
 	"controller/servicemgr/executor/androidexecutor"
@@ -46,8 +51,29 @@ type RequestServiceInfo struct {
 }
 
 type ReqeustService struct {
-	ServiceName string
-	ServiceInfo []RequestServiceInfo
+	ServiceName      string
+	ServiceInfo      []RequestServiceInfo
+	RequiredLabels   map[string]string
+	PreferredLabels  map[string]string
+	SchedulingPolicy string
+	Replicas         int
+	SpreadPolicy     string
+}
+
+// SetRequiredLabel adds a device label which target device should have
+func (r *ReqeustService) SetRequiredLabel(key string, value string) {
+	if r.RequiredLabels == nil {
+		r.RequiredLabels = make(map[string]string)
+	}
+	r.RequiredLabels[key] = value
+}
+
+// SetPreferredLabel adds a device label which target device is preferred to have
+func (r *ReqeustService) SetPreferredLabel(key string, value string) {
+	if r.PreferredLabels == nil {
+		r.PreferredLabels = make(map[string]string)
+	}
+	r.PreferredLabels[key] = value
 }
 
 func (r *ReqeustService) SetExecutionCommand(execType string, command string) {
@@ -95,6 +121,8 @@ type ResponseService struct {
 	Message          string
 	ServiceName      string
 	RemoteTargetInfo *TargetInfo
+	// GroupID is set if the request has replicas, see OrchestrationGetServiceGroup
+	GroupID string
 }
 
 func (r ResponseService) GetExecutedType() string {
@@ -118,11 +146,19 @@ const (
 	configPath = edgeDir + "apps"
 	dbPath     = edgeDir + "db"
 
-	cipherKeyFilePath = edgeDir + "orchestration_userID.txt"
-	deviceIDFilePath  = edgeDir + "orchestration_deviceID.txt"
+	cipherKeyFilePath        = edgeDir + "orchestration_userID.txt"
+	deviceIDFilePath         = edgeDir + "orchestration_deviceID.txt"
+	deviceLabelFilePath      = edgeDir + "orchestration_labels.txt"
+	deviceLimitFilePath      = edgeDir + "orchestration_limits.txt"
+	monitoringConfigFilePath = edgeDir + "orchestration_monitoring.txt"
+
+	shutdownTimeout = 10 * time.Second
 )
 
-var orcheEngine orchestrationapi.Orche
+var (
+	orcheEngine    orchestrationapi.Orche
+	restEdgeRouter *route.RestRouter
+)
 
 // OrchestrationInit runs orchestration service and discovers remote orchestration services
 func OrchestrationInit() (errCode int) {
@@ -136,6 +172,17 @@ func OrchestrationInit() (errCode int) {
 	restIns.SetCipher(sha256.GetCipher(cipherKeyFilePath))
 
 	servicemgr.GetInstance().SetClient(restIns)
+	if limits, err := servicemgr.ReadLimits(deviceLimitFilePath); err == nil {
+		servicemgr.GetInstance().SetLimits(limits)
+	}
+
+	if intervals, err := resourceutil.ReadCollectorIntervals(monitoringConfigFilePath); err == nil {
+		for name, interval := range intervals {
+			if err := resourceutil.SetCollectorInterval(name, interval); err != nil {
+				log.Printf("[%s] %s", logPrefix, err.Error())
+			}
+		}
+	}
 
 	builder := orchestrationapi.OrchestrationBuilder{}
 	builder.SetWatcher(configuremgr.GetInstance(configPath))
@@ -143,6 +190,7 @@ func OrchestrationInit() (errCode int) {
 	builder.SetScoring(scoringmgr.GetInstance())
 	builder.SetService(servicemgr.GetInstance())
 	builder.SetExecutor(androidexecutor.GetInstance())
+	builder.AddExecutor(executionType, androidexecutor.GetInstance())
 	builder.SetClient(restIns)
 
 	orcheEngine = builder.Build()
@@ -151,9 +199,9 @@ func OrchestrationInit() (errCode int) {
 		return
 	}
 
-	orcheEngine.Start(deviceIDFilePath, platform, executionType)
+	orcheEngine.Start(deviceIDFilePath, platform, executionType, deviceLabelFilePath)
 
-	restEdgeRouter := route.NewRestRouter()
+	restEdgeRouter = route.NewRestRouter()
 
 	internalapi, err := orchestrationapi.GetInternalAPI()
 	if err != nil {
@@ -173,6 +221,47 @@ func OrchestrationInit() (errCode int) {
 	return
 }
 
+// OrchestrationDeinit stops orchestration service gracefully
+func OrchestrationDeinit() (errCode int) {
+	log.Printf("[%s] OrchestrationDeinit", logPrefix)
+	if orcheEngine == nil {
+		return -1
+	}
+
+	// every stage has its own deadline, an open stream should not expire the next stage
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	err := restEdgeRouter.Stop(ctx)
+	cancel()
+	if err != nil {
+		log.Printf("[%s] REST server shutdown : %s", logPrefix, err.Error())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := orcheEngine.Stop(ctx); err != nil {
+		log.Printf("[%s] Orchestration deinitialize fail : %s", logPrefix, err.Error())
+		return -1
+	}
+
+	log.Println(logPrefix, "Orchestration deinit done")
+
+	errCode = 0
+
+	return
+}
+
+// OrchestrationSetScheduler sets the default scheduling policy of service requests
+func OrchestrationSetScheduler(policy string) (errCode int) {
+	log.Printf("[%s] OrchestrationSetScheduler", logPrefix)
+	if err := schedulermgr.GetInstance().SetDefault(policy); err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		return -1
+	}
+
+	return 0
+}
+
 // OrchestrationRequestService performs request from service applications which uses orchestration service
 func OrchestrationRequestService(request *ReqeustService) *ResponseService {
 	log.Printf("[%s] OrchestrationRequestService", logPrefix)
@@ -183,7 +272,14 @@ func OrchestrationRequestService(request *ReqeustService) *ResponseService {
 		log.Fatalf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
 	}
 
-	changed := orchestrationapi.ReqeustService{ServiceName: request.ServiceName}
+	changed := orchestrationapi.ReqeustService{
+		ServiceName:      request.ServiceName,
+		RequiredLabels:   request.RequiredLabels,
+		PreferredLabels:  request.PreferredLabels,
+		SchedulingPolicy: request.SchedulingPolicy,
+		Replicas:         request.Replicas,
+		SpreadPolicy:     request.SpreadPolicy,
+	}
 
 	changed.ServiceInfo = make([]orchestrationapi.RequestServiceInfo, len(request.ServiceInfo))
 	for idx, info := range request.ServiceInfo {
@@ -201,10 +297,37 @@ func OrchestrationRequestService(request *ReqeustService) *ResponseService {
 			ExecutionType: response.RemoteTargetInfo.ExecutionType,
 			Target:        response.RemoteTargetInfo.Target,
 		},
+		GroupID: response.GroupID,
 	}
 	return ret
 }
 
+// OrchestrationGetServiceGroup returns the status of every replica of the service group as JSON string,
+// it is empty if the group is not found
+func OrchestrationGetServiceGroup(groupID string) string {
+	log.Printf("[%s] OrchestrationGetServiceGroup", logPrefix)
+
+	externalAPI, err := orchestrationapi.GetExternalAPI()
+	if err != nil {
+		log.Printf("[%s] Orchestaration external api : %s", logPrefix, err.Error())
+		return ""
+	}
+
+	group, err := externalAPI.GetServiceGroup(groupID)
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		return ""
+	}
+
+	groupBytes, err := json.Marshal(group)
+	if err != nil {
+		log.Printf("[%s] can not marshal service group : %s", logPrefix, err.Error())
+		return ""
+	}
+
+	return string(groupBytes)
+}
+
 var count int
 var mtx sync.Mutex
 
@@ -5,9 +5,16 @@
 package mocks
 
 import (
+	context "context"
+	discoverymgr "controller/discoverymgr"
+	servicemgr "controller/servicemgr"
+	notification "controller/servicemgr/notification"
+	servicelog "controller/servicemgr/servicelog"
+	resource "db/bolt/resource"
 	gomock "github.com/golang/mock/gomock"
 	orchestrationapi "orchestrationapi"
 	reflect "reflect"
+	time "time"
 )
 
 // MockOrche is a mock of Orche interface
@@ -34,15 +41,29 @@ func (m *MockOrche) EXPECT() *MockOrcheMockRecorder {
 }
 
 // Start mocks base method
-func (m *MockOrche) Start(deviceIDPath, platform, executionType string) {
+func (m *MockOrche) Start(deviceIDPath, platform, executionType, labelPath string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Start", deviceIDPath, platform, executionType)
+	m.ctrl.Call(m, "Start", deviceIDPath, platform, executionType, labelPath)
 }
 
 // Start indicates an expected call of Start
-func (mr *MockOrcheMockRecorder) Start(deviceIDPath, platform, executionType interface{}) *gomock.Call {
+func (mr *MockOrcheMockRecorder) Start(deviceIDPath, platform, executionType, labelPath interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockOrche)(nil).Start), deviceIDPath, platform, executionType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockOrche)(nil).Start), deviceIDPath, platform, executionType, labelPath)
+}
+
+// Stop mocks base method
+func (m *MockOrche) Stop(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockOrcheMockRecorder) Stop(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockOrche)(nil).Stop), ctx)
 }
 
 // MockOrcheExternalAPI is a mock of OrcheExternalAPI interface
@@ -82,6 +103,153 @@ func (mr *MockOrcheExternalAPIMockRecorder) RequestService(serviceInfo interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestService", reflect.TypeOf((*MockOrcheExternalAPI)(nil).RequestService), serviceInfo)
 }
 
+// RequestServiceDryRun mocks base method
+func (m *MockOrcheExternalAPI) RequestServiceDryRun(serviceInfo orchestrationapi.ReqeustService) orchestrationapi.DryRunResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestServiceDryRun", serviceInfo)
+	ret0, _ := ret[0].(orchestrationapi.DryRunResponse)
+	return ret0
+}
+
+// RequestServiceDryRun indicates an expected call of RequestServiceDryRun
+func (mr *MockOrcheExternalAPIMockRecorder) RequestServiceDryRun(serviceInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestServiceDryRun", reflect.TypeOf((*MockOrcheExternalAPI)(nil).RequestServiceDryRun), serviceInfo)
+}
+
+// RequestWorkflow mocks base method
+func (m *MockOrcheExternalAPI) RequestWorkflow(request orchestrationapi.WorkflowRequest) orchestrationapi.WorkflowStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestWorkflow", request)
+	ret0, _ := ret[0].(orchestrationapi.WorkflowStatus)
+	return ret0
+}
+
+// RequestWorkflow indicates an expected call of RequestWorkflow
+func (mr *MockOrcheExternalAPIMockRecorder) RequestWorkflow(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestWorkflow", reflect.TypeOf((*MockOrcheExternalAPI)(nil).RequestWorkflow), request)
+}
+
+// GetWorkflowStatus mocks base method
+func (m *MockOrcheExternalAPI) GetWorkflowStatus(workflowID string) (orchestrationapi.WorkflowStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflowStatus", workflowID)
+	ret0, _ := ret[0].(orchestrationapi.WorkflowStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkflowStatus indicates an expected call of GetWorkflowStatus
+func (mr *MockOrcheExternalAPIMockRecorder) GetWorkflowStatus(workflowID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowStatus", reflect.TypeOf((*MockOrcheExternalAPI)(nil).GetWorkflowStatus), workflowID)
+}
+
+// GetPendingRequests mocks base method
+func (m *MockOrcheExternalAPI) GetPendingRequests() []orchestrationapi.PendingRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingRequests")
+	ret0, _ := ret[0].([]orchestrationapi.PendingRequest)
+	return ret0
+}
+
+// GetPendingRequests indicates an expected call of GetPendingRequests
+func (mr *MockOrcheExternalAPIMockRecorder) GetPendingRequests() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingRequests", reflect.TypeOf((*MockOrcheExternalAPI)(nil).GetPendingRequests))
+}
+
+// GetPendingRequest mocks base method
+func (m *MockOrcheExternalAPI) GetPendingRequest(requestID string) (orchestrationapi.PendingRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingRequest", requestID)
+	ret0, _ := ret[0].(orchestrationapi.PendingRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingRequest indicates an expected call of GetPendingRequest
+func (mr *MockOrcheExternalAPIMockRecorder) GetPendingRequest(requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingRequest", reflect.TypeOf((*MockOrcheExternalAPI)(nil).GetPendingRequest), requestID)
+}
+
+// GetServiceGroup mocks base method
+func (m *MockOrcheExternalAPI) GetServiceGroup(groupID string) (orchestrationapi.ServiceGroupEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceGroup", groupID)
+	ret0, _ := ret[0].(orchestrationapi.ServiceGroupEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceGroup indicates an expected call of GetServiceGroup
+func (mr *MockOrcheExternalAPIMockRecorder) GetServiceGroup(groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceGroup", reflect.TypeOf((*MockOrcheExternalAPI)(nil).GetServiceGroup), groupID)
+}
+
+// GetResourceHistory mocks base method
+func (m *MockOrcheExternalAPI) GetResourceHistory(name string, window time.Duration) (resource.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceHistory", name, window)
+	ret0, _ := ret[0].(resource.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceHistory indicates an expected call of GetResourceHistory
+func (mr *MockOrcheExternalAPIMockRecorder) GetResourceHistory(name, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceHistory", reflect.TypeOf((*MockOrcheExternalAPI)(nil).GetResourceHistory), name, window)
+}
+
+// GetServiceLog mocks base method
+func (m *MockOrcheExternalAPI) GetServiceLog(serviceID, since uint64, wait time.Duration) (servicelog.Chunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceLog", serviceID, since, wait)
+	ret0, _ := ret[0].(servicelog.Chunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceLog indicates an expected call of GetServiceLog
+func (mr *MockOrcheExternalAPIMockRecorder) GetServiceLog(serviceID, since, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceLog", reflect.TypeOf((*MockOrcheExternalAPI)(nil).GetServiceLog), serviceID, since, wait)
+}
+
+// SubscribeDeviceEvent mocks base method
+func (m *MockOrcheExternalAPI) SubscribeDeviceEvent() (<-chan discoverymgr.DeviceEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeDeviceEvent")
+	ret0, _ := ret[0].(<-chan discoverymgr.DeviceEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// SubscribeDeviceEvent indicates an expected call of SubscribeDeviceEvent
+func (mr *MockOrcheExternalAPIMockRecorder) SubscribeDeviceEvent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeDeviceEvent", reflect.TypeOf((*MockOrcheExternalAPI)(nil).SubscribeDeviceEvent))
+}
+
+// SubscribeServiceGroup mocks base method
+func (m *MockOrcheExternalAPI) SubscribeServiceGroup() (<-chan orchestrationapi.ServiceGroupEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeServiceGroup")
+	ret0, _ := ret[0].(<-chan orchestrationapi.ServiceGroupEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// SubscribeServiceGroup indicates an expected call of SubscribeServiceGroup
+func (mr *MockOrcheExternalAPIMockRecorder) SubscribeServiceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeServiceGroup", reflect.TypeOf((*MockOrcheExternalAPI)(nil).SubscribeServiceGroup))
+}
+
 // MockOrcheInternalAPI is a mock of OrcheInternalAPI interface
 type MockOrcheInternalAPI struct {
 	ctrl     *gomock.Controller
@@ -118,9 +286,11 @@ func (mr *MockOrcheInternalAPIMockRecorder) Notify(serviceName interface{}) *gom
 }
 
 // ExecuteAppOnLocal mocks base method
-func (m *MockOrcheInternalAPI) ExecuteAppOnLocal(appInfo map[string]interface{}) {
+func (m *MockOrcheInternalAPI) ExecuteAppOnLocal(appInfo map[string]interface{}) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "ExecuteAppOnLocal", appInfo)
+	ret := m.ctrl.Call(m, "ExecuteAppOnLocal", appInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // ExecuteAppOnLocal indicates an expected call of ExecuteAppOnLocal
@@ -129,18 +299,32 @@ func (mr *MockOrcheInternalAPIMockRecorder) ExecuteAppOnLocal(appInfo interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAppOnLocal", reflect.TypeOf((*MockOrcheInternalAPI)(nil).ExecuteAppOnLocal), appInfo)
 }
 
-// HandleNotificationOnLocal mocks base method
-func (m *MockOrcheInternalAPI) HandleNotificationOnLocal(serviceID float64, status string) error {
+// GetCapacity mocks base method
+func (m *MockOrcheInternalAPI) GetCapacity() servicemgr.Capacity {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HandleNotificationOnLocal", serviceID, status)
+	ret := m.ctrl.Call(m, "GetCapacity")
+	ret0, _ := ret[0].(servicemgr.Capacity)
+	return ret0
+}
+
+// GetCapacity indicates an expected call of GetCapacity
+func (mr *MockOrcheInternalAPIMockRecorder) GetCapacity() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapacity", reflect.TypeOf((*MockOrcheInternalAPI)(nil).GetCapacity))
+}
+
+// HandleExecutionResultOnLocal mocks base method
+func (m *MockOrcheInternalAPI) HandleExecutionResultOnLocal(result notification.ExecutionResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleExecutionResultOnLocal", result)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// HandleNotificationOnLocal indicates an expected call of HandleNotificationOnLocal
-func (mr *MockOrcheInternalAPIMockRecorder) HandleNotificationOnLocal(serviceID, status interface{}) *gomock.Call {
+// HandleExecutionResultOnLocal indicates an expected call of HandleExecutionResultOnLocal
+func (mr *MockOrcheInternalAPIMockRecorder) HandleExecutionResultOnLocal(result interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNotificationOnLocal", reflect.TypeOf((*MockOrcheInternalAPI)(nil).HandleNotificationOnLocal), serviceID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleExecutionResultOnLocal", reflect.TypeOf((*MockOrcheInternalAPI)(nil).HandleExecutionResultOnLocal), result)
 }
 
 // GetScore mocks base method
@@ -157,3 +341,34 @@ func (mr *MockOrcheInternalAPIMockRecorder) GetScore(target interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScore", reflect.TypeOf((*MockOrcheInternalAPI)(nil).GetScore), target)
 }
+
+// GetScoreWithComponents mocks base method
+func (m *MockOrcheInternalAPI) GetScoreWithComponents(target string) (float64, map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreWithComponents", target)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(map[string]float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetScoreWithComponents indicates an expected call of GetScoreWithComponents
+func (mr *MockOrcheInternalAPIMockRecorder) GetScoreWithComponents(target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreWithComponents", reflect.TypeOf((*MockOrcheInternalAPI)(nil).GetScoreWithComponents), target)
+}
+
+// ReadServiceLogOnLocal mocks base method
+func (m *MockOrcheInternalAPI) ReadServiceLogOnLocal(requester string, serviceID, since uint64, wait time.Duration) (servicelog.Chunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadServiceLogOnLocal", requester, serviceID, since, wait)
+	ret0, _ := ret[0].(servicelog.Chunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadServiceLogOnLocal indicates an expected call of ReadServiceLogOnLocal
+func (mr *MockOrcheInternalAPIMockRecorder) ReadServiceLogOnLocal(requester, serviceID, since, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadServiceLogOnLocal", reflect.TypeOf((*MockOrcheInternalAPI)(nil).ReadServiceLogOnLocal), requester, serviceID, since, wait)
+}
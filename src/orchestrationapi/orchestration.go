@@ -19,8 +19,10 @@
 package orchestrationapi
 
 import (
+	"context"
 	"errors"
 	"log"
+	"sort"
 	"time"
 
 	"common/networkhelper"
@@ -31,6 +33,8 @@ import (
 	"controller/servicemgr"
 	"controller/servicemgr/executor"
 	"controller/servicemgr/notification"
+	"controller/servicemgr/servicelog"
+	resourceDB "db/bolt/resource"
 	"restinterface/client"
 )
 
@@ -38,30 +42,46 @@ const logtag = "Orchestration"
 
 // Orche is the interface implemented by orchestration start funciton
 type Orche interface {
-	Start(deviceIDPath string, platform string, executionType string)
+	Start(deviceIDPath string, platform string, executionType string, labelPath string)
+	Stop(ctx context.Context) error
 }
 
 // OrcheExternalAPI is the interface implemented by external REST API
 type OrcheExternalAPI interface {
 	RequestService(serviceInfo ReqeustService) ResponseService
+	RequestServiceDryRun(serviceInfo ReqeustService) DryRunResponse
+	RequestWorkflow(request WorkflowRequest) WorkflowStatus
+	GetWorkflowStatus(workflowID string) (WorkflowStatus, error)
+	GetPendingRequests() []PendingRequest
+	GetPendingRequest(requestID string) (PendingRequest, error)
+	GetServiceGroup(groupID string) (ServiceGroupEvent, error)
+	SubscribeDeviceEvent() (events <-chan discoverymgr.DeviceEvent, cancel func())
+	SubscribeServiceGroup() (events <-chan ServiceGroupEvent, cancel func())
+	GetResourceHistory(name string, window time.Duration) (resourceDB.History, error)
+	GetServiceLog(serviceID uint64, since uint64, wait time.Duration) (servicelog.Chunk, error)
 }
 
 // OrcheInternalAPI is the interface implemented by internal REST API
 type OrcheInternalAPI interface {
 	configuremgr.Notifier
-	ExecuteAppOnLocal(appInfo map[string]interface{})
-	HandleNotificationOnLocal(serviceID float64, status string) error
+	ExecuteAppOnLocal(appInfo map[string]interface{}) error
+	GetCapacity() servicemgr.Capacity
+	HandleExecutionResultOnLocal(result notification.ExecutionResult) error
 	GetScore(target string) (scoreValue float64, err error)
+	GetScoreWithComponents(target string) (scoreValue float64, components map[string]float64, err error)
+	ReadServiceLogOnLocal(requester string, serviceID uint64, since uint64, wait time.Duration) (servicelog.Chunk, error)
 }
 
 var (
 	orcheIns            *orcheImpl
 	resourceMonitorImpl resourceutil.Monitor
+	resourceIns         resourceutil.GetResource
 )
 
 func init() {
 	orcheIns = new(orcheImpl)
 	orcheIns.networkhelper = networkhelper.GetInstance()
+	resourceIns = &resourceutil.ResourceImpl{}
 }
 
 // GetExternalAPI registers the orchestration external API
@@ -100,6 +120,7 @@ type OrchestrationBuilder struct {
 
 	isSetExecutor bool
 	executorIns   executor.ServiceExecutor
+	executors     map[string]executor.ServiceExecutor
 
 	isSetClient bool
 	clientAPI   client.Clienter
@@ -135,6 +156,15 @@ func (o *OrchestrationBuilder) SetExecutor(e executor.ServiceExecutor) {
 	o.executorIns = e
 }
 
+// AddExecutor registers the interface to execute service application of execType,
+// the device advertises execType in addition to the execution type given to Start
+func (o *OrchestrationBuilder) AddExecutor(execType string, e executor.ServiceExecutor) {
+	if o.executors == nil {
+		o.executors = make(map[string]executor.ServiceExecutor)
+	}
+	o.executors[execType] = e
+}
+
 // SetClient registers the interface to send request to remote device
 func (o *OrchestrationBuilder) SetClient(c client.Clienter) {
 	o.isSetClient = true
@@ -144,7 +174,7 @@ func (o *OrchestrationBuilder) SetClient(c client.Clienter) {
 // Build registrers every interface to run orchestration
 func (o OrchestrationBuilder) Build() Orche {
 	if !o.isSetWatcher || !o.isSetDiscovery || !o.isSetScoring ||
-		!o.isSetService || !o.isSetExecutor || !o.isSetClient {
+		!o.isSetService || (!o.isSetExecutor && len(o.executors) == 0) || !o.isSetClient {
 		return nil
 	}
 
@@ -157,20 +187,73 @@ func (o OrchestrationBuilder) Build() Orche {
 	resourceMonitorImpl = resourceutil.GetMonitoringInstance()
 
 	orcheIns.notificationIns = notification.GetInstance()
-	orcheIns.serviceIns.SetLocalServiceExecutor(o.executorIns)
+	if o.isSetExecutor {
+		orcheIns.serviceIns.SetLocalServiceExecutor(o.executorIns)
+	}
+
+	orcheIns.execTypes = make([]string, 0, len(o.executors))
+	for execType, e := range o.executors {
+		orcheIns.serviceIns.AddLocalServiceExecutor(execType, e)
+		orcheIns.execTypes = append(orcheIns.execTypes, execType)
+	}
+	sort.Strings(orcheIns.execTypes)
 
 	return orcheIns
 }
 
-// Start runs the orchestration service itself
-func (o *orcheImpl) Start(deviceIDPath string, platform string, executionType string) {
+// Start runs the orchestration service itself, executionType is the primary execution type of the device
+func (o *orcheImpl) Start(deviceIDPath string, platform string, executionType string, labelPath string) {
 	resourceMonitorImpl.StartMonitoringResource()
-	o.discoverIns.StartDiscovery(deviceIDPath, platform, executionType)
+	o.discoverIns.StartDiscovery(deviceIDPath, platform, o.supportedExecTypes(executionType), labelPath)
 	o.watcher.Watch(o)
 	o.Ready = true
 	time.Sleep(1000)
 }
 
+// supportedExecTypes returns every execution type of the device with the primary one first
+func (o *orcheImpl) supportedExecTypes(executionType string) []string {
+	execTypes := []string{executionType}
+	for _, execType := range o.execTypes {
+		if execType != executionType {
+			execTypes = append(execTypes, execType)
+		}
+	}
+	return execTypes
+}
+
+// Stop terminates the orchestration service after in-flight requests are done or ctx is expired,
+// the pending queue worker and the services running on local device are stopped
+func (o *orcheImpl) Stop(ctx context.Context) (err error) {
+	requestMtx.Lock()
+	if o.Ready == false {
+		requestMtx.Unlock()
+		return errors.New("orchestration engine does not ready")
+	}
+	o.Ready = false
+
+	drained := make(chan struct{})
+	if requestCount == 0 {
+		close(drained)
+	} else {
+		requestDrained = drained
+	}
+	requestMtx.Unlock()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		log.Println(logtag, "[Stop]", "in-flight requests are not drained :", err.Error())
+	}
+
+	stopPendingQueue()
+	o.serviceIns.NotifyTermination()
+	resourceMonitorImpl.StopMonitoringResource()
+	o.discoverIns.StopDiscovery()
+
+	return
+}
+
 // Notify gives the notifications to scoringmgr and discoverymgr package after checking installed service applications
 func (o orcheImpl) Notify(service string) {
 	if err := o.discoverIns.AddNewServiceName(service); err != nil {
@@ -180,16 +263,41 @@ func (o orcheImpl) Notify(service string) {
 }
 
 // ExecuteAppOnLocal executes a service application on local device
-func (o orcheImpl) ExecuteAppOnLocal(appInfo map[string]interface{}) {
-	o.serviceIns.ExecuteAppOnLocal(appInfo)
+func (o orcheImpl) ExecuteAppOnLocal(appInfo map[string]interface{}) error {
+	return o.serviceIns.ExecuteAppOnLocal(appInfo)
 }
 
-// HandleNotificationOnLocal handles notifications from local device after executing service application
-func (o orcheImpl) HandleNotificationOnLocal(serviceID float64, status string) error {
-	return o.notificationIns.HandleNotificationOnLocal(serviceID, status)
+// GetCapacity gets the limits and the current load of local device
+func (o orcheImpl) GetCapacity() servicemgr.Capacity {
+	return o.serviceIns.GetCapacity()
+}
+
+// HandleExecutionResultOnLocal handles results from remote device after executing service application
+func (o orcheImpl) HandleExecutionResultOnLocal(result notification.ExecutionResult) error {
+	return o.notificationIns.HandleResultOnLocal(result)
 }
 
 // GetScore gets a resource score of local device for specific app
 func (o orcheImpl) GetScore(devID string) (scoreValue float64, err error) {
 	return o.scoringIns.GetScore(devID)
 }
+
+// GetScoreWithComponents gets a resource score of local device with its sub-scores
+func (o orcheImpl) GetScoreWithComponents(devID string) (scoreValue float64, components map[string]float64, err error) {
+	return o.scoringIns.GetScoreWithComponents(devID)
+}
+
+// GetResourceHistory gets the recent samples of a resource of local device with their statistics
+func (o orcheImpl) GetResourceHistory(name string, window time.Duration) (resourceDB.History, error) {
+	return resourceIns.GetResourceHistory(name, window)
+}
+
+// GetServiceLog gets output of service requested by local device
+func (o orcheImpl) GetServiceLog(serviceID uint64, since uint64, wait time.Duration) (servicelog.Chunk, error) {
+	return o.serviceIns.GetServiceLog(serviceID, since, wait)
+}
+
+// ReadServiceLogOnLocal reads output of service executed on local device for the requester
+func (o orcheImpl) ReadServiceLogOnLocal(requester string, serviceID uint64, since uint64, wait time.Duration) (servicelog.Chunk, error) {
+	return servicelog.GetInstance().Read(requester, serviceID, since, wait)
+}
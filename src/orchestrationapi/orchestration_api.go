@@ -20,15 +20,18 @@ package orchestrationapi
 import (
 	"errors"
 	"log"
-	"sort"
 	"sync"
-	"sync/atomic"
+	"time"
 
+	"common/eventbus"
+	"common/metrics"
 	"common/networkhelper"
 	"controller/configuremgr"
 	"controller/discoverymgr"
+	"controller/schedulermgr"
 	"controller/scoringmgr"
 	"controller/servicemgr"
+	"controller/servicemgr/executor/containerspec"
 	"controller/servicemgr/notification"
 	dbcommon "db/bolt/common"
 	"restinterface/client"
@@ -49,6 +52,9 @@ type orcheImpl struct {
 	networkhelper networkhelper.Network
 
 	clientAPI client.Clienter
+
+	// execTypes has the execution types of the executors added by the builder
+	execTypes []string
 }
 
 type deviceScore struct {
@@ -56,10 +62,23 @@ type deviceScore struct {
 	endpoint string
 	score    float64
 	execType string
+	labels   map[string]string
+	isLocal  bool
+
+	components map[string]float64
+	err        error
 }
 
+// orcheClient is the state of a request, it is owned by the request until its service is done
+// and it is kept while a supervised service is restarted or relocated
 type orcheClient struct {
-	appName   string
+	appName string
+	// deviceID is guarded by mtx, it is changed by the relocator while the notification is listened
+	mtx       sync.Mutex
+	deviceID  string
+	group     *serviceGroup
+	replica   int
+	done      func(status string)
 	args      []string
 	notiChan  chan string
 	endSignal chan bool
@@ -68,23 +87,48 @@ type orcheClient struct {
 type RequestServiceInfo struct {
 	ExecutionType string
 	ExeCmd        []string
+	// ContainerSpec is used instead of ExeCmd of docker run arguments for container execution type
+	ContainerSpec *containerspec.Spec
 }
 
 type ReqeustService struct {
-	ServiceName string
-	ServiceInfo []RequestServiceInfo
+	ServiceName     string
+	ServiceInfo     []RequestServiceInfo
+	RequiredLabels  map[string]string
+	PreferredLabels map[string]string
+	// SchedulingPolicy selects the scheduler of this request, default scheduler is used if it is empty
+	SchedulingPolicy string
+	// Replicas is the number of instances to run, one instance is run if it is less than 2
+	Replicas int
+	// SpreadPolicy places the replicas on devices, SpreadDistinct is used if it is empty
+	SpreadPolicy string
+	// PendingTimeout keeps the request in the pending queue until a device can run it, it is not queued if it is 0
+	PendingTimeout time.Duration
+	// Stdin is the payload given to the standard input of the service
+	Stdin string
+	// ExecutionTimeout is the deadline of the execution, the service is terminated with TimedOut status after it
+	ExecutionTimeout time.Duration
+	// RestartPolicy executes the service again after it is ended, it is relocated if its device leaves
+	RestartPolicy servicemgr.RestartPolicy
 	// TODO add status callback
 }
 
 type TargetInfo struct {
 	ExecutionType string
 	Target        string
+	// ServiceID is given to get output of the executed service
+	ServiceID uint64
 }
 
 type ResponseService struct {
 	Message          string
 	ServiceName      string
 	RemoteTargetInfo TargetInfo
+	// GroupID and ReplicaTargetInfo are set if the request has replicas
+	GroupID           string
+	ReplicaTargetInfo []TargetInfo
+	// PendingRequestID is set if the request is queued with PENDING message
+	PendingRequestID string
 }
 
 const (
@@ -92,48 +136,121 @@ const (
 	INVALID_PARAMETER     = "INVALID_PARAMETER"
 	SERVICE_NOT_FOUND     = "SERVICE_NOT_FOUND"
 	INTERNAL_SERVER_ERROR = "INTERNAL_SERVER_ERROR"
+	PENDING               = "PENDING"
 )
 
 var (
-	orchClientID int32 = -1
-	orcheClients       = [1024]orcheClient{}
-
 	sysDBExecutor sysDB.DBInterface
 
 	helper dbhelper.MultipleBucketQuery
+
+	schedulerIns schedulermgr.SchedulerMgr
+
+	// requestMtx, requestCount and requestDrained keep track of in-flight requests to drain them on Stop
+	requestMtx     sync.Mutex
+	requestCount   int
+	requestDrained chan struct{}
+
+	// scoringDuration and scoringErrors measure getting scores of candidates by device ID
+	scoringDuration = metrics.NewHistogram(
+		"edge_orchestration_scoring_duration_seconds",
+		"Time taken to get the score of a candidate device by peer",
+		nil, "peer")
+	scoringErrors = metrics.NewCounter(
+		"edge_orchestration_scoring_errors_total",
+		"Number of failures to get the score of a candidate device by peer",
+		"peer")
 )
 
 func init() {
 	sysDBExecutor = sysDB.Query{}
 
 	helper = dbhelper.GetInstance()
+	schedulerIns = schedulermgr.GetInstance()
 }
 
 // RequestService handles service reqeust (ex. offloading) from service application
 func (orcheEngine *orcheImpl) RequestService(serviceInfo ReqeustService) ResponseService {
+	return orcheEngine.requestService(serviceInfo, nil)
+}
+
+// requestService executes the service and calls done with the final status of the service (or its group) if it is set
+func (orcheEngine *orcheImpl) requestService(serviceInfo ReqeustService, done func(status string)) ResponseService {
+	return orcheEngine.placeService(serviceInfo, done, false)
+}
+
+// retryPendingService executes the request of the pending queue, it is not queued again
+// but devices which can not run it now are still refused
+func (orcheEngine *orcheImpl) retryPendingService(serviceInfo ReqeustService, done func(status string)) ResponseService {
+	return orcheEngine.placeService(serviceInfo, done, true)
+}
+
+// placeService schedules and executes the service, the request is queued if no device can run it now
+// unless it comes from the pending queue
+func (orcheEngine *orcheImpl) placeService(serviceInfo ReqeustService, done func(status string), fromPendingQueue bool) ResponseService {
 	log.Printf("[RequestService] %v: %v\n", serviceInfo.ServiceName, serviceInfo.ServiceInfo)
-	if orcheEngine.Ready == false {
+	if orcheEngine.beginRequest() == false {
 		return ResponseService{
 			Message:          INTERNAL_SERVER_ERROR,
 			ServiceName:      serviceInfo.ServiceName,
 			RemoteTargetInfo: TargetInfo{},
 		}
 	}
+	defer orcheEngine.endRequest()
+
+	serviceClient := newServiceClient(serviceInfo.ServiceName)
+
+	scheduler, err := schedulerIns.GetScheduler(serviceInfo.SchedulingPolicy)
+	if err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
+		return ResponseService{
+			Message:          INVALID_PARAMETER,
+			ServiceName:      serviceInfo.ServiceName,
+			RemoteTargetInfo: TargetInfo{},
+		}
+	}
 
-	atomic.AddInt32(&orchClientID, 1)
+	if isValidSpreadPolicy(serviceInfo.SpreadPolicy) == false {
+		log.Println("[orchestrationapi] ", "unknown spread policy", serviceInfo.SpreadPolicy)
+		return ResponseService{
+			Message:          INVALID_PARAMETER,
+			ServiceName:      serviceInfo.ServiceName,
+			RemoteTargetInfo: TargetInfo{},
+		}
+	}
 
-	handle := int(orchClientID)
+	if err := validateServiceInfos(serviceInfo.ServiceInfo); err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
+		return ResponseService{
+			Message:          INVALID_PARAMETER,
+			ServiceName:      serviceInfo.ServiceName,
+			RemoteTargetInfo: TargetInfo{},
+		}
+	}
 
-	serviceClient := addServiceClient(handle, serviceInfo.ServiceName)
-	go serviceClient.listenNotify()
+	if err := serviceInfo.RestartPolicy.Validate(); err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
+		return ResponseService{
+			Message:          INVALID_PARAMETER,
+			ServiceName:      serviceInfo.ServiceName,
+			RemoteTargetInfo: TargetInfo{},
+		}
+	}
 
 	executionTypes := make([]string, 0)
 	for _, info := range serviceInfo.ServiceInfo {
 		executionTypes = append(executionTypes, info.ExecutionType)
 	}
 
-	candidates, err := orcheEngine.getCandidate(serviceInfo.ServiceName, executionTypes)
-	if err != nil {
+	selector := dbhelper.LabelSelector{
+		Required:  serviceInfo.RequiredLabels,
+		Preferred: serviceInfo.PreferredLabels,
+	}
+
+	candidates, err := orcheEngine.getCandidate(serviceInfo.ServiceName, executionTypes, selector)
+	if err != nil && serviceInfo.PendingTimeout > 0 && !fromPendingQueue {
+		return enqueuePendingRequest(orcheEngine.retryPendingService, serviceInfo, done, err.Error())
+	} else if err != nil {
 		return ResponseService{
 			Message:          err.Error(),
 			ServiceName:      serviceInfo.ServiceName,
@@ -141,7 +258,7 @@ func (orcheEngine *orcheImpl) RequestService(serviceInfo ReqeustService) Respons
 		}
 	}
 
-	deviceScores := sortByScore(orcheEngine.gatherDevicesScore(candidates))
+	deviceScores := scheduleDevices(scheduler.Schedule, serviceInfo.ServiceName, orcheEngine.gatherDevicesScore(candidates))
 	if len(deviceScores) <= 0 {
 		return ResponseService{
 			Message:          SERVICE_NOT_FOUND,
@@ -150,32 +267,161 @@ func (orcheEngine *orcheImpl) RequestService(serviceInfo ReqeustService) Respons
 		}
 	}
 
-	args, err := getExecCmds(deviceScores[0].execType, serviceInfo.ServiceInfo)
-	if err != nil {
-		log.Println(err.Error())
+	replicas, err := selectReplicas(deviceScores, serviceInfo.Replicas, serviceInfo.SpreadPolicy)
+	if serviceInfo.PendingTimeout > 0 && err != nil {
+		if fromPendingQueue {
+			return ResponseService{
+				Message:          err.Error(),
+				ServiceName:      serviceInfo.ServiceName,
+				RemoteTargetInfo: TargetInfo{},
+			}
+		}
+		return enqueuePendingRequest(orcheEngine.retryPendingService, serviceInfo, done, err.Error())
+	} else if err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
 		return ResponseService{
-			Message:          err.Error(),
+			Message:          SERVICE_NOT_FOUND,
 			ServiceName:      serviceInfo.ServiceName,
 			RemoteTargetInfo: TargetInfo{},
 		}
 	}
 
-	orcheEngine.executeApp(deviceScores[0].endpoint, serviceInfo.ServiceName, args, serviceClient.notiChan)
-	log.Println("[orchestrationapi] ", deviceScores)
+	replicaArgs := make([][]string, len(replicas))
+	for idx, replica := range replicas {
+		replicaArgs[idx], err = getExecCmds(replica.execType, serviceInfo.ServiceInfo)
+		if err != nil {
+			log.Println(err.Error())
+			return ResponseService{
+				Message:          err.Error(),
+				ServiceName:      serviceInfo.ServiceName,
+				RemoteTargetInfo: TargetInfo{},
+			}
+		}
+	}
+
+	var group *serviceGroup
+	if len(replicas) > 1 {
+		group = newServiceGroup(newServiceGroupID(serviceInfo.ServiceName), serviceInfo.ServiceName, replicas)
+		group.done = done
+	} else {
+		serviceClient.done = done
+	}
+
+	targets := make([]TargetInfo, len(replicas))
+	for idx, replica := range replicas {
+		client := serviceClient
+		if idx > 0 {
+			client = newServiceClient(serviceInfo.ServiceName)
+		}
+		client.group = group
+		client.replica = idx
+
+		schedulerIns.RecordPlacement(replica.id)
+		client.setDevice(replica.id)
+
+		opts := orcheEngine.executionOptions(serviceInfo, replica, client)
+		serviceID, err := orcheEngine.executeApp(replica.endpoint, serviceInfo.ServiceName, replicaArgs[idx], opts, client.notiChan)
+		if err != nil {
+			// NOTE : the device may not notify anything if the request is not delivered
+			schedulerIns.RecordCompletion(replica.id)
+			if group == nil {
+				return ResponseService{
+					Message:          err.Error(),
+					ServiceName:      serviceInfo.ServiceName,
+					RemoteTargetInfo: TargetInfo{},
+				}
+			}
+			group.report(idx, servicemgr.ConstServiceStatusFailed)
+		} else {
+			if group != nil {
+				group.start(idx)
+			}
+			go client.listenNotify()
+		}
+
+		targets[idx] = TargetInfo{
+			ExecutionType: replica.execType,
+			Target:        replica.endpoint,
+			ServiceID:     serviceID,
+		}
+	}
+	log.Println("[orchestrationapi] ", scheduler.Name(), deviceScores)
+
+	resp := ResponseService{
+		Message:          ERROR_NONE,
+		ServiceName:      serviceInfo.ServiceName,
+		RemoteTargetInfo: targets[0],
+	}
+	if group != nil {
+		resp.GroupID = group.id
+		resp.ReplicaTargetInfo = targets
+	}
+
+	return resp
+}
+
+// beginRequest registers in-flight request if orchestration is ready
+func (orcheEngine *orcheImpl) beginRequest() bool {
+	requestMtx.Lock()
+	defer requestMtx.Unlock()
+
+	if orcheEngine.Ready == false {
+		return false
+	}
+	requestCount++
+
+	return true
+}
+
+// endRequest unregisters in-flight request and wakes up Stop waiting for draining
+func (orcheEngine *orcheImpl) endRequest() {
+	requestMtx.Lock()
+	defer requestMtx.Unlock()
+
+	requestCount--
+	if requestCount == 0 && requestDrained != nil {
+		close(requestDrained)
+		requestDrained = nil
+	}
+}
+
+// SubscribeDeviceEvent gives the stream of device join/update/leave events until cancel is called
+func (orcheEngine *orcheImpl) SubscribeDeviceEvent() (<-chan discoverymgr.DeviceEvent, func()) {
+	bus := eventbus.GetInstance()
+	sub := bus.Subscribe(discoverymgr.DeviceEventTopic)
+	events := make(chan discoverymgr.DeviceEvent, cap(sub.C))
+
+	go func() {
+		defer close(events)
+		for data := range sub.C {
+			event, ok := data.(discoverymgr.DeviceEvent)
+			if !ok {
+				continue
+			}
+			select {
+			case events <- event:
+			default:
+				log.Println("[SubscribeDeviceEvent]", "subscriber is not receiving", event.DeviceID)
+			}
+		}
+	}()
 
-	return ResponseService{
-		Message:     ERROR_NONE,
-		ServiceName: serviceInfo.ServiceName,
-		RemoteTargetInfo: TargetInfo{
-			ExecutionType: deviceScores[0].execType,
-			Target:        deviceScores[0].endpoint,
-		},
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			bus.Unsubscribe(sub)
+		})
 	}
+
+	return events, cancel
 }
 
 func getExecCmds(execType string, requestServiceInfos []RequestServiceInfo) ([]string, error) {
 	for _, requestServiceInfo := range requestServiceInfos {
 		if execType == requestServiceInfo.ExecutionType {
+			if requestServiceInfo.ContainerSpec != nil {
+				return requestServiceInfo.ContainerSpec.Args()
+			}
 			return requestServiceInfo.ExeCmd, nil
 		}
 	}
@@ -183,11 +429,32 @@ func getExecCmds(execType string, requestServiceInfos []RequestServiceInfo) ([]s
 	return nil, errors.New("Not Found")
 }
 
-func (orcheEngine orcheImpl) getCandidate(appName string, execType []string) (deviceList []dbhelper.ExecutionCandidate, err error) {
-	return helper.GetDeviceInfoWithService(appName, execType)
+// validateServiceInfos checks the container specs of the request, only container execution type can have it
+func validateServiceInfos(requestServiceInfos []RequestServiceInfo) error {
+	for _, requestServiceInfo := range requestServiceInfos {
+		if requestServiceInfo.ContainerSpec == nil {
+			continue
+		}
+		if requestServiceInfo.ExecutionType != "container" {
+			return errors.New("container spec is given to " + requestServiceInfo.ExecutionType)
+		}
+		if err := requestServiceInfo.ContainerSpec.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (orcheEngine orcheImpl) getCandidate(appName string, execType []string, selector dbhelper.LabelSelector) (deviceList []dbhelper.ExecutionCandidate, err error) {
+	return helper.GetDeviceInfoWithService(appName, execType, selector)
 }
 
 func (orcheEngine orcheImpl) gatherDevicesScore(candidates []dbhelper.ExecutionCandidate) (deviceScores []deviceScore) {
+	return orcheEngine.collectDevicesScore(candidates, false)
+}
+
+// collectDevicesScore gets scores of candidates concurrently, sub-scores are also collected if detail is set
+func (orcheEngine orcheImpl) collectDevicesScore(candidates []dbhelper.ExecutionCandidate, detail bool) (deviceScores []deviceScore) {
 	scores := make(chan deviceScore, len(candidates))
 	count := len(candidates)
 
@@ -222,21 +489,33 @@ func (orcheEngine orcheImpl) gatherDevicesScore(candidates []dbhelper.ExecutionC
 	for _, candidate := range candidates {
 		go func(cand dbhelper.ExecutionCandidate) {
 			var score float64
+			var components map[string]float64
 			var err error
 
-			if dbcommon.HasElem(cand.Endpoint, localhost) {
+			start := time.Now()
+			isLocal := dbcommon.HasElem(cand.Endpoint, localhost)
+			switch {
+			case isLocal && orcheEngine.serviceIns.GetCapacity().Saturated:
+				err = errors.New("device is saturated")
+			case isLocal && detail:
+				score, components, err = orcheEngine.GetScoreWithComponents(info.Value)
+			case isLocal:
 				score, err = orcheEngine.GetScore(info.Value)
-			} else {
+			case detail:
+				score, components, err = orcheEngine.clientAPI.DoGetScoreDetailRemoteDevice(info.Value, cand.Endpoint[0])
+			default:
 				// TODO change index of ips
 				score, err = orcheEngine.clientAPI.DoGetScoreRemoteDevice(info.Value, cand.Endpoint[0])
 			}
+			scoringDuration.Observe(time.Since(start).Seconds(), cand.Id)
 
 			if err != nil {
+				scoringErrors.Inc(cand.Id)
 				log.Println("[orchestrationapi] ", "cannot getting score from : ", cand.Endpoint[0], " cause by ", err.Error())
-				scores <- deviceScore{endpoint: cand.Endpoint[0], score: float64(0.0), id: cand.Id}
+				scores <- deviceScore{endpoint: cand.Endpoint[0], score: float64(0.0), id: cand.Id, labels: cand.Labels, isLocal: isLocal, err: err}
 				return
 			}
-			scores <- deviceScore{endpoint: cand.Endpoint[0], score: score, id: cand.Id, execType: cand.ExecType}
+			scores <- deviceScore{endpoint: cand.Endpoint[0], score: score, id: cand.Id, execType: cand.ExecType, labels: cand.Labels, isLocal: isLocal, components: components}
 		}(candidate)
 	}
 
@@ -245,35 +524,146 @@ func (orcheEngine orcheImpl) gatherDevicesScore(candidates []dbhelper.ExecutionC
 	return
 }
 
-func (orcheEngine orcheImpl) executeApp(endpoint string, serviceName string, args []string, notiChan chan string) {
+// executionOptions gives the options of execution of the request on replica
+func (orcheEngine orcheImpl) executionOptions(serviceInfo ReqeustService, replica deviceScore, client *orcheClient) servicemgr.ExecutionOptions {
+	return servicemgr.ExecutionOptions{
+		Stdin:    serviceInfo.Stdin,
+		Timeout:  serviceInfo.ExecutionTimeout,
+		ExecType: replica.execType,
+		Restart:  serviceInfo.RestartPolicy,
+		DeviceID: replica.id,
+		Relocate: orcheEngine.relocator(serviceInfo, client),
+	}
+}
+
+// relocator places the service of the request on the best device except the lost one,
+// the placement history follows the service to the new device
+func (orcheEngine orcheImpl) relocator(serviceInfo ReqeustService, client *orcheClient) servicemgr.Relocator {
+	return func(lost servicemgr.Placement) (placement servicemgr.Placement, err error) {
+		scheduler, err := schedulerIns.GetScheduler(serviceInfo.SchedulingPolicy)
+		if err != nil {
+			return
+		}
+
+		executionTypes := make([]string, 0)
+		for _, info := range serviceInfo.ServiceInfo {
+			executionTypes = append(executionTypes, info.ExecutionType)
+		}
+
+		selector := dbhelper.LabelSelector{
+			Required:  serviceInfo.RequiredLabels,
+			Preferred: serviceInfo.PreferredLabels,
+		}
+
+		candidates, err := orcheEngine.getCandidate(serviceInfo.ServiceName, executionTypes, selector)
+		if err != nil {
+			return
+		}
+
+		available := make([]dbhelper.ExecutionCandidate, 0, len(candidates))
+		for _, candidate := range candidates {
+			if candidate.Id != lost.DeviceID {
+				available = append(available, candidate)
+			}
+		}
+		if len(available) == 0 {
+			return placement, errors.New("no other device can run the service")
+		}
+
+		for _, device := range scheduleDevices(scheduler.Schedule, serviceInfo.ServiceName, orcheEngine.gatherDevicesScore(available)) {
+			if device.err != nil {
+				continue
+			}
+			args, err := getExecCmds(device.execType, serviceInfo.ServiceInfo)
+			if err != nil {
+				continue
+			}
+
+			schedulerIns.RecordCompletion(lost.DeviceID)
+			schedulerIns.RecordPlacement(device.id)
+			client.setDevice(device.id)
+
+			return servicemgr.Placement{DeviceID: device.id, Target: device.endpoint, ExecType: device.execType, Args: execArgs(args)}, nil
+		}
+		return placement, errors.New("no other device can run the service")
+	}
+}
+
+func execArgs(args []string) []interface{} {
 	ifArgs := make([]interface{}, len(args))
 	for i, v := range args {
 		ifArgs[i] = v
 	}
+	return ifArgs
+}
 
-	orcheEngine.serviceIns.Execute(endpoint, serviceName, ifArgs, notiChan)
+func (orcheEngine orcheImpl) executeApp(endpoint string, serviceName string, args []string, opts servicemgr.ExecutionOptions, notiChan chan string) (uint64, error) {
+	serviceID, err := orcheEngine.serviceIns.Execute(endpoint, serviceName, execArgs(args), opts, notiChan)
+	if err != nil {
+		log.Println("[orchestrationapi] ", "cannot execute on : ", endpoint, " cause by ", err.Error())
+	}
+	return serviceID, err
 }
 
 func (client *orcheClient) listenNotify() {
 	select {
 	case str := <-client.notiChan:
 		log.Printf("[orchestrationapi] service status changed [appNames:%s][status:%s]\n", client.appName, str)
+		schedulerIns.RecordCompletion(client.device())
+		if client.group != nil {
+			client.group.report(client.replica, str)
+		} else if client.done != nil {
+			client.done(str)
+		}
 	}
 }
 
-func addServiceClient(clientID int, appName string) (client *orcheClient) {
-	// orcheClients[clientID].args = args
-	orcheClients[clientID].appName = appName
-	orcheClients[clientID].notiChan = make(chan string)
+func (client *orcheClient) setDevice(deviceID string) {
+	client.mtx.Lock()
+	defer client.mtx.Unlock()
 
-	client = &orcheClients[clientID]
-	return
+	client.deviceID = deviceID
+}
+
+func (client *orcheClient) device() string {
+	client.mtx.Lock()
+	defer client.mtx.Unlock()
+
+	return client.deviceID
 }
 
-func sortByScore(deviceScores []deviceScore) []deviceScore {
-	sort.Slice(deviceScores, func(i, j int) bool {
-		return deviceScores[i].score > deviceScores[j].score
-	})
+// newServiceClient returns the state of a request which is owned by the request until its service is done
+func newServiceClient(appName string) *orcheClient {
+	return &orcheClient{
+		appName: appName,
+		// a status notified after the execution failed is not waited, it should not block the notifier
+		notiChan: make(chan string, 1),
+	}
+}
+
+// scheduleDevices orders devices with Schedule or Preview of scheduler using their scores, labels and placement history
+func scheduleDevices(order func(string, []schedulermgr.Candidate) []schedulermgr.Candidate, serviceName string, deviceScores []deviceScore) []deviceScore {
+	candidates := make([]schedulermgr.Candidate, len(deviceScores))
+	scoreMap := make(map[string]deviceScore)
+	for idx, device := range deviceScores {
+		candidates[idx] = schedulermgr.Candidate{
+			ID:       device.id,
+			Endpoint: device.endpoint,
+			ExecType: device.execType,
+			Score:    device.score,
+			Labels:   device.labels,
+			IsLocal:  device.isLocal,
+			History:  schedulerIns.GetHistory(device.id),
+		}
+		scoreMap[device.id] = device
+	}
+
+	ordered := order(serviceName, candidates)
+
+	scheduled := make([]deviceScore, 0, len(ordered))
+	for _, candidate := range ordered {
+		scheduled = append(scheduled, scoreMap[candidate.ID])
+	}
 
-	return deviceScores
+	return scheduled
 }
@@ -0,0 +1,144 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	"log"
+
+	dbhelper "db/helper"
+)
+
+// CandidateExplanation describes how a device was evaluated for a service request
+type CandidateExplanation struct {
+	DeviceID        string
+	ExecutionType   string
+	Endpoint        string
+	Labels          map[string]string
+	Score           float64
+	ScoreComponents map[string]float64
+	// Error is the reason why the score could not be gotten
+	Error string
+	// ExclusionReason is the reason why the device is not a candidate
+	ExclusionReason string
+	// Rank is the order given by the scheduler starting from 1, 0 if the device is excluded
+	Rank int
+}
+
+// DryRunResponse is the placement decision of a service request which is not executed
+type DryRunResponse struct {
+	Message          string
+	ServiceName      string
+	SchedulingPolicy string
+	RemoteTargetInfo TargetInfo
+	Candidates       []CandidateExplanation
+}
+
+// RequestServiceDryRun evaluates a service request like RequestService without executing anything
+// and explains the score, the error and the exclusion reason of every device
+func (orcheEngine *orcheImpl) RequestServiceDryRun(serviceInfo ReqeustService) DryRunResponse {
+	log.Printf("[RequestServiceDryRun] %v: %v\n", serviceInfo.ServiceName, serviceInfo.ServiceInfo)
+	resp := DryRunResponse{
+		ServiceName: serviceInfo.ServiceName,
+		Candidates:  make([]CandidateExplanation, 0),
+	}
+
+	if orcheEngine.beginRequest() == false {
+		resp.Message = INTERNAL_SERVER_ERROR
+		return resp
+	}
+	defer orcheEngine.endRequest()
+
+	scheduler, err := schedulerIns.GetScheduler(serviceInfo.SchedulingPolicy)
+	if err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
+		resp.Message = INVALID_PARAMETER
+		return resp
+	}
+	resp.SchedulingPolicy = scheduler.Name()
+
+	if err := validateServiceInfos(serviceInfo.ServiceInfo); err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
+		resp.Message = INVALID_PARAMETER
+		return resp
+	}
+
+	executionTypes := make([]string, 0)
+	for _, info := range serviceInfo.ServiceInfo {
+		executionTypes = append(executionTypes, info.ExecutionType)
+	}
+
+	selector := dbhelper.LabelSelector{
+		Required:  serviceInfo.RequiredLabels,
+		Preferred: serviceInfo.PreferredLabels,
+	}
+
+	candidates, excluded, err := helper.ExplainDeviceInfoWithService(serviceInfo.ServiceName, executionTypes, selector)
+	for _, device := range excluded {
+		resp.Candidates = append(resp.Candidates, CandidateExplanation{
+			DeviceID:        device.Id,
+			ExecutionType:   device.ExecType,
+			Labels:          device.Labels,
+			ExclusionReason: device.Reason,
+		})
+	}
+	if err != nil {
+		resp.Message = err.Error()
+		return resp
+	}
+
+	execTypes := make(map[string]string)
+	for _, candidate := range candidates {
+		execTypes[candidate.Id] = candidate.ExecType
+	}
+
+	// NOTE : the dry run should not change the order of the next request
+	deviceScores := scheduleDevices(scheduler.Preview, serviceInfo.ServiceName, orcheEngine.collectDevicesScore(candidates, true))
+	if len(deviceScores) <= 0 {
+		resp.Message = SERVICE_NOT_FOUND
+		return resp
+	}
+
+	for idx, device := range deviceScores {
+		explanation := CandidateExplanation{
+			DeviceID:        device.id,
+			ExecutionType:   execTypes[device.id],
+			Endpoint:        device.endpoint,
+			Labels:          device.labels,
+			Score:           device.score,
+			ScoreComponents: device.components,
+			Rank:            idx + 1,
+		}
+		if device.err != nil {
+			explanation.Error = device.err.Error()
+		}
+		resp.Candidates = append(resp.Candidates, explanation)
+	}
+
+	if _, err := getExecCmds(deviceScores[0].execType, serviceInfo.ServiceInfo); err != nil {
+		resp.Message = err.Error()
+		return resp
+	}
+
+	resp.Message = ERROR_NONE
+	resp.RemoteTargetInfo = TargetInfo{
+		ExecutionType: deviceScores[0].execType,
+		Target:        deviceScores[0].endpoint,
+	}
+
+	return resp
+}
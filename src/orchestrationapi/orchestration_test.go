@@ -18,8 +18,10 @@
 package orchestrationapi
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -200,17 +202,112 @@ func TestStart(t *testing.T) {
 		deviceIDPath := "/etc/"
 		platform := "linux"
 		executionType := "container"
+		labelPath := "/etc/labels"
 
 		gomock.InOrder(
 			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor),
 			mockResourceutil.EXPECT().StartMonitoringResource(),
-			mockDiscovery.EXPECT().StartDiscovery(gomock.Eq(deviceIDPath), gomock.Eq(platform), gomock.Eq(executionType)),
+			mockDiscovery.EXPECT().StartDiscovery(gomock.Eq(deviceIDPath), gomock.Eq(platform), gomock.Eq([]string{executionType}), gomock.Eq(labelPath)),
 			mockWatcher.EXPECT().Watch(gomock.Any()),
 		)
 
-		getOcheIns(ctrl).Start(deviceIDPath, platform, executionType)
+		getOcheIns(ctrl).Start(deviceIDPath, platform, executionType, labelPath)
+	})
+	t.Run("MultipleExecutors", func(t *testing.T) {
+		deviceIDPath := "/etc/"
+		platform := "linux"
+		executionType := "native"
+		labelPath := "/etc/labels"
+
+		var builder OrchestrationBuilder
+		builder.SetDiscovery(mockDiscovery)
+		builder.SetScoring(mockScoring)
+		builder.SetService(mockService)
+		builder.SetWatcher(mockWatcher)
+		builder.SetClient(mockClient)
+		builder.AddExecutor("native", mockExecutor)
+		builder.AddExecutor("container", mockExecutor)
+
+		mockService.EXPECT().AddLocalServiceExecutor(gomock.Eq("native"), mockExecutor)
+		mockService.EXPECT().AddLocalServiceExecutor(gomock.Eq("container"), mockExecutor)
+		gomock.InOrder(
+			mockResourceutil.EXPECT().StartMonitoringResource(),
+			mockDiscovery.EXPECT().StartDiscovery(gomock.Eq(deviceIDPath), gomock.Eq(platform), gomock.Eq([]string{"native", "container"}), gomock.Eq(labelPath)),
+			mockWatcher.EXPECT().Watch(gomock.Any()),
+		)
+
+		orche := builder.Build()
+		if orche == nil {
+			t.Fatal("unexpected nil orchestration")
+		}
+		resourceMonitorImpl = mockResourceutil
+		orche.Start(deviceIDPath, platform, executionType, labelPath)
 	})
 }
+
+func TestStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createMockIns(ctrl)
+
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor),
+			mockService.EXPECT().NotifyTermination(),
+			mockResourceutil.EXPECT().StopMonitoringResource(),
+			mockDiscovery.EXPECT().StopDiscovery(),
+		)
+
+		orche := getOcheIns(ctrl)
+		getOrcheImple().Ready = true
+
+		if err := orche.Stop(context.Background()); err != nil {
+			t.Error("unexpected error " + err.Error())
+		}
+		if getOrcheImple().Ready == true {
+			t.Error("unexpected ready flag")
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		t.Run("NotReady", func(t *testing.T) {
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+
+			orche := getOcheIns(ctrl)
+			getOrcheImple().Ready = false
+
+			if err := orche.Stop(context.Background()); err == nil {
+				t.Error("unexpected success")
+			}
+		})
+		t.Run("DrainTimeout", func(t *testing.T) {
+			gomock.InOrder(
+				mockService.EXPECT().SetLocalServiceExecutor(mockExecutor),
+				mockService.EXPECT().NotifyTermination(),
+				mockResourceutil.EXPECT().StopMonitoringResource(),
+				mockDiscovery.EXPECT().StopDiscovery(),
+			)
+
+			orche := getOcheIns(ctrl)
+			getOrcheImple().Ready = true
+			if getOrcheImple().beginRequest() == false {
+				t.Fatal("unexpected request rejection")
+			}
+			defer getOrcheImple().endRequest()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			if err := orche.Stop(ctx); err != context.DeadlineExceeded {
+				t.Error("unexpected error ", err)
+			}
+			if getOrcheImple().beginRequest() == true {
+				t.Error("unexpected request acceptance after stop")
+			}
+		})
+	})
+}
+
 func TestNotify(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
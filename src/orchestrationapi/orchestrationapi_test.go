@@ -23,9 +23,14 @@ $ go test -failfast -v -count=1 orchestrationapi
 package orchestrationapi
 
 import (
+	"controller/schedulermgr"
+	"controller/servicemgr"
+	"controller/servicemgr/executor/containerspec"
 	sysDB "db/bolt/system"
 	dbhelper "db/helper"
 	"errors"
+	"reflect"
+	"time"
 
 	"testing"
 
@@ -78,13 +83,13 @@ func TestRequestService(t *testing.T) {
 
 		gomock.InOrder(
 			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor),
-			mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any()).Return(candidateInfos, nil),
+			mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(candidateInfos, nil),
 			mockSystemDBExecutor.EXPECT().Get("id").Return(sysInfo, nil),
 			mockNetwork.EXPECT().GetOutboundIP().Return("", nil),
 			mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Any()).Return(scores[0], nil),
 			mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Any()).Return(scores[1], nil),
 			mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Any()).Return(scores[2], nil),
-			mockService.EXPECT().Execute(gomock.Any(), appName, gomock.Any(), gomock.Any()),
+			mockService.EXPECT().Execute(gomock.Any(), appName, gomock.Any(), gomock.Any(), gomock.Any()),
 		)
 
 		o := getOcheIns(ctrl)
@@ -102,7 +107,112 @@ func TestRequestService(t *testing.T) {
 		}
 	})
 
+	t.Run("Replicas", func(t *testing.T) {
+		scores := []float64{float64(1.0), float64(2.0), float64(3.0)}
+
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(candidateInfos, nil)
+		mockSystemDBExecutor.EXPECT().Get("id").Return(sysInfo, nil)
+		mockNetwork.EXPECT().GetOutboundIP().Return("", nil)
+		for idx, score := range scores {
+			mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Eq(candidateInfos[idx].Endpoint[0])).Return(score, nil)
+		}
+		mockService.EXPECT().Execute(gomock.Eq("endpoint3"), appName, gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(3), nil)
+		mockService.EXPECT().Execute(gomock.Eq("endpoint2"), appName, gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(4), nil)
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		request := requestServiceInfo
+		request.Replicas = 2
+		request.SchedulingPolicy = schedulermgr.BestScore
+		res := oche.RequestService(request)
+		if res.Message != ERROR_NONE || len(res.GroupID) == 0 || len(res.ReplicaTargetInfo) != 2 {
+			t.Error("unexpected response : ", res)
+		} else if res.ReplicaTargetInfo[0].ServiceID != 3 || res.ReplicaTargetInfo[1].ServiceID != 4 {
+			t.Error("unexpected service IDs : ", res.ReplicaTargetInfo)
+		}
+	})
+
+	t.Run("RestartPolicy", func(t *testing.T) {
+		scores := []float64{float64(1.0), float64(2.0), float64(3.0)}
+
+		var opts servicemgr.ExecutionOptions
+		var notiChan chan string
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(candidateInfos, nil).Times(2)
+		mockSystemDBExecutor.EXPECT().Get("id").Return(sysInfo, nil).Times(2)
+		mockNetwork.EXPECT().GetOutboundIP().Return("", nil).Times(2)
+		for idx, score := range scores {
+			mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Eq(candidateInfos[idx].Endpoint[0])).Return(score, nil).MinTimes(1)
+		}
+		mockService.EXPECT().Execute(gomock.Eq("endpoint3"), appName, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(target string, name string, args []interface{}, executionOpts servicemgr.ExecutionOptions, noti chan string) (uint64, error) {
+				opts, notiChan = executionOpts, noti
+				return 5, nil
+			})
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		request := requestServiceInfo
+		request.SchedulingPolicy = schedulermgr.BestScore
+		request.RestartPolicy = servicemgr.RestartPolicy{Policy: servicemgr.RestartAlways}
+		res := oche.RequestService(request)
+		if res.Message != ERROR_NONE {
+			t.Fatal("unexpected response : ", res)
+		}
+		if opts.Restart != request.RestartPolicy || opts.DeviceID != "ID3" || opts.Relocate == nil {
+			t.Fatal("unexpected options : ", opts)
+		}
+
+		// @Note : the service is relocated to the best device except the lost one
+		placement, err := opts.Relocate(servicemgr.Placement{DeviceID: "ID3", Target: "endpoint3"})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if placement.DeviceID != "ID2" || placement.Target != "endpoint2" || len(placement.Args) != len(args) {
+			t.Error("unexpected placement : ", placement)
+		}
+
+		// @Note : the state of the supervised service is not taken by later requests
+		for idx := 0; idx <= 1024; idx++ {
+			newServiceClient(appName).setDevice("ID1")
+		}
+
+		// @Note : the completion is recorded on the device the service is relocated to
+		running := schedulerIns.GetHistory("ID2").Running
+		notiChan <- servicemgr.ConstServiceStatusFinished
+		deadline := time.Now().Add(time.Second)
+		for schedulerIns.GetHistory("ID2").Running == running && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if schedulerIns.GetHistory("ID2").Running != running-1 {
+			t.Error("completion is not recorded on the relocated device")
+		}
+	})
+
 	t.Run("Error", func(t *testing.T) {
+		t.Run("ExecuteFail", func(t *testing.T) {
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+			mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(candidateInfos[:1], nil)
+			mockSystemDBExecutor.EXPECT().Get("id").Return(sysInfo, nil)
+			mockNetwork.EXPECT().GetOutboundIP().Return("", nil)
+			mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Eq("endpoint1")).Return(float64(1.0), nil).AnyTimes()
+			mockService.EXPECT().Execute(gomock.Eq("endpoint1"), appName, gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(0), errors.New("connection refused"))
+
+			getOcheIns(ctrl)
+			oche := getOrcheImple()
+			oche.Ready = true
+
+			request := requestServiceInfo
+			request.SchedulingPolicy = schedulermgr.BestScore
+			if res := oche.RequestService(request); res.Message != "connection refused" {
+				t.Error("unexpected message : ", res.Message)
+			}
+		})
 		t.Run("NotReady", func(t *testing.T) {
 			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
 			o := getOcheIns(ctrl)
@@ -119,7 +229,7 @@ func TestRequestService(t *testing.T) {
 		t.Run("DiscoveryFail", func(t *testing.T) {
 			gomock.InOrder(
 				mockService.EXPECT().SetLocalServiceExecutor(mockExecutor),
-				mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any()).Return(nil, errors.New("-3")),
+				mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(nil, errors.New("-3")),
 			)
 			o := getOcheIns(ctrl)
 			if o == nil {
@@ -135,5 +245,224 @@ func TestRequestService(t *testing.T) {
 				t.Error("unexpected Error")
 			}
 		})
+		t.Run("UnknownSpreadPolicy", func(t *testing.T) {
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+			getOcheIns(ctrl)
+
+			oche := getOrcheImple()
+			oche.Ready = true
+
+			request := requestServiceInfo
+			request.SpreadPolicy = "unknown"
+			res := oche.RequestService(request)
+			if res.Message != INVALID_PARAMETER {
+				t.Error("unexpected Error")
+			}
+		})
+		t.Run("UnknownRestartPolicy", func(t *testing.T) {
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+			getOcheIns(ctrl)
+
+			oche := getOrcheImple()
+			oche.Ready = true
+
+			request := requestServiceInfo
+			request.RestartPolicy = servicemgr.RestartPolicy{Policy: "unknown"}
+			res := oche.RequestService(request)
+			if res.Message != INVALID_PARAMETER {
+				t.Error("unexpected Error")
+			}
+		})
+		t.Run("InvalidContainerSpec", func(t *testing.T) {
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+			getOcheIns(ctrl)
+
+			oche := getOrcheImple()
+			oche.Ready = true
+
+			request := requestServiceInfo
+			request.ServiceInfo = []RequestServiceInfo{{
+				ExecutionType: "native",
+				ContainerSpec: &containerspec.Spec{Image: "hello-world"},
+			}}
+			res := oche.RequestService(request)
+			if res.Message != INVALID_PARAMETER {
+				t.Error("unexpected Error")
+			}
+		})
+		t.Run("UnknownSchedulingPolicy", func(t *testing.T) {
+			mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+			getOcheIns(ctrl)
+
+			oche := getOrcheImple()
+			oche.Ready = true
+
+			request := requestServiceInfo
+			request.SchedulingPolicy = "unknown"
+			res := oche.RequestService(request)
+			if res.Message != INVALID_PARAMETER {
+				t.Error("unexpected Error")
+			}
+		})
+	})
+}
+
+func TestGetExecCmds(t *testing.T) {
+	spec := containerspec.Spec{Image: "hello-world"}
+	infos := []RequestServiceInfo{
+		{ExecutionType: "native", ExeCmd: []string{"ls"}},
+		{ExecutionType: "container", ExeCmd: []string{"docker", "run", "alpine"}, ContainerSpec: &spec},
+	}
+
+	if args, err := getExecCmds("native", infos); err != nil || !reflect.DeepEqual(args, []string{"ls"}) {
+		t.Error("unexpected args : ", args, err)
+	}
+
+	args, err := getExecCmds("container", infos)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if parsed, ok, err := containerspec.Parse(args); !ok || err != nil || parsed.Image != spec.Image {
+		t.Error("unexpected args : ", args, err)
+	}
+}
+
+func TestScheduleDevices(t *testing.T) {
+	deviceScores := []deviceScore{
+		{id: "ID1", endpoint: "endpoint1", score: 3.0},
+		{id: "ID2", endpoint: "endpoint2", score: 1.0, isLocal: true},
+		{id: "ID3", endpoint: "endpoint3", score: 2.0},
+	}
+
+	t.Run("BestScore", func(t *testing.T) {
+		scheduler, _ := schedulerIns.GetScheduler(schedulermgr.BestScore)
+		scheduled := scheduleDevices(scheduler.Schedule, "MyApp", deviceScores)
+		if len(scheduled) != 3 || scheduled[0].endpoint != "endpoint1" || scheduled[2].endpoint != "endpoint2" {
+			t.Error("unexpected order : ", scheduled)
+		}
+	})
+	t.Run("PreferLocal", func(t *testing.T) {
+		scheduler, _ := schedulerIns.GetScheduler(schedulermgr.PreferLocal)
+		scheduled := scheduleDevices(scheduler.Schedule, "MyApp", deviceScores)
+		if len(scheduled) != 3 || scheduled[0].endpoint != "endpoint2" {
+			t.Error("unexpected order : ", scheduled)
+		}
+	})
+}
+
+func TestRequestServiceDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createMockIns(ctrl)
+
+	appName := "MyApp"
+
+	var requestServiceInfo ReqeustService
+	requestServiceInfo.ServiceName = appName
+	requestServiceInfo.ServiceInfo = []RequestServiceInfo{
+		{
+			ExecutionType: "platform",
+			ExeCmd:        []string{"-a"},
+		},
+	}
+
+	candidateInfos := []dbhelper.ExecutionCandidate{
+		{Id: "ID1", ExecType: "platform", Endpoint: []string{"endpoint1"}},
+		{Id: "ID2", ExecType: "platform", Endpoint: []string{"endpoint2"}},
+	}
+	excluded := []dbhelper.ExcludedDevice{
+		{Id: "ID3", ExecType: "container", Reason: dbhelper.ExcludedByExecType},
+	}
+
+	sysInfo := sysDB.SystemInfo{
+		Name:  "ID",
+		Value: "ID",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		components := map[string]float64{"network": 1.0, "cpu": 2.0, "rendering": 1.0}
+
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().ExplainDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(candidateInfos, excluded, nil)
+		mockSystemDBExecutor.EXPECT().Get("id").Return(sysInfo, nil)
+		mockNetwork.EXPECT().GetOutboundIP().Return("", nil)
+		mockClient.EXPECT().DoGetScoreDetailRemoteDevice(gomock.Any(), gomock.Eq("endpoint1")).Return(float64(3.0), components, nil)
+		mockClient.EXPECT().DoGetScoreDetailRemoteDevice(gomock.Any(), gomock.Eq("endpoint2")).Return(float64(0.0), nil, errors.New("unreachable"))
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		res := oche.RequestServiceDryRun(requestServiceInfo)
+		if res.Message != ERROR_NONE {
+			t.Fatal("unexpected message : ", res.Message)
+		}
+		if res.RemoteTargetInfo.Target != "endpoint1" || res.SchedulingPolicy != schedulermgr.BestScore {
+			t.Error("unexpected target : ", res.RemoteTargetInfo, res.SchedulingPolicy)
+		}
+		if len(res.Candidates) != 3 {
+			t.Fatal("unexpected candidates : ", res.Candidates)
+		}
+
+		explanations := make(map[string]CandidateExplanation)
+		for _, candidate := range res.Candidates {
+			explanations[candidate.DeviceID] = candidate
+		}
+		if explanations["ID1"].Rank != 1 || explanations["ID1"].ScoreComponents["cpu"] != 2.0 {
+			t.Error("unexpected explanation : ", explanations["ID1"])
+		}
+		if explanations["ID2"].Rank != 2 || explanations["ID2"].Error != "unreachable" {
+			t.Error("unexpected explanation : ", explanations["ID2"])
+		}
+		if explanations["ID3"].Rank != 0 || explanations["ID3"].ExclusionReason != dbhelper.ExcludedByExecType {
+			t.Error("unexpected explanation : ", explanations["ID3"])
+		}
+	})
+	t.Run("RoundRobin", func(t *testing.T) {
+		rotated := requestServiceInfo
+		rotated.ServiceName = "DryRunApp"
+		rotated.SchedulingPolicy = schedulermgr.RoundRobin
+
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().ExplainDeviceInfoWithService(gomock.Eq("DryRunApp"), gomock.Any(), gomock.Any()).Return(candidateInfos, nil, nil).Times(2)
+		mockSystemDBExecutor.EXPECT().Get("id").Return(sysInfo, nil).AnyTimes()
+		mockNetwork.EXPECT().GetOutboundIP().Return("", nil).AnyTimes()
+		mockClient.EXPECT().DoGetScoreDetailRemoteDevice(gomock.Any(), gomock.Any()).Return(float64(3.0), nil, nil).Times(4)
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		first := oche.RequestServiceDryRun(rotated)
+		second := oche.RequestServiceDryRun(rotated)
+		if first.Message != ERROR_NONE || first.RemoteTargetInfo.Target != second.RemoteTargetInfo.Target {
+			t.Error("dry run changes the order : ", first.RemoteTargetInfo, second.RemoteTargetInfo)
+		}
+
+		scheduler, _ := schedulerIns.GetScheduler(schedulermgr.RoundRobin)
+		scheduled := scheduleDevices(scheduler.Schedule, "DryRunApp", []deviceScore{
+			{id: "ID1", endpoint: "endpoint1", score: 3.0},
+			{id: "ID2", endpoint: "endpoint2", score: 3.0},
+		})
+		if scheduled[0].endpoint != first.RemoteTargetInfo.Target {
+			t.Error("unexpected target of the request : ", scheduled[0].endpoint)
+		}
+	})
+	t.Run("NoCandidate", func(t *testing.T) {
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().ExplainDeviceInfoWithService(gomock.Eq(appName), gomock.Any(), gomock.Any()).Return(nil, excluded, errors.New("-3"))
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		res := oche.RequestServiceDryRun(requestServiceInfo)
+		if res.Message == ERROR_NONE {
+			t.Error("unexpected message : ", res.Message)
+		}
+		if len(res.Candidates) != 1 || res.Candidates[0].ExclusionReason != dbhelper.ExcludedByExecType {
+			t.Error("unexpected candidates : ", res.Candidates)
+		}
 	})
 }
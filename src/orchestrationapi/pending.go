@@ -0,0 +1,299 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"common/errors"
+	"common/eventbus"
+	"controller/discoverymgr"
+)
+
+// Statuses of a pending request
+const (
+	PendingStatusWaiting   = "Waiting"
+	PendingStatusScheduled = "Scheduled"
+	PendingStatusExpired   = "Expired"
+)
+
+const maxFinishedPendingRequests = 64
+
+// pendingReasonStopped is the reason of the requests expired by stopping orchestration
+const pendingReasonStopped = "orchestration is stopped"
+
+// PendingRequest is a service request waiting for a device which can run it
+type PendingRequest struct {
+	RequestID   string
+	ServiceName string
+	Status      string
+	Deadline    time.Time
+	// Reason is why the request could not be scheduled at the last try
+	Reason string
+	// Response is the result of the request after it is scheduled
+	Response ResponseService
+}
+
+type pendingEntry struct {
+	PendingRequest
+
+	runner      serviceRunner
+	serviceInfo ReqeustService
+	done        func(status string)
+
+	// timer expires the request on its deadline unless it is being retried
+	timer    *time.Timer
+	retrying bool
+}
+
+var (
+	pendingRequestID int32
+
+	// pendingRetryInterval is the period to retry pending requests without device events, for freed capacity
+	pendingRetryInterval = 5 * time.Second
+
+	pendingMtx      sync.Mutex
+	pendingRequests = make(map[string]*pendingEntry)
+	pendingOrder    = make([]string, 0)
+	pendingFinished = make([]string, 0)
+	pendingRunning  bool
+	// pendingStop is closed by stopPendingQueue to stop the running worker
+	pendingStop chan struct{}
+)
+
+// GetPendingRequests returns every request in the pending queue including recently finished ones
+func (orcheEngine *orcheImpl) GetPendingRequests() []PendingRequest {
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	requests := make([]PendingRequest, 0, len(pendingRequests))
+	for _, id := range pendingFinished {
+		requests = append(requests, pendingRequests[id].PendingRequest)
+	}
+	for _, id := range pendingOrder {
+		requests = append(requests, pendingRequests[id].PendingRequest)
+	}
+	return requests
+}
+
+// GetPendingRequest returns the status of the request in the pending queue
+func (orcheEngine *orcheImpl) GetPendingRequest(requestID string) (PendingRequest, error) {
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	entry, exist := pendingRequests[requestID]
+	if !exist {
+		return PendingRequest{RequestID: requestID}, errors.NotFound{Message: "pending request " + requestID + " is not found"}
+	}
+	return entry.PendingRequest, nil
+}
+
+// enqueuePendingRequest keeps the request until its deadline and starts the pending queue worker if it is not running,
+// runner must not queue the request again
+func enqueuePendingRequest(runner serviceRunner, serviceInfo ReqeustService, done func(status string), reason string) ResponseService {
+	entry := &pendingEntry{
+		PendingRequest: PendingRequest{
+			RequestID:   "pending-" + strconv.Itoa(int(atomic.AddInt32(&pendingRequestID, 1))),
+			ServiceName: serviceInfo.ServiceName,
+			Status:      PendingStatusWaiting,
+			Deadline:    time.Now().Add(serviceInfo.PendingTimeout),
+			Reason:      reason,
+		},
+		runner:      runner,
+		serviceInfo: serviceInfo,
+		done:        done,
+	}
+	pendingMtx.Lock()
+	pendingRequests[entry.RequestID] = entry
+	pendingOrder = append(pendingOrder, entry.RequestID)
+	entry.timer = time.AfterFunc(serviceInfo.PendingTimeout, func() {
+		expirePendingRequest(entry)
+	})
+	if !pendingRunning {
+		pendingRunning = true
+		pendingStop = make(chan struct{})
+		go runPendingQueue(pendingStop)
+	}
+	pendingMtx.Unlock()
+
+	log.Printf("[orchestrationapi] request is pending [requestID:%s][serviceName:%s][reason:%s]\n", entry.RequestID, entry.ServiceName, reason)
+
+	return ResponseService{
+		Message:          PENDING,
+		ServiceName:      serviceInfo.ServiceName,
+		PendingRequestID: entry.RequestID,
+	}
+}
+
+// runPendingQueue retries pending requests when a device joins or is updated and periodically
+// until the queue is empty or stop is closed
+func runPendingQueue(stop <-chan struct{}) {
+	bus := eventbus.GetInstance()
+	sub := bus.Subscribe(discoverymgr.DeviceEventTopic)
+	defer bus.Unsubscribe(sub)
+
+	ticker := time.NewTicker(pendingRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data := <-sub.C:
+			if event, ok := data.(discoverymgr.DeviceEvent); ok && event.Type == discoverymgr.DeviceLeft {
+				continue
+			}
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		if retryPendingRequests(stop) == false {
+			return
+		}
+	}
+}
+
+// retryPendingRequests tries every waiting request once and returns false after the queue becomes empty,
+// a request which is not scheduled is expired if its deadline is passed or stop is closed while it is retried
+func retryPendingRequests(stop <-chan struct{}) bool {
+	pendingMtx.Lock()
+	entries := make([]*pendingEntry, 0, len(pendingOrder))
+	for _, id := range pendingOrder {
+		entries = append(entries, pendingRequests[id])
+	}
+	pendingMtx.Unlock()
+
+	for _, entry := range entries {
+		if startPendingRetry(entry) == false {
+			continue
+		}
+		status, reason := PendingStatusWaiting, entry.Reason
+
+		resp := entry.runner(entry.serviceInfo, entry.done)
+		if resp.Message == ERROR_NONE {
+			status = PendingStatusScheduled
+		} else if time.Now().After(entry.Deadline) {
+			status, reason = PendingStatusExpired, resp.Message
+		} else if isClosed(stop) {
+			status, reason = PendingStatusExpired, pendingReasonStopped
+		} else {
+			reason = resp.Message
+		}
+
+		finishPendingRequest(entry, status, reason, resp)
+	}
+
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	if len(pendingOrder) == 0 {
+		pendingRunning = false
+		return false
+	}
+	return true
+}
+
+// startPendingRetry marks the request as being retried if it is still waiting
+func startPendingRetry(entry *pendingEntry) bool {
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	if entry.Status != PendingStatusWaiting {
+		return false
+	}
+	entry.retrying = true
+	return true
+}
+
+// expirePendingRequest expires the request on its deadline,
+// the request being retried is expired by the retry if it is not scheduled
+func expirePendingRequest(entry *pendingEntry) {
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	if entry.Status != PendingStatusWaiting || entry.retrying {
+		return
+	}
+	finishPendingRequestLocked(entry, PendingStatusExpired, entry.Reason, ResponseService{ServiceName: entry.ServiceName})
+}
+
+// stopPendingQueue stops the pending queue worker and expires the waiting requests,
+// the worker is started again by the next pending request
+func stopPendingQueue() {
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	if pendingRunning {
+		close(pendingStop)
+		pendingRunning = false
+	}
+
+	for _, id := range append([]string{}, pendingOrder...) {
+		if entry := pendingRequests[id]; !entry.retrying {
+			finishPendingRequestLocked(entry, PendingStatusExpired, pendingReasonStopped, ResponseService{ServiceName: entry.ServiceName})
+		}
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func finishPendingRequest(entry *pendingEntry, status string, reason string, resp ResponseService) {
+	pendingMtx.Lock()
+	defer pendingMtx.Unlock()
+
+	entry.retrying = false
+	finishPendingRequestLocked(entry, status, reason, resp)
+}
+
+func finishPendingRequestLocked(entry *pendingEntry, status string, reason string, resp ResponseService) {
+	entry.Status = status
+	entry.Reason = reason
+	if status == PendingStatusWaiting {
+		return
+	}
+
+	log.Printf("[orchestrationapi] pending request is done [requestID:%s][status:%s]\n", entry.RequestID, status)
+	entry.timer.Stop()
+	entry.Response = resp
+	if status == PendingStatusExpired && entry.done != nil {
+		go entry.done(PendingStatusExpired)
+	}
+
+	for idx, id := range pendingOrder {
+		if id == entry.RequestID {
+			pendingOrder = append(pendingOrder[:idx], pendingOrder[idx+1:]...)
+			break
+		}
+	}
+
+	pendingFinished = append(pendingFinished, entry.RequestID)
+	if len(pendingFinished) > maxFinishedPendingRequests {
+		delete(pendingRequests, pendingFinished[0])
+		pendingFinished = pendingFinished[1:]
+	}
+}
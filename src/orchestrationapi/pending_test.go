@@ -0,0 +1,202 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	sysDB "db/bolt/system"
+	dbhelper "db/helper"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestPendingRequest(t *testing.T) {
+	defaultInterval := pendingRetryInterval
+	pendingRetryInterval = 10 * time.Millisecond
+	defer func() {
+		pendingRetryInterval = defaultInterval
+	}()
+
+	oche := getOrcheImple()
+	serviceInfo := ReqeustService{ServiceName: "MyApp", PendingTimeout: time.Minute}
+
+	waitPending := func(requestID string, status string) PendingRequest {
+		for i := 0; i < 100; i++ {
+			request, err := oche.GetPendingRequest(requestID)
+			if err != nil {
+				t.Fatal("unexpected error : ", err.Error())
+			}
+			if request.Status == status {
+				return request
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("pending request is not ", status)
+		return PendingRequest{}
+	}
+
+	t.Run("Scheduled", func(t *testing.T) {
+		tries := 0
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			if serviceInfo.PendingTimeout == 0 {
+				t.Error("retried request should keep its pending timeout")
+			}
+			if tries++; tries < 2 {
+				return ResponseService{Message: SERVICE_NOT_FOUND, ServiceName: serviceInfo.ServiceName}
+			}
+			return ResponseService{Message: ERROR_NONE, ServiceName: serviceInfo.ServiceName, RemoteTargetInfo: TargetInfo{Target: "endpoint1"}}
+		}
+
+		resp := enqueuePendingRequest(runner, serviceInfo, nil, "no device")
+		if resp.Message != PENDING || len(resp.PendingRequestID) == 0 {
+			t.Fatal("unexpected response : ", resp)
+		}
+
+		request := waitPending(resp.PendingRequestID, PendingStatusScheduled)
+		if request.Response.RemoteTargetInfo.Target != "endpoint1" {
+			t.Error("unexpected response : ", request.Response)
+		}
+	})
+	t.Run("Expired", func(t *testing.T) {
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			return ResponseService{Message: SERVICE_NOT_FOUND, ServiceName: serviceInfo.ServiceName}
+		}
+
+		notified := make(chan string, 1)
+		request := serviceInfo
+		request.PendingTimeout = time.Millisecond
+		resp := enqueuePendingRequest(runner, request, func(status string) { notified <- status }, "no device")
+
+		// NOTE : the request is expired on its deadline before it is retried
+		expired := waitPending(resp.PendingRequestID, PendingStatusExpired)
+		if expired.Reason != "no device" {
+			t.Error("unexpected reason : ", expired.Reason)
+		}
+
+		select {
+		case status := <-notified:
+			if status != PendingStatusExpired {
+				t.Error("unexpected status : ", status)
+			}
+		case <-time.After(time.Second):
+			t.Error("expired request is not notified")
+		}
+
+		found := false
+		for _, pending := range oche.GetPendingRequests() {
+			found = found || pending.RequestID == resp.PendingRequestID
+		}
+		if !found {
+			t.Error("finished request is not listed")
+		}
+	})
+	t.Run("ScheduledOnDeadline", func(t *testing.T) {
+		request := serviceInfo
+		request.PendingTimeout = 5 * pendingRetryInterval
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			// NOTE : the deadline is passed while the request is retried
+			time.Sleep(2 * request.PendingTimeout)
+			return ResponseService{Message: ERROR_NONE, ServiceName: serviceInfo.ServiceName}
+		}
+
+		notified := make(chan string, 1)
+		resp := enqueuePendingRequest(runner, request, func(status string) { notified <- status }, "no device")
+
+		waitPending(resp.PendingRequestID, PendingStatusScheduled)
+		select {
+		case status := <-notified:
+			t.Error("scheduled request is notified : ", status)
+		case <-time.After(10 * pendingRetryInterval):
+		}
+	})
+	t.Run("Saturated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		createMockIns(ctrl)
+
+		candidates := []dbhelper.ExecutionCandidate{{Id: "ID1", ExecType: "native", Endpoint: []string{"endpoint1"}}}
+
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq("MyApp"), gomock.Any(), gomock.Any()).Return(candidates, nil).MinTimes(2)
+		mockSystemDBExecutor.EXPECT().Get("id").Return(sysDB.SystemInfo{Name: "ID", Value: "ID"}, nil).MinTimes(2)
+		mockNetwork.EXPECT().GetOutboundIP().Return("", nil).MinTimes(2)
+		mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Eq("endpoint1")).Return(float64(0.0), errors.New("device is saturated")).MinTimes(2)
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		request := serviceInfo
+		request.ServiceInfo = []RequestServiceInfo{{ExecutionType: "native", ExeCmd: []string{"MyApp"}}}
+		request.PendingTimeout = 100 * time.Millisecond
+		resp := oche.RequestService(request)
+		if resp.Message != PENDING {
+			t.Fatal("unexpected response : ", resp)
+		}
+
+		// NOTE : the request should not be executed on the saturated device by retries
+		expired := waitPending(resp.PendingRequestID, PendingStatusExpired)
+		if expired.Reason != "no device can run the service" {
+			t.Error("unexpected reason : ", expired.Reason)
+		}
+	})
+	t.Run("Stopped", func(t *testing.T) {
+		var tries int32
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			atomic.AddInt32(&tries, 1)
+			return ResponseService{Message: SERVICE_NOT_FOUND, ServiceName: serviceInfo.ServiceName}
+		}
+
+		notified := make(chan string, 1)
+		resp := enqueuePendingRequest(runner, serviceInfo, func(status string) { notified <- status }, "no device")
+		for i := 0; i < 100 && atomic.LoadInt32(&tries) == 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		stopPendingQueue()
+		stopped := atomic.LoadInt32(&tries)
+
+		expired := waitPending(resp.PendingRequestID, PendingStatusExpired)
+		if expired.Reason != pendingReasonStopped {
+			t.Error("unexpected reason : ", expired.Reason)
+		}
+
+		select {
+		case status := <-notified:
+			if status != PendingStatusExpired {
+				t.Error("unexpected status : ", status)
+			}
+		case <-time.After(time.Second):
+			t.Error("stopped request is not notified")
+		}
+
+		time.Sleep(10 * pendingRetryInterval)
+		if retried := atomic.LoadInt32(&tries); retried > stopped+1 {
+			t.Error("pending request is retried after the queue is stopped : ", retried-stopped)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		if _, err := oche.GetPendingRequest("unknown"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,266 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	commonErrors "common/errors"
+	"common/eventbus"
+	"controller/servicemgr"
+)
+
+// ServiceGroupTopic is the eventbus topic of ServiceGroupEvent
+const ServiceGroupTopic = "orchestrationapi/group"
+
+const maxFinishedServiceGroups = 64
+
+// Spread policies placing the replicas of a service request
+const (
+	// SpreadDistinct places every replica on a different device (default)
+	SpreadDistinct = "distinct"
+	// SpreadWrap places replicas on the ranked devices in turn when there are fewer devices than replicas
+	SpreadWrap = "wrap"
+)
+
+// ReplicaPending is the status of a replica which is not executed yet
+const ReplicaPending = "Pending"
+
+// ReplicaStatus is the status of a replica in the service group
+type ReplicaStatus struct {
+	DeviceID string
+	Target   string
+	Status   string
+}
+
+// ServiceGroupEvent is the status of a service group, it is published once every replica of the group is done
+type ServiceGroupEvent struct {
+	GroupID     string
+	ServiceName string
+	Status      string
+	Done        bool
+	Replicas    []ReplicaStatus
+}
+
+type serviceGroup struct {
+	id          string
+	serviceName string
+
+	// done is called with the aggregated status after the last replica
+	done func(status string)
+
+	mtx      sync.Mutex
+	replicas []ReplicaStatus
+	reported int
+}
+
+var (
+	serviceGroupID int32
+
+	groupMtx       sync.Mutex
+	serviceGroups  = make(map[string]*serviceGroup)
+	groupsFinished = make([]string, 0)
+)
+
+// GetServiceGroup returns the status of every replica of the service group including recently finished groups
+func (orcheEngine *orcheImpl) GetServiceGroup(groupID string) (ServiceGroupEvent, error) {
+	groupMtx.Lock()
+	group, exist := serviceGroups[groupID]
+	groupMtx.Unlock()
+
+	if !exist {
+		return ServiceGroupEvent{GroupID: groupID}, commonErrors.NotFound{Message: "service group " + groupID + " is not found"}
+	}
+
+	group.mtx.Lock()
+	defer group.mtx.Unlock()
+
+	return group.status(), nil
+}
+
+// SubscribeServiceGroup gives the stream of finished service groups until cancel is called
+func (orcheEngine *orcheImpl) SubscribeServiceGroup() (<-chan ServiceGroupEvent, func()) {
+	bus := eventbus.GetInstance()
+	sub := bus.Subscribe(ServiceGroupTopic)
+	events := make(chan ServiceGroupEvent, cap(sub.C))
+
+	go func() {
+		defer close(events)
+		for data := range sub.C {
+			event, ok := data.(ServiceGroupEvent)
+			if !ok {
+				continue
+			}
+			select {
+			case events <- event:
+			default:
+				log.Println("[SubscribeServiceGroup]", "subscriber is not receiving", event.GroupID)
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			bus.Unsubscribe(sub)
+		})
+	}
+
+	return events, cancel
+}
+
+// finishServiceGroup keeps the finished group for status requests until newer groups push it out
+func finishServiceGroup(id string) {
+	groupMtx.Lock()
+	defer groupMtx.Unlock()
+
+	groupsFinished = append(groupsFinished, id)
+	if len(groupsFinished) > maxFinishedServiceGroups {
+		delete(serviceGroups, groupsFinished[0])
+		groupsFinished = groupsFinished[1:]
+	}
+}
+
+// newServiceGroupID gives the ID of a new group, it is not given again while the orchestration runs
+func newServiceGroupID(serviceName string) string {
+	return serviceName + "-" + strconv.Itoa(int(atomic.AddInt32(&serviceGroupID, 1)))
+}
+
+func newServiceGroup(id string, serviceName string, devices []deviceScore) *serviceGroup {
+	group := &serviceGroup{
+		id:          id,
+		serviceName: serviceName,
+		replicas:    make([]ReplicaStatus, len(devices)),
+	}
+	for idx, device := range devices {
+		group.replicas[idx] = ReplicaStatus{
+			DeviceID: device.id,
+			Target:   device.endpoint,
+			Status:   ReplicaPending,
+		}
+	}
+
+	groupMtx.Lock()
+	serviceGroups[id] = group
+	groupMtx.Unlock()
+
+	return group
+}
+
+// start marks the replica Started after its execution is requested
+func (group *serviceGroup) start(replica int) {
+	group.mtx.Lock()
+	defer group.mtx.Unlock()
+
+	if replica < 0 || replica >= len(group.replicas) || group.replicas[replica].Status != ReplicaPending {
+		return
+	}
+	group.replicas[replica].Status = servicemgr.ConstServiceStatusStarted
+}
+
+// report updates the status of a replica and publishes the group notification after the last replica,
+// done is called without mtx to let it read the group
+func (group *serviceGroup) report(replica int, status string) {
+	group.mtx.Lock()
+	if replica < 0 || replica >= len(group.replicas) {
+		group.mtx.Unlock()
+		return
+	}
+
+	group.replicas[replica].Status = status
+	group.reported++
+	event := group.status()
+	group.mtx.Unlock()
+
+	if !event.Done {
+		return
+	}
+
+	finishServiceGroup(group.id)
+
+	log.Printf("[orchestrationapi] service group is done [groupID:%s][status:%s]\n", event.GroupID, event.Status)
+	eventbus.GetInstance().Publish(ServiceGroupTopic, event)
+	if group.done != nil {
+		group.done(event.Status)
+	}
+}
+
+// status returns the snapshot of the group, the caller should hold mtx
+func (group *serviceGroup) status() ServiceGroupEvent {
+	return ServiceGroupEvent{
+		GroupID:     group.id,
+		ServiceName: group.serviceName,
+		Status:      aggregateStatus(group.replicas),
+		Done:        group.reported == len(group.replicas),
+		Replicas:    append([]ReplicaStatus{}, group.replicas...),
+	}
+}
+
+// aggregateStatus gives Finished if every replica is finished, Failed if any replica is failed
+// and the status of the first unfinished replica otherwise
+func aggregateStatus(replicas []ReplicaStatus) string {
+	status := servicemgr.ConstServiceStatusFinished
+	for _, replica := range replicas {
+		switch replica.Status {
+		case servicemgr.ConstServiceStatusFinished:
+		case servicemgr.ConstServiceStatusFailed:
+			return servicemgr.ConstServiceStatusFailed
+		default:
+			if status == servicemgr.ConstServiceStatusFinished {
+				status = replica.Status
+			}
+		}
+	}
+	return status
+}
+
+func isValidSpreadPolicy(policy string) bool {
+	switch policy {
+	case "", SpreadDistinct, SpreadWrap:
+		return true
+	}
+	return false
+}
+
+// selectReplicas chooses the devices of replicas from the scheduled devices whose score is given
+func selectReplicas(deviceScores []deviceScore, replicas int, policy string) ([]deviceScore, error) {
+	available := make([]deviceScore, 0, len(deviceScores))
+	for _, device := range deviceScores {
+		if device.err == nil {
+			available = append(available, device)
+		}
+	}
+
+	if len(available) == 0 {
+		return nil, errors.New("no device can run the service")
+	} else if replicas <= 1 {
+		return available[:1], nil
+	} else if policy != SpreadWrap && len(available) < replicas {
+		return nil, errors.New("not enough devices for replicas")
+	}
+
+	selected := make([]deviceScore, replicas)
+	for idx := range selected {
+		selected[idx] = available[idx%len(available)]
+	}
+	return selected, nil
+}
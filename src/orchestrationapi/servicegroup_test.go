@@ -0,0 +1,200 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"common/eventbus"
+	"controller/servicemgr"
+)
+
+func TestSelectReplicas(t *testing.T) {
+	deviceScores := []deviceScore{
+		{id: "ID1", endpoint: "endpoint1", score: 3.0},
+		{id: "ID2", endpoint: "endpoint2", score: 0.0, err: errors.New("unreachable")},
+		{id: "ID3", endpoint: "endpoint3", score: 2.0},
+	}
+
+	t.Run("Single", func(t *testing.T) {
+		selected, err := selectReplicas(deviceScores, 0, "")
+		if err != nil || len(selected) != 1 || selected[0].id != "ID1" {
+			t.Error("unexpected replicas : ", selected, err)
+		}
+
+		// @Note : the device whose score is failed is not selected even for a single replica
+		selected, err = selectReplicas(deviceScores[1:], 1, "")
+		if err != nil || len(selected) != 1 || selected[0].id != "ID3" {
+			t.Error("unexpected replicas : ", selected, err)
+		}
+		if _, err := selectReplicas(deviceScores[1:2], 1, ""); err == nil {
+			t.Error("expected error for no available device")
+		}
+	})
+	t.Run("Distinct", func(t *testing.T) {
+		selected, err := selectReplicas(deviceScores, 2, SpreadDistinct)
+		if err != nil || len(selected) != 2 || selected[0].id != "ID1" || selected[1].id != "ID3" {
+			t.Error("unexpected replicas : ", selected, err)
+		}
+
+		if _, err := selectReplicas(deviceScores, 3, ""); err == nil {
+			t.Error("expected error for not enough devices")
+		}
+	})
+	t.Run("Wrap", func(t *testing.T) {
+		selected, err := selectReplicas(deviceScores, 3, SpreadWrap)
+		if err != nil || len(selected) != 3 || selected[2].id != "ID1" {
+			t.Error("unexpected replicas : ", selected, err)
+		}
+	})
+}
+
+func TestAggregateStatus(t *testing.T) {
+	finished := ReplicaStatus{Status: servicemgr.ConstServiceStatusFinished}
+	failed := ReplicaStatus{Status: servicemgr.ConstServiceStatusFailed}
+	terminated := ReplicaStatus{Status: servicemgr.ConstServiceStatusTerminated}
+
+	if status := aggregateStatus([]ReplicaStatus{finished, finished}); status != servicemgr.ConstServiceStatusFinished {
+		t.Error("unexpected status : ", status)
+	}
+	if status := aggregateStatus([]ReplicaStatus{terminated, failed}); status != servicemgr.ConstServiceStatusFailed {
+		t.Error("unexpected status : ", status)
+	}
+	if status := aggregateStatus([]ReplicaStatus{finished, terminated}); status != servicemgr.ConstServiceStatusTerminated {
+		t.Error("unexpected status : ", status)
+	}
+}
+
+func TestServiceGroupReport(t *testing.T) {
+	sub := eventbus.GetInstance().Subscribe(ServiceGroupTopic)
+	defer eventbus.GetInstance().Unsubscribe(sub)
+
+	group := newServiceGroup("MyApp-1", "MyApp", []deviceScore{
+		{id: "ID1", endpoint: "endpoint1"},
+		{id: "ID2", endpoint: "endpoint2"},
+	})
+
+	status, _ := getOrcheImple().GetServiceGroup("MyApp-1")
+	if status.Replicas[0].Status != ReplicaPending || status.Replicas[1].Status != ReplicaPending {
+		t.Error("unexpected replicas before execution : ", status.Replicas)
+	}
+	group.start(0)
+	group.start(1)
+
+	group.report(1, servicemgr.ConstServiceStatusFinished)
+	select {
+	case event := <-sub.C:
+		t.Error("unexpected group event before every replica is done : ", event)
+	default:
+	}
+
+	status, err := getOrcheImple().GetServiceGroup("MyApp-1")
+	if err != nil || status.Done || status.Replicas[0].Status != servicemgr.ConstServiceStatusStarted ||
+		status.Replicas[1].Status != servicemgr.ConstServiceStatusFinished {
+		t.Error("unexpected group status : ", status, err)
+	}
+
+	group.report(0, servicemgr.ConstServiceStatusFailed)
+	select {
+	case data := <-sub.C:
+		event, ok := data.(ServiceGroupEvent)
+		if !ok || event.GroupID != "MyApp-1" || event.Status != servicemgr.ConstServiceStatusFailed || len(event.Replicas) != 2 {
+			t.Error("unexpected group event : ", data)
+		}
+	case <-time.After(time.Second):
+		t.Error("group event is not published")
+	}
+
+	status, err = getOrcheImple().GetServiceGroup("MyApp-1")
+	if err != nil || !status.Done || status.Status != servicemgr.ConstServiceStatusFailed {
+		t.Error("unexpected group status : ", status, err)
+	}
+
+	if _, err := getOrcheImple().GetServiceGroup("MyApp-0"); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestSubscribeServiceGroup(t *testing.T) {
+	events, cancel := getOrcheImple().SubscribeServiceGroup()
+
+	eventbus.GetInstance().Publish(ServiceGroupTopic, ServiceGroupEvent{GroupID: "MyApp-2", Done: true})
+	select {
+	case event := <-events:
+		if event.GroupID != "MyApp-2" {
+			t.Error("unexpected group event : ", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("group event is not delivered")
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("events is not closed")
+	}
+}
+
+func TestFinishServiceGroup(t *testing.T) {
+	for idx := 0; idx <= maxFinishedServiceGroups; idx++ {
+		group := newServiceGroup("Finished-"+strconv.Itoa(idx), "Finished", []deviceScore{{id: "ID1"}})
+		group.report(0, servicemgr.ConstServiceStatusFinished)
+	}
+
+	if _, err := getOrcheImple().GetServiceGroup("Finished-0"); err == nil {
+		t.Error("the oldest finished group is kept")
+	}
+	if _, err := getOrcheImple().GetServiceGroup("Finished-" + strconv.Itoa(maxFinishedServiceGroups)); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestNewServiceGroupID(t *testing.T) {
+	ids := make(map[string]bool)
+	for idx := 0; idx < 2048; idx++ {
+		id := newServiceGroupID("MyApp")
+		if ids[id] {
+			t.Fatal("group ID is given again : ", id)
+		}
+		ids[id] = true
+	}
+}
+
+func TestServiceGroupDone(t *testing.T) {
+	group := newServiceGroup(newServiceGroupID("MyApp"), "MyApp", []deviceScore{{id: "ID1"}})
+
+	statuses := make(chan ServiceGroupEvent, 1)
+	group.done = func(string) {
+		// @Note : done reads the group like the requester of the group does
+		status, _ := getOrcheImple().GetServiceGroup(group.id)
+		statuses <- status
+	}
+
+	go group.report(0, servicemgr.ConstServiceStatusFinished)
+
+	select {
+	case status := <-statuses:
+		if !status.Done || status.Status != servicemgr.ConstServiceStatusFinished {
+			t.Error("unexpected group status : ", status)
+		}
+	case <-time.After(time.Second):
+		t.Error("done of the group is blocked")
+	}
+}
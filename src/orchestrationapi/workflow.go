@@ -0,0 +1,442 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"common/errors"
+	"controller/servicemgr"
+	"controller/servicemgr/servicelog"
+)
+
+// Statuses of a workflow step which is not executed
+const (
+	WorkflowStepPending = "Pending"
+	WorkflowStepSkipped = "Skipped"
+)
+
+const maxFinishedWorkflows = 64
+
+// WorkflowStep is a service request which runs after every step in DependsOn is finished.
+// "{{<step>.Target}}" and "{{<step>.ExecutionType}}" in ExeCmd, and in Command and Env of ContainerSpec
+// are replaced with the placement of the step, and "{{<step>.Output}}" with the standard output of the step.
+// @Note : the output of a replicated step is the output of its first replica,
+// and the output is empty if the step is pending or if its output is not kept any more.
+type WorkflowStep struct {
+	Name      string
+	DependsOn []string
+	Service   ReqeustService
+}
+
+// WorkflowRequest describes the steps of a workflow and their dependencies
+type WorkflowRequest struct {
+	WorkflowName string
+	Steps        []WorkflowStep
+}
+
+// StepStatus is the status of a workflow step
+type StepStatus struct {
+	Name             string
+	Status           string
+	Message          string
+	RemoteTargetInfo TargetInfo
+	Output           string
+}
+
+// WorkflowStatus is the overall status of a workflow with the status of every step
+type WorkflowStatus struct {
+	Message      string
+	WorkflowID   string
+	WorkflowName string
+	Status       string
+	Steps        []StepStatus
+}
+
+type serviceRunner func(serviceInfo ReqeustService, done func(status string)) ResponseService
+
+type outputReader func(serviceID uint64) (string, error)
+
+type workflow struct {
+	id      string
+	request WorkflowRequest
+
+	mtx    sync.Mutex
+	status string
+	steps  []StepStatus
+}
+
+type stepResult struct {
+	step   int
+	status string
+	msg    string
+}
+
+var (
+	workflowID int32
+
+	workflowMtx      sync.Mutex
+	workflows        = make(map[string]*workflow)
+	finishedWorkflow = make([]string, 0)
+)
+
+// RequestWorkflow validates the workflow and executes its steps in the order of dependencies
+func (orcheEngine *orcheImpl) RequestWorkflow(request WorkflowRequest) WorkflowStatus {
+	log.Printf("[RequestWorkflow] %v\n", request.WorkflowName)
+	if orcheEngine.beginRequest() == false {
+		return WorkflowStatus{Message: INTERNAL_SERVER_ERROR, WorkflowName: request.WorkflowName}
+	}
+	defer orcheEngine.endRequest()
+
+	if err := validateWorkflow(request); err != nil {
+		log.Println("[orchestrationapi] ", err.Error())
+		return WorkflowStatus{Message: INVALID_PARAMETER, WorkflowName: request.WorkflowName}
+	}
+
+	wf := newWorkflow(request)
+
+	workflowMtx.Lock()
+	workflows[wf.id] = wf
+	workflowMtx.Unlock()
+
+	go wf.run(orcheEngine.requestService, orcheEngine.readOutput)
+
+	return wf.getStatus()
+}
+
+// GetWorkflowStatus returns the status of the workflow
+func (orcheEngine *orcheImpl) GetWorkflowStatus(workflowID string) (WorkflowStatus, error) {
+	workflowMtx.Lock()
+	wf, exist := workflows[workflowID]
+	workflowMtx.Unlock()
+
+	if !exist {
+		return WorkflowStatus{Message: SERVICE_NOT_FOUND, WorkflowID: workflowID}, errors.NotFound{Message: "workflow " + workflowID + " is not found"}
+	}
+	return wf.getStatus(), nil
+}
+
+// readOutput returns the standard output kept for the service
+func (orcheEngine *orcheImpl) readOutput(serviceID uint64) (string, error) {
+	chunk, err := orcheEngine.serviceIns.GetServiceLog(serviceID, 0, 0)
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0, len(chunk.Lines))
+	for _, line := range chunk.Lines {
+		if line.Stream == servicelog.StreamStdout {
+			lines = append(lines, line.Text)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func newWorkflow(request WorkflowRequest) *workflow {
+	wf := &workflow{
+		id:      request.WorkflowName + "-" + strconv.Itoa(int(atomic.AddInt32(&workflowID, 1))),
+		request: request,
+		status:  servicemgr.ConstServiceStatusStarted,
+		steps:   make([]StepStatus, len(request.Steps)),
+	}
+	for idx, step := range request.Steps {
+		wf.steps[idx] = StepStatus{Name: step.Name, Status: WorkflowStepPending}
+	}
+	return wf
+}
+
+func (wf *workflow) getStatus() WorkflowStatus {
+	wf.mtx.Lock()
+	defer wf.mtx.Unlock()
+
+	return WorkflowStatus{
+		Message:      ERROR_NONE,
+		WorkflowID:   wf.id,
+		WorkflowName: wf.request.WorkflowName,
+		Status:       wf.status,
+		Steps:        append([]StepStatus{}, wf.steps...),
+	}
+}
+
+// run starts every step whose dependencies are finished until no step can be started,
+// the output of a finished step is read before its dependents are started
+func (wf *workflow) run(runner serviceRunner, output outputReader) {
+	results := make(chan stepResult)
+	running := 0
+
+	for {
+		for _, idx := range wf.nextSteps() {
+			running++
+			go wf.startStep(runner, idx, results)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		if result.status == servicemgr.ConstServiceStatusFinished {
+			wf.readStepOutput(result.step, output)
+		}
+		wf.setStepStatus(result.step, result.status, result.msg)
+		log.Printf("[orchestrationapi] workflow step is done [workflowID:%s][step:%s][status:%s]\n", wf.id, wf.request.Steps[result.step].Name, result.status)
+	}
+
+	wf.finish()
+}
+
+// nextSteps marks pending steps as Started if their dependencies are finished
+// and as Skipped if any of their dependencies is not finished successfully
+func (wf *workflow) nextSteps() []int {
+	wf.mtx.Lock()
+	defer wf.mtx.Unlock()
+
+	ready := make([]int, 0)
+	for changed := true; changed; {
+		changed = false
+		for idx, step := range wf.request.Steps {
+			if wf.steps[idx].Status != WorkflowStepPending {
+				continue
+			}
+
+			finished, skipped := true, false
+			for _, dep := range step.DependsOn {
+				switch wf.steps[wf.stepIndex(dep)].Status {
+				case servicemgr.ConstServiceStatusFinished:
+				case WorkflowStepPending, servicemgr.ConstServiceStatusStarted:
+					finished = false
+				default:
+					skipped = true
+				}
+			}
+
+			if skipped {
+				wf.steps[idx].Status = WorkflowStepSkipped
+				wf.steps[idx].Message = "dependency is not finished"
+				changed = true
+			} else if finished {
+				wf.steps[idx].Status = servicemgr.ConstServiceStatusStarted
+				ready = append(ready, idx)
+			}
+		}
+	}
+	return ready
+}
+
+func (wf *workflow) startStep(runner serviceRunner, idx int, results chan<- stepResult) {
+	serviceInfo := wf.resolveStep(idx)
+
+	// placed makes the result wait for the placement of the step which its dependents refer to
+	placed := make(chan struct{})
+	var once sync.Once
+	done := func(status string) {
+		go func() {
+			<-placed
+			once.Do(func() {
+				results <- stepResult{step: idx, status: status}
+			})
+		}()
+	}
+
+	resp := runner(serviceInfo, done)
+	if resp.Message == PENDING {
+		// done is called after the pending request is scheduled and finished, or expired
+		close(placed)
+		return
+	} else if resp.Message != ERROR_NONE {
+		close(placed)
+		once.Do(func() {
+			results <- stepResult{step: idx, status: servicemgr.ConstServiceStatusFailed, msg: resp.Message}
+		})
+		return
+	}
+
+	wf.mtx.Lock()
+	wf.steps[idx].RemoteTargetInfo = resp.RemoteTargetInfo
+	wf.mtx.Unlock()
+	close(placed)
+}
+
+// resolveStep replaces the placeholders of dependencies in the commands of the step,
+// every other field of the request is kept
+func (wf *workflow) resolveStep(idx int) ReqeustService {
+	wf.mtx.Lock()
+	defer wf.mtx.Unlock()
+
+	step := wf.request.Steps[idx]
+	pairs := make([]string, 0)
+	for _, dep := range step.DependsOn {
+		depStatus := wf.steps[wf.stepIndex(dep)]
+		pairs = append(pairs,
+			"{{"+dep+".Target}}", depStatus.RemoteTargetInfo.Target,
+			"{{"+dep+".ExecutionType}}", depStatus.RemoteTargetInfo.ExecutionType,
+			"{{"+dep+".Output}}", depStatus.Output)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	serviceInfo := step.Service
+	serviceInfo.ServiceInfo = make([]RequestServiceInfo, len(step.Service.ServiceInfo))
+	for i, info := range step.Service.ServiceInfo {
+		info.ExeCmd = replaceAll(replacer, info.ExeCmd)
+		if info.ContainerSpec != nil {
+			// NOTE : the spec of the request is not changed, it is shared by every run of the step
+			spec := *info.ContainerSpec
+			spec.Command = replaceAll(replacer, spec.Command)
+			if spec.Env != nil {
+				spec.Env = make(map[string]string, len(info.ContainerSpec.Env))
+				for key, value := range info.ContainerSpec.Env {
+					spec.Env[key] = replacer.Replace(value)
+				}
+			}
+			info.ContainerSpec = &spec
+		}
+		serviceInfo.ServiceInfo[i] = info
+	}
+	return serviceInfo
+}
+
+func replaceAll(replacer *strings.Replacer, strs []string) []string {
+	if strs == nil {
+		return nil
+	}
+
+	replaced := make([]string, len(strs))
+	for i, str := range strs {
+		replaced[i] = replacer.Replace(str)
+	}
+	return replaced
+}
+
+// readStepOutput keeps the output of the step for its dependents
+func (wf *workflow) readStepOutput(idx int, output outputReader) {
+	wf.mtx.Lock()
+	serviceID := wf.steps[idx].RemoteTargetInfo.ServiceID
+	wf.mtx.Unlock()
+
+	// NOTE : a step which is pending when it is requested has no service ID
+	if serviceID == 0 {
+		return
+	}
+
+	out, err := output(serviceID)
+	if err != nil {
+		log.Printf("[orchestrationapi] cannot read output of workflow step [workflowID:%s][step:%s] : %s\n", wf.id, wf.request.Steps[idx].Name, err.Error())
+		return
+	}
+
+	wf.mtx.Lock()
+	wf.steps[idx].Output = out
+	wf.mtx.Unlock()
+}
+
+func (wf *workflow) setStepStatus(idx int, status string, msg string) {
+	wf.mtx.Lock()
+	defer wf.mtx.Unlock()
+
+	wf.steps[idx].Status = status
+	wf.steps[idx].Message = msg
+}
+
+// finish decides the overall status and forgets the oldest finished workflows
+func (wf *workflow) finish() {
+	wf.mtx.Lock()
+	wf.status = servicemgr.ConstServiceStatusFinished
+	for _, step := range wf.steps {
+		if step.Status != servicemgr.ConstServiceStatusFinished {
+			wf.status = servicemgr.ConstServiceStatusFailed
+			break
+		}
+	}
+	log.Printf("[orchestrationapi] workflow is done [workflowID:%s][status:%s]\n", wf.id, wf.status)
+	wf.mtx.Unlock()
+
+	workflowMtx.Lock()
+	defer workflowMtx.Unlock()
+
+	finishedWorkflow = append(finishedWorkflow, wf.id)
+	if len(finishedWorkflow) > maxFinishedWorkflows {
+		delete(workflows, finishedWorkflow[0])
+		finishedWorkflow = finishedWorkflow[1:]
+	}
+}
+
+func (wf *workflow) stepIndex(name string) int {
+	for idx, step := range wf.request.Steps {
+		if step.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// validateWorkflow checks that step names are unique, dependencies are known and there is no cycle
+func validateWorkflow(request WorkflowRequest) error {
+	if len(request.Steps) == 0 {
+		return errors.InvalidParam{Message: "workflow has no step"}
+	}
+
+	indegree := make(map[string]int)
+	for _, step := range request.Steps {
+		if len(step.Name) == 0 {
+			return errors.InvalidParam{Message: "workflow step has no name"}
+		}
+		if _, exist := indegree[step.Name]; exist {
+			return errors.InvalidParam{Message: "duplicated workflow step " + step.Name}
+		}
+		indegree[step.Name] = len(step.DependsOn)
+	}
+
+	dependents := make(map[string][]string)
+	for _, step := range request.Steps {
+		for _, dep := range step.DependsOn {
+			if _, exist := indegree[dep]; !exist {
+				return errors.InvalidParam{Message: "unknown dependency " + dep + " of workflow step " + step.Name}
+			}
+			dependents[dep] = append(dependents[dep], step.Name)
+		}
+	}
+
+	queue := make([]string, 0)
+	for name, count := range indegree {
+		if count == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	visited := 0
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, dependent := range dependents[name] {
+			if indegree[dependent]--; indegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if visited != len(request.Steps) {
+		return errors.InvalidParam{Message: "workflow has a dependency cycle"}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+/*******************************************************************************
+ * Copyright 2019 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package orchestrationapi
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"controller/servicemgr"
+	"controller/servicemgr/executor/containerspec"
+	"controller/servicemgr/servicelog"
+	sysDB "db/bolt/system"
+	dbhelper "db/helper"
+)
+
+func getWorkflowRequest() WorkflowRequest {
+	return WorkflowRequest{
+		WorkflowName: "pipeline",
+		Steps: []WorkflowStep{
+			{
+				Name:    "capture",
+				Service: ReqeustService{ServiceName: "capture", ServiceInfo: []RequestServiceInfo{{ExecutionType: "native", ExeCmd: []string{"capture"}}}},
+			},
+			{
+				Name:      "preprocess",
+				DependsOn: []string{"capture"},
+				Service:   ReqeustService{ServiceName: "preprocess", ServiceInfo: []RequestServiceInfo{{ExecutionType: "native", ExeCmd: []string{"preprocess", "{{capture.Target}}"}}}},
+			},
+			{
+				Name:      "analyze",
+				DependsOn: []string{"preprocess"},
+				Service:   ReqeustService{ServiceName: "analyze", ServiceInfo: []RequestServiceInfo{{ExecutionType: "native", ExeCmd: []string{"analyze"}}}},
+			},
+		},
+	}
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		if err := validateWorkflow(getWorkflowRequest()); err != nil {
+			t.Error("unexpected error : ", err.Error())
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		t.Run("NoStep", func(t *testing.T) {
+			if err := validateWorkflow(WorkflowRequest{WorkflowName: "empty"}); err == nil {
+				t.Error("expected error")
+			}
+		})
+		t.Run("DuplicatedStep", func(t *testing.T) {
+			request := getWorkflowRequest()
+			request.Steps[2].Name = "capture"
+			if err := validateWorkflow(request); err == nil {
+				t.Error("expected error")
+			}
+		})
+		t.Run("UnknownDependency", func(t *testing.T) {
+			request := getWorkflowRequest()
+			request.Steps[1].DependsOn = []string{"unknown"}
+			if err := validateWorkflow(request); err == nil {
+				t.Error("expected error")
+			}
+		})
+		t.Run("Cycle", func(t *testing.T) {
+			request := getWorkflowRequest()
+			request.Steps[0].DependsOn = []string{"analyze"}
+			if err := validateWorkflow(request); err == nil {
+				t.Error("expected error")
+			}
+		})
+	})
+}
+
+func TestWorkflowRun(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var mtx sync.Mutex
+		requested := make(map[string]ReqeustService)
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			mtx.Lock()
+			requested[serviceInfo.ServiceName] = serviceInfo
+			mtx.Unlock()
+
+			done(servicemgr.ConstServiceStatusFinished)
+			return ResponseService{
+				Message:          ERROR_NONE,
+				ServiceName:      serviceInfo.ServiceName,
+				RemoteTargetInfo: TargetInfo{ExecutionType: "native", Target: serviceInfo.ServiceName + "-endpoint"},
+			}
+		}
+
+		wf := newWorkflow(getWorkflowRequest())
+		wf.run(runner, nil)
+
+		status := wf.getStatus()
+		if status.Status != servicemgr.ConstServiceStatusFinished {
+			t.Error("unexpected status : ", status)
+		}
+		if cmd := requested["preprocess"].ServiceInfo[0].ExeCmd[1]; cmd != "capture-endpoint" {
+			t.Error("unexpected resolved command : ", cmd)
+		}
+	})
+	t.Run("ContainerSpec", func(t *testing.T) {
+		var mtx sync.Mutex
+		requested := make(map[string]ReqeustService)
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			mtx.Lock()
+			requested[serviceInfo.ServiceName] = serviceInfo
+			mtx.Unlock()
+
+			done(servicemgr.ConstServiceStatusFinished)
+			return ResponseService{
+				Message:          ERROR_NONE,
+				ServiceName:      serviceInfo.ServiceName,
+				RemoteTargetInfo: TargetInfo{ExecutionType: "container", Target: serviceInfo.ServiceName + "-endpoint"},
+			}
+		}
+
+		spec := &containerspec.Spec{
+			Image:   "analyze:1.0",
+			Command: []string{"analyze", "--source", "{{preprocess.Target}}"},
+			Env:     map[string]string{"SOURCE": "{{preprocess.Target}}"},
+			Ports:   []containerspec.Port{{ContainerPort: 8080}},
+		}
+		request := getWorkflowRequest()
+		request.Steps[2].Service.ServiceInfo = []RequestServiceInfo{{ExecutionType: "container", ContainerSpec: spec}}
+
+		wf := newWorkflow(request)
+		wf.run(runner, nil)
+
+		if status := wf.getStatus(); status.Status != servicemgr.ConstServiceStatusFinished {
+			t.Fatal("unexpected status : ", status)
+		}
+
+		resolved := requested["analyze"].ServiceInfo[0].ContainerSpec
+		if resolved == nil {
+			t.Fatal("container spec is dropped")
+		}
+		if resolved.Image != spec.Image || reflect.DeepEqual(resolved.Ports, spec.Ports) != true {
+			t.Error("unexpected container spec : ", resolved)
+		}
+		if resolved.Command[2] != "preprocess-endpoint" || resolved.Env["SOURCE"] != "preprocess-endpoint" {
+			t.Error("unexpected resolved container spec : ", resolved)
+		}
+		if spec.Command[2] != "{{preprocess.Target}}" || spec.Env["SOURCE"] != "{{preprocess.Target}}" {
+			t.Error("container spec of the request is changed : ", spec)
+		}
+	})
+	t.Run("Output", func(t *testing.T) {
+		var mtx sync.Mutex
+		requested := make(map[string]ReqeustService)
+		serviceIDs := map[string]uint64{"capture": 1, "preprocess": 2, "analyze": 3}
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			mtx.Lock()
+			requested[serviceInfo.ServiceName] = serviceInfo
+			mtx.Unlock()
+
+			done(servicemgr.ConstServiceStatusFinished)
+			return ResponseService{
+				Message:          ERROR_NONE,
+				ServiceName:      serviceInfo.ServiceName,
+				RemoteTargetInfo: TargetInfo{ExecutionType: "native", Target: "endpoint", ServiceID: serviceIDs[serviceInfo.ServiceName]},
+			}
+		}
+		output := func(serviceID uint64) (string, error) {
+			if serviceID == serviceIDs["preprocess"] {
+				return "", errors.New("not found")
+			}
+			return "frames-" + strconv.FormatUint(serviceID, 10), nil
+		}
+
+		request := getWorkflowRequest()
+		request.Steps[1].Service.ServiceInfo[0].ExeCmd = []string{"preprocess", "{{capture.Output}}"}
+		request.Steps[2].Service.ServiceInfo[0].ExeCmd = []string{"analyze", "{{preprocess.Output}}"}
+
+		wf := newWorkflow(request)
+		wf.run(runner, output)
+
+		status := wf.getStatus()
+		if status.Status != servicemgr.ConstServiceStatusFinished {
+			t.Fatal("unexpected status : ", status)
+		}
+		if status.Steps[0].Output != "frames-1" || status.Steps[1].Output != "" {
+			t.Error("unexpected step output : ", status.Steps)
+		}
+		if cmd := requested["preprocess"].ServiceInfo[0].ExeCmd[1]; cmd != "frames-1" {
+			t.Error("unexpected resolved command : ", cmd)
+		}
+		if cmd := requested["analyze"].ServiceInfo[0].ExeCmd[1]; cmd != "" {
+			t.Error("unexpected resolved command : ", cmd)
+		}
+	})
+	t.Run("StepFailed", func(t *testing.T) {
+		runner := func(serviceInfo ReqeustService, done func(status string)) ResponseService {
+			if serviceInfo.ServiceName == "preprocess" {
+				return ResponseService{Message: SERVICE_NOT_FOUND, ServiceName: serviceInfo.ServiceName}
+			}
+			done(servicemgr.ConstServiceStatusFinished)
+			return ResponseService{Message: ERROR_NONE, ServiceName: serviceInfo.ServiceName}
+		}
+
+		wf := newWorkflow(getWorkflowRequest())
+		wf.run(runner, nil)
+
+		status := wf.getStatus()
+		if status.Status != servicemgr.ConstServiceStatusFailed {
+			t.Error("unexpected status : ", status)
+		}
+		expected := []string{servicemgr.ConstServiceStatusFinished, servicemgr.ConstServiceStatusFailed, WorkflowStepSkipped}
+		for idx, step := range status.Steps {
+			if step.Status != expected[idx] {
+				t.Error("unexpected step status : ", step)
+			}
+		}
+		if status.Steps[1].Message != SERVICE_NOT_FOUND {
+			t.Error("unexpected step message : ", status.Steps[1].Message)
+		}
+	})
+}
+
+func TestRequestWorkflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createMockIns(ctrl)
+
+	t.Run("NotReady", func(t *testing.T) {
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = false
+
+		if status := oche.RequestWorkflow(getWorkflowRequest()); status.Message != INTERNAL_SERVER_ERROR {
+			t.Error("unexpected message : ", status.Message)
+		}
+	})
+	t.Run("ExecuteFailed", func(t *testing.T) {
+		candidates := []dbhelper.ExecutionCandidate{{Id: "ID1", ExecType: "native", Endpoint: []string{"endpoint1"}}}
+
+		mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+		mockDBHelper.EXPECT().GetDeviceInfoWithService(gomock.Eq("capture"), gomock.Any(), gomock.Any()).Return(candidates, nil)
+		mockSystemDBExecutor.EXPECT().Get("id").Return(sysDB.SystemInfo{Name: "ID", Value: "ID"}, nil)
+		mockNetwork.EXPECT().GetOutboundIP().Return("", nil)
+		mockClient.EXPECT().DoGetScoreRemoteDevice(gomock.Any(), gomock.Eq("endpoint1")).Return(float64(1.0), nil)
+		mockService.EXPECT().Execute(gomock.Eq("endpoint1"), "capture", gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(0), errors.New("connection refused"))
+
+		getOcheIns(ctrl)
+		oche := getOrcheImple()
+		oche.Ready = true
+
+		status := oche.RequestWorkflow(getWorkflowRequest())
+		if status.Message != ERROR_NONE {
+			t.Fatal("unexpected message : ", status.Message)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for status.Status == servicemgr.ConstServiceStatusStarted && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			status, _ = oche.GetWorkflowStatus(status.WorkflowID)
+		}
+
+		if status.Status != servicemgr.ConstServiceStatusFailed {
+			t.Fatal("workflow is not failed : ", status)
+		}
+		expected := []string{servicemgr.ConstServiceStatusFailed, WorkflowStepSkipped, WorkflowStepSkipped}
+		for idx, step := range status.Steps {
+			if step.Status != expected[idx] {
+				t.Error("unexpected step status : ", step)
+			}
+		}
+		if status.Steps[0].Message != "connection refused" {
+			t.Error("unexpected step message : ", status.Steps[0].Message)
+		}
+	})
+}
+
+func TestReadOutput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createMockIns(ctrl)
+
+	mockService.EXPECT().SetLocalServiceExecutor(mockExecutor)
+	getOcheIns(ctrl)
+	oche := getOrcheImple()
+
+	t.Run("Success", func(t *testing.T) {
+		chunk := servicelog.Chunk{
+			ServiceID: 1,
+			Lines: []servicelog.Line{
+				{Seq: 0, Stream: servicelog.StreamStdout, Text: "line1"},
+				{Seq: 1, Stream: servicelog.StreamStderr, Text: "warning"},
+				{Seq: 2, Stream: servicelog.StreamStdout, Text: "line2"},
+			},
+			Next:     3,
+			Finished: true,
+		}
+		mockService.EXPECT().GetServiceLog(uint64(1), uint64(0), time.Duration(0)).Return(chunk, nil)
+
+		out, err := oche.readOutput(1)
+		if err != nil {
+			t.Fatal("unexpected error : ", err.Error())
+		}
+		if out != "line1\nline2" {
+			t.Error("unexpected output : ", out)
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		mockService.EXPECT().GetServiceLog(uint64(2), uint64(0), time.Duration(0)).Return(servicelog.Chunk{}, errors.New("not found"))
+
+		if _, err := oche.readOutput(2); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
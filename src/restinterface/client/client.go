@@ -19,6 +19,8 @@
 package client
 
 import (
+	"time"
+
 	"restinterface/cipher"
 )
 
@@ -29,9 +31,11 @@ type Clienter interface {
 	// for servicemgr
 	DoExecuteRemoteDevice(appInfo map[string]interface{}, target string) (err error)
 	DoNotifyAppStatusRemoteDevice(statusNotificationInfo map[string]interface{}, appID uint64, target string) (err error)
+	DoGetServiceLogRemoteDevice(serviceID uint64, since uint64, wait time.Duration, target string) (logs map[string]interface{}, err error)
 
 	// for scoringmgr
 	DoGetScoreRemoteDevice(devID string, endpoint string) (scoreValue float64, err error)
+	DoGetScoreDetailRemoteDevice(devID string, endpoint string) (scoreValue float64, components map[string]float64, err error)
 }
 
 // Setter interface
@@ -25,6 +25,7 @@ import (
 	reflect "reflect"
 	cipher "restinterface/cipher"
 	client "restinterface/client"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -92,6 +93,21 @@ func (mr *MockClienterMockRecorder) DoNotifyAppStatusRemoteDevice(statusNotifica
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoNotifyAppStatusRemoteDevice", reflect.TypeOf((*MockClienter)(nil).DoNotifyAppStatusRemoteDevice), statusNotificationInfo, appID, target)
 }
 
+// DoGetServiceLogRemoteDevice mocks base method
+func (m *MockClienter) DoGetServiceLogRemoteDevice(serviceID, since uint64, wait time.Duration, target string) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoGetServiceLogRemoteDevice", serviceID, since, wait, target)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoGetServiceLogRemoteDevice indicates an expected call of DoGetServiceLogRemoteDevice
+func (mr *MockClienterMockRecorder) DoGetServiceLogRemoteDevice(serviceID, since, wait, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoGetServiceLogRemoteDevice", reflect.TypeOf((*MockClienter)(nil).DoGetServiceLogRemoteDevice), serviceID, since, wait, target)
+}
+
 // DoGetScoreRemoteDevice mocks base method
 func (m *MockClienter) DoGetScoreRemoteDevice(devID, endpoint string) (float64, error) {
 	m.ctrl.T.Helper()
@@ -107,6 +123,22 @@ func (mr *MockClienterMockRecorder) DoGetScoreRemoteDevice(devID, endpoint inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoGetScoreRemoteDevice", reflect.TypeOf((*MockClienter)(nil).DoGetScoreRemoteDevice), devID, endpoint)
 }
 
+// DoGetScoreDetailRemoteDevice mocks base method
+func (m *MockClienter) DoGetScoreDetailRemoteDevice(devID, endpoint string) (float64, map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoGetScoreDetailRemoteDevice", devID, endpoint)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(map[string]float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DoGetScoreDetailRemoteDevice indicates an expected call of DoGetScoreDetailRemoteDevice
+func (mr *MockClienterMockRecorder) DoGetScoreDetailRemoteDevice(devID, endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoGetScoreDetailRemoteDevice", reflect.TypeOf((*MockClienter)(nil).DoGetScoreDetailRemoteDevice), devID, endpoint)
+}
+
 // MockSetter is a mock of Setter interface
 type MockSetter struct {
 	ctrl     *gomock.Controller
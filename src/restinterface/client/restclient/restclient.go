@@ -23,7 +23,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"common/types/servicemgrtypes"
 	"restinterface/cipher"
 	"restinterface/client"
 	"restinterface/resthelper"
@@ -83,6 +85,8 @@ func (c restClientImpl) DoExecuteRemoteDevice(appInfo map[string]interface{}, ta
 	str := respMsg["Status"].(string)
 	if str == "Failed" {
 		err = errors.New("failed")
+	} else if str == servicemgrtypes.ConstServiceStatusRejected {
+		err = errors.New("rejected : " + fmt.Sprint(respMsg["Message"]))
 	}
 
 	return
@@ -110,6 +114,29 @@ func (c restClientImpl) DoNotifyAppStatusRemoteDevice(statusNotificationInfo map
 	return nil
 }
 
+// DoGetServiceLogRemoteDevice sends request to remote orchestration (APIV1ServicemgrServicesLogsServiceIDGet) to get output of service
+func (c restClientImpl) DoGetServiceLogRemoteDevice(serviceID uint64, since uint64, wait time.Duration, target string) (logs map[string]interface{}, err error) {
+	if c.IsSetKey == false {
+		return nil, errors.New("[" + logPrefix + "] does not set key")
+	}
+
+	restapi := fmt.Sprintf("/api/v1/servicemgr/services/logs/%d?since=%d&wait=%g", serviceID, since, wait.Seconds())
+
+	targetURL := c.helper.MakeTargetURL(target, c.port, restapi)
+
+	respBytes, code, err := c.helper.DoGet(targetURL)
+	if err != nil || code != http.StatusOK {
+		return nil, errors.New("[" + logPrefix + "] get return error")
+	}
+
+	logs, err = c.Key.DecryptByteToJSON(respBytes)
+	if err != nil {
+		return nil, errors.New("[" + logPrefix + "] can not decryption " + err.Error())
+	}
+
+	return
+}
+
 // DoGetScoreRemoteDevice  sends request to remote orchestration (APIV1ScoringmgrScoreLibnameGet) to get score
 func (c restClientImpl) DoGetScoreRemoteDevice(devID string, endpoint string) (scoreValue float64, err error) {
 	if c.IsSetKey == false {
@@ -139,6 +166,10 @@ func (c restClientImpl) DoGetScoreRemoteDevice(devID string, endpoint string) (s
 
 	log.Println("[JSON] : ", respMsg)
 
+	if isSaturated(respMsg) {
+		return 0.0, errors.New("device is saturated")
+	}
+
 	scoreValue = respMsg["ScoreValue"].(float64)
 	if scoreValue == 0.0 {
 		err = errors.New("failed")
@@ -146,6 +177,69 @@ func (c restClientImpl) DoGetScoreRemoteDevice(devID string, endpoint string) (s
 	return
 }
 
+// DoGetScoreDetailRemoteDevice sends request to remote orchestration (APIV1ScoringmgrScoreDetailGet) to get score with its sub-scores
+func (c restClientImpl) DoGetScoreDetailRemoteDevice(devID string, endpoint string) (scoreValue float64, components map[string]float64, err error) {
+	if c.IsSetKey == false {
+		return scoreValue, nil, errors.New("[" + logPrefix + "] does not set key")
+	}
+
+	restapi := "/api/v1/scoringmgr/score/detail"
+
+	targetURL := c.helper.MakeTargetURL(endpoint, c.port, restapi)
+
+	info := make(map[string]interface{})
+	info["devID"] = devID
+	encryptBytes, err := c.Key.EncryptJSONToByte(info)
+	if err != nil {
+		return scoreValue, nil, errors.New("[" + logPrefix + "] can not encryption " + err.Error())
+	}
+
+	respBytes, code, err := c.helper.DoGetWithBody(targetURL, encryptBytes)
+	if err != nil || code != http.StatusOK {
+		return scoreValue, nil, errors.New("[" + logPrefix + "] get return error")
+	}
+
+	respMsg, err := c.Key.DecryptByteToJSON(respBytes)
+	if err != nil {
+		return scoreValue, nil, errors.New("[" + logPrefix + "] can not decryption " + err.Error())
+	}
+
+	log.Println("[JSON] : ", respMsg)
+
+	if scoreErr, ok := respMsg["ScoreError"].(string); ok && len(scoreErr) != 0 {
+		return scoreValue, nil, errors.New(scoreErr)
+	}
+
+	if isSaturated(respMsg) {
+		return scoreValue, nil, errors.New("device is saturated")
+	}
+
+	scoreValue, _ = respMsg["ScoreValue"].(float64)
+	if values, ok := respMsg["ScoreComponents"].(map[string]interface{}); ok {
+		components = make(map[string]float64)
+		for key, value := range values {
+			if score, ok := value.(float64); ok {
+				components[key] = score
+			}
+		}
+	}
+
+	if scoreValue == 0.0 {
+		err = errors.New("failed")
+	}
+	return
+}
+
+// isSaturated checks the capacity in the score response, devices which do not report it are not saturated
+func isSaturated(respMsg map[string]interface{}) bool {
+	capacity, ok := respMsg["Capacity"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	saturated, _ := capacity["Saturated"].(bool)
+	return saturated
+}
+
 func (c *restClientImpl) setHelper(helper resthelper.RestHelper) {
 	c.helper = helper
 }
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	ciphermock "restinterface/cipher/mocks"
 	helpermock "restinterface/resthelper/mocks"
@@ -239,6 +240,60 @@ func TestDoNotifyAppStatusRemoteDevice(t *testing.T) {
 	})
 }
 
+func TestDoGetServiceLogRemoteDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := restClient
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	t.Run("Error", func(t *testing.T) {
+		t.Run("IsNotSetKey", func(t *testing.T) {
+			client.setHelper(mockHelper)
+
+			client.IsSetKey = false
+			_, err := client.DoGetServiceLogRemoteDevice(1, 0, time.Second, "")
+			if err == nil {
+				t.Error("expect error is not nil, but nil")
+			}
+		})
+		t.Run("StatusNotFound", func(t *testing.T) {
+			client.SetCipher(mockCipher)
+			client.setHelper(mockHelper)
+			gomock.InOrder(
+				mockHelper.EXPECT().MakeTargetURL(gomock.Any(), gomock.Any(), gomock.Any()).Return(""),
+				mockHelper.EXPECT().DoGet(gomock.Any()).Return(nil, http.StatusNotFound, nil),
+			)
+
+			_, err := client.DoGetServiceLogRemoteDevice(1, 0, time.Second, "")
+			if err == nil {
+				t.Error("expect error is not nil, but nil")
+			}
+		})
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		client.SetCipher(mockCipher)
+		client.setHelper(mockHelper)
+
+		respMsg := map[string]interface{}{"ServiceID": float64(1), "Next": float64(3)}
+
+		gomock.InOrder(
+			mockHelper.EXPECT().MakeTargetURL("target", constWellknownPort, "/api/v1/servicemgr/services/logs/1?since=2&wait=1.5").Return(""),
+			mockHelper.EXPECT().DoGet(gomock.Any()).Return(nil, http.StatusOK, nil),
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(respMsg, nil),
+		)
+
+		logs, err := client.DoGetServiceLogRemoteDevice(1, 2, 1500*time.Millisecond, "target")
+		if err != nil {
+			t.Error("expect error is nil, but not nil")
+		} else if logs["Next"] != float64(3) {
+			t.Error("unexpected logs")
+		}
+	})
+}
+
 func TestDoGetScoreRemoteDevice(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -349,3 +404,67 @@ func TestDoGetScoreRemoteDevice(t *testing.T) {
 		}
 	})
 }
+
+func TestDoGetScoreDetailRemoteDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := restClient
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	t.Run("Error", func(t *testing.T) {
+		t.Run("IsNotSetKey", func(t *testing.T) {
+			client.setHelper(mockHelper)
+
+			client.IsSetKey = false
+			_, _, err := client.DoGetScoreDetailRemoteDevice("", "")
+			if err == nil {
+				t.Error("expect error is not nil, but nil")
+			}
+		})
+		t.Run("ScoreError", func(t *testing.T) {
+			client.SetCipher(mockCipher)
+			client.setHelper(mockHelper)
+
+			respMsg := make(map[string]interface{})
+			respMsg["ScoreValue"] = float64(0.0)
+			respMsg["ScoreError"] = "no rtt"
+
+			gomock.InOrder(
+				mockHelper.EXPECT().MakeTargetURL(gomock.Any(), gomock.Any(), gomock.Any()).Return(""),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
+				mockHelper.EXPECT().DoGetWithBody(gomock.Any(), gomock.Any()).Return(nil, http.StatusOK, nil),
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(respMsg, nil),
+			)
+
+			_, _, err := client.DoGetScoreDetailRemoteDevice("", "")
+			if err == nil || err.Error() != "no rtt" {
+				t.Error("unexpected error : ", err)
+			}
+		})
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		client.SetCipher(mockCipher)
+		client.setHelper(mockHelper)
+
+		respMsg := make(map[string]interface{})
+		respMsg["ScoreValue"] = float64(1.0)
+		respMsg["ScoreComponents"] = map[string]interface{}{"cpu": float64(0.5), "network": float64(0.5)}
+
+		gomock.InOrder(
+			mockHelper.EXPECT().MakeTargetURL(gomock.Any(), gomock.Any(), gomock.Any()).Return(""),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
+			mockHelper.EXPECT().DoGetWithBody(gomock.Any(), gomock.Any()).Return(nil, http.StatusOK, nil),
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(respMsg, nil),
+		)
+
+		score, components, err := client.DoGetScoreDetailRemoteDevice("", "")
+		if err != nil {
+			t.Error("expect error is nil, but not nil")
+		} else if score != float64(1.0) || components["cpu"] != float64(0.5) {
+			t.Error("unexpected score value")
+		}
+	})
+}
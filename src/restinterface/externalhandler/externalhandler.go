@@ -19,15 +19,27 @@
 package externalhandler
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"common/errors"
+	"controller/discoverymgr"
+	"controller/servicemgr"
+	"controller/servicemgr/executor/containerspec"
+	"controller/servicemgr/servicelog"
+	resourceDB "db/bolt/resource"
 	"orchestrationapi"
 	"restinterface"
 	"restinterface/cipher"
 	"restinterface/resthelper"
+
+	"github.com/gorilla/mux"
 )
 
 const logPrefix = "RestExternalInterface"
@@ -56,6 +68,76 @@ func init() {
 			Pattern:     "/api/v1/orchestration/services",
 			HandlerFunc: handler.APIV1RequestServicePost,
 		},
+
+		restinterface.Route{
+			Name:        "APIV1RequestServiceDryRunPost",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/api/v1/orchestration/services/dryrun",
+			HandlerFunc: handler.APIV1RequestServiceDryRunPost,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1PendingRequestsGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/services/pending",
+			HandlerFunc: handler.APIV1PendingRequestsGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1PendingRequestGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/services/pending/{requestid}",
+			HandlerFunc: handler.APIV1PendingRequestGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1ServiceGroupEventsGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/groups/events",
+			HandlerFunc: handler.APIV1ServiceGroupEventsGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1ServiceGroupGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/groups/{groupid}",
+			HandlerFunc: handler.APIV1ServiceGroupGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1ServiceLogsGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/services/{serviceid}/logs",
+			HandlerFunc: handler.APIV1ServiceLogsGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1ResourceHistoryGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/resources/history",
+			HandlerFunc: handler.APIV1ResourceHistoryGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1WorkflowPost",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/api/v1/orchestration/workflows",
+			HandlerFunc: handler.APIV1WorkflowPost,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1WorkflowGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/workflows/{workflowid}",
+			HandlerFunc: handler.APIV1WorkflowGet,
+		},
+
+		restinterface.Route{
+			Name:        "APIV1DeviceEventsGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/orchestration/devices/events",
+			HandlerFunc: handler.APIV1DeviceEventsGet,
+		},
 	}
 }
 
@@ -88,8 +170,8 @@ func (h *Handler) APIV1RequestServicePost(w http.ResponseWriter, r *http.Request
 		responseName string
 		resp         orchestrationapi.ResponseService
 
-		executeEnvs        []interface{}
 		responseTargetInfo map[string]interface{}
+		responseGroup      map[string]interface{}
 	)
 
 	//request
@@ -102,20 +184,732 @@ func (h *Handler) APIV1RequestServicePost(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	serviceInfos := orchestrationapi.ReqeustService{}
-	name, ok := appCommand["ServiceName"].(string)
+	if !checkLabels(appCommand) {
+		log.Printf("[%s] invalid label selector", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	serviceInfos, ok := getRequestService(appCommand)
 	if !ok {
 		responseMsg = orchestrationapi.INVALID_PARAMETER
-		responseName = ""
+		responseName = serviceInfos.ServiceName
 		goto SEND_RESP
 	}
+
+	resp = h.api.RequestService(serviceInfos)
+
+	responseMsg = resp.Message
+	responseName = resp.ServiceName
+
+	responseTargetInfo = convertTargetInfo(resp.RemoteTargetInfo)
+
+	if len(resp.GroupID) != 0 {
+		replicaTargetInfo := make([]interface{}, len(resp.ReplicaTargetInfo))
+		for idx, targetInfo := range resp.ReplicaTargetInfo {
+			replicaTargetInfo[idx] = convertTargetInfo(targetInfo)
+		}
+		responseGroup = map[string]interface{}{
+			"GroupID":           resp.GroupID,
+			"ReplicaTargetInfo": replicaTargetInfo,
+		}
+	}
+
+SEND_RESP:
+	respJSONMsg := make(map[string]interface{})
+	respJSONMsg["Message"] = responseMsg
+	respJSONMsg["ServiceName"] = responseName
+	respJSONMsg["RemoteTargetInfo"] = responseTargetInfo
+	for key, value := range responseGroup {
+		respJSONMsg[key] = value
+	}
+	if len(resp.PendingRequestID) != 0 {
+		respJSONMsg["PendingRequestID"] = resp.PendingRequestID
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(respJSONMsg)
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// APIV1RequestServiceDryRunPost handles service request which explains the placement without executing the service
+func (h *Handler) APIV1RequestServiceDryRunPost(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1RequestServiceDryRunPost", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	encryptBytes, _ := ioutil.ReadAll(r.Body)
+
+	appCommand, err := h.Key.DecryptByteToJSON(encryptBytes)
+	if err != nil {
+		log.Printf("[%s] can not decryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	if !checkLabels(appCommand) {
+		log.Printf("[%s] invalid label selector", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	var resp orchestrationapi.DryRunResponse
+	serviceInfos, ok := getRequestService(appCommand)
+	if !ok {
+		resp.Message = orchestrationapi.INVALID_PARAMETER
+		resp.ServiceName = serviceInfos.ServiceName
+	} else {
+		resp = h.api.RequestServiceDryRun(serviceInfos)
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(convertDryRunResponse(resp))
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+func convertDryRunResponse(resp orchestrationapi.DryRunResponse) map[string]interface{} {
+	candidates := make([]interface{}, 0, len(resp.Candidates))
+	for _, candidate := range resp.Candidates {
+		info := make(map[string]interface{})
+		info["DeviceID"] = candidate.DeviceID
+		info["ExecutionType"] = candidate.ExecutionType
+		info["Endpoint"] = candidate.Endpoint
+		info["Labels"] = candidate.Labels
+		info["Score"] = candidate.Score
+		info["ScoreComponents"] = candidate.ScoreComponents
+		info["Error"] = candidate.Error
+		info["ExclusionReason"] = candidate.ExclusionReason
+		info["Rank"] = candidate.Rank
+		candidates = append(candidates, info)
+	}
+
+	targetInfo := make(map[string]interface{})
+	targetInfo["ExecutionType"] = resp.RemoteTargetInfo.ExecutionType
+	targetInfo["Target"] = resp.RemoteTargetInfo.Target
+
+	respJSONMsg := make(map[string]interface{})
+	respJSONMsg["Message"] = resp.Message
+	respJSONMsg["ServiceName"] = resp.ServiceName
+	respJSONMsg["SchedulingPolicy"] = resp.SchedulingPolicy
+	respJSONMsg["RemoteTargetInfo"] = targetInfo
+	respJSONMsg["Candidates"] = candidates
+
+	return respJSONMsg
+}
+
+// APIV1PendingRequestsGet handles request of every request in the pending queue
+func (h *Handler) APIV1PendingRequestsGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1PendingRequestsGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	requests := make([]interface{}, 0)
+	for _, request := range h.api.GetPendingRequests() {
+		requests = append(requests, convertPendingRequest(request))
+	}
+
+	respJSONMsg := make(map[string]interface{})
+	respJSONMsg["PendingRequests"] = requests
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(respJSONMsg)
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// APIV1PendingRequestGet handles request of the status of a pending request
+func (h *Handler) APIV1PendingRequestGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1PendingRequestGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	request, err := h.api.GetPendingRequest(mux.Vars(r)["requestid"])
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusNotFound)
+		return
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(convertPendingRequest(request))
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// APIV1ServiceLogsGet handles request of the output of a service requested by this device,
+// since is the sequence number of the first line to return and follow streams output as server-sent events until the service is ended
+func (h *Handler) APIV1ServiceLogsGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1ServiceLogsGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	serviceID, err := strconv.ParseUint(mux.Vars(r)["serviceid"], 10, 64)
+	if err != nil {
+		log.Printf("[%s] invalid service id", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var since uint64
+	if value := query.Get("since"); len(value) != 0 {
+		if since, err = strconv.ParseUint(value, 10, 64); err != nil {
+			log.Printf("[%s] invalid since : %s", logPrefix, value)
+			h.helper.Response(w, http.StatusBadRequest)
+			return
+		}
+	}
+	follow := query.Get("follow") == "true"
+
+	var wait time.Duration
+	if follow {
+		wait = servicelog.MaxWait
+	}
+
+	chunk, err := h.api.GetServiceLog(serviceID, since, wait)
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		switch err.(type) {
+		case errors.NotFound:
+			h.helper.Response(w, http.StatusNotFound)
+		default:
+			h.helper.Response(w, http.StatusBadGateway)
+		}
+		return
+	}
+
+	if !follow {
+		respEncryptBytes, err := h.Key.EncryptJSONToByte(chunk.ToMap())
+		if err != nil {
+			log.Printf("[%s] can not encryption", logPrefix)
+			h.helper.Response(w, http.StatusServiceUnavailable)
+			return
+		}
+
+		h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("[%s] does not support streaming", logPrefix)
+		h.helper.Response(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		if chunk.Dropped {
+			h.writeEvent(w, "dropped", map[string]interface{}{"ServiceID": float64(serviceID), "Since": float64(since)})
+		}
+		for _, line := range chunk.Lines {
+			h.writeEvent(w, "log", line.ToMap())
+		}
+		if chunk.Finished {
+			h.writeEvent(w, "end", map[string]interface{}{"ServiceID": float64(serviceID), "Next": float64(chunk.Next)})
+		}
+		flusher.Flush()
+
+		if chunk.Finished {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			log.Printf("[%s] service log subscriber is disconnected", logPrefix)
+			return
+		default:
+		}
+
+		since = chunk.Next
+		if chunk, err = h.api.GetServiceLog(serviceID, since, wait); err != nil {
+			log.Printf("[%s] %s", logPrefix, err.Error())
+			return
+		}
+	}
+}
+
+func (h *Handler) writeEvent(w http.ResponseWriter, event string, info map[string]interface{}) {
+	encryptBytes, err := h.Key.EncryptJSONToByte(info)
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		return
+	}
+
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, encryptBytes)
+}
+
+func convertTargetInfo(info orchestrationapi.TargetInfo) map[string]interface{} {
+	targetInfo := make(map[string]interface{})
+	targetInfo["ExecutionType"] = info.ExecutionType
+	targetInfo["Target"] = info.Target
+	if info.ServiceID != 0 {
+		targetInfo["ServiceID"] = float64(info.ServiceID)
+	}
+	return targetInfo
+}
+
+func convertPendingRequest(request orchestrationapi.PendingRequest) map[string]interface{} {
+	targetInfo := convertTargetInfo(request.Response.RemoteTargetInfo)
+
+	info := make(map[string]interface{})
+	info["RequestID"] = request.RequestID
+	info["ServiceName"] = request.ServiceName
+	info["Status"] = request.Status
+	info["Deadline"] = request.Deadline.Format(time.RFC3339)
+	info["Reason"] = request.Reason
+	info["RemoteTargetInfo"] = targetInfo
+	return info
+}
+
+// APIV1ServiceGroupGet handles request of the status of every replica of a service group
+func (h *Handler) APIV1ServiceGroupGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1ServiceGroupGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	group, err := h.api.GetServiceGroup(mux.Vars(r)["groupid"])
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusNotFound)
+		return
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(convertServiceGroup(group))
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// APIV1ServiceGroupEventsGet streams the aggregated result of service groups as server-sent events when every replica is done
+func (h *Handler) APIV1ServiceGroupEventsGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1ServiceGroupEventsGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("[%s] does not support streaming", logPrefix)
+		h.helper.Response(w, http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := h.api.SubscribeServiceGroup()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Printf("[%s] service group subscriber is disconnected", logPrefix)
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			encryptBytes, err := h.Key.EncryptJSONToByte(convertServiceGroup(event))
+			if err != nil {
+				log.Printf("[%s] can not encryption", logPrefix)
+				continue
+			}
+
+			fmt.Fprintf(w, "event: group\ndata: %s\n\n", encryptBytes)
+			flusher.Flush()
+		}
+	}
+}
+
+func convertServiceGroup(group orchestrationapi.ServiceGroupEvent) map[string]interface{} {
+	replicas := make([]interface{}, len(group.Replicas))
+	for idx, replica := range group.Replicas {
+		replicas[idx] = map[string]interface{}{
+			"DeviceID": replica.DeviceID,
+			"Target":   replica.Target,
+			"Status":   replica.Status,
+		}
+	}
+
+	info := make(map[string]interface{})
+	info["GroupID"] = group.GroupID
+	info["ServiceName"] = group.ServiceName
+	info["Status"] = group.Status
+	info["Done"] = group.Done
+	info["Replicas"] = replicas
+	return info
+}
+
+// APIV1ResourceHistoryGet handles request of the recent samples of a local resource with their statistics,
+// name is the resource name and window is the seconds of samples to return (every sample if it is omitted)
+func (h *Handler) APIV1ResourceHistoryGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1ResourceHistoryGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if len(name) == 0 {
+		log.Printf("[%s] resource name is missing", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	var window time.Duration
+	if value := query.Get("window"); len(value) != 0 {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			log.Printf("[%s] invalid window : %s", logPrefix, value)
+			h.helper.Response(w, http.StatusBadRequest)
+			return
+		}
+		window = time.Duration(seconds * float64(time.Second))
+	}
+
+	history, err := h.api.GetResourceHistory(name, window)
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		switch err.(type) {
+		case errors.NotSupport:
+			h.helper.Response(w, http.StatusBadRequest)
+		default:
+			h.helper.Response(w, http.StatusNotFound)
+		}
+		return
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(convertResourceHistory(history))
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+func convertResourceHistory(history resourceDB.History) map[string]interface{} {
+	samples := make([]interface{}, 0, len(history.Samples))
+	for _, sample := range history.Samples {
+		samples = append(samples, map[string]interface{}{
+			"Time":  sample.Time.Format(time.RFC3339),
+			"Value": sample.Value,
+		})
+	}
+
+	info := make(map[string]interface{})
+	info["Name"] = history.Name
+	info["Window"] = history.Window.Seconds()
+	info["Count"] = history.Count
+	info["Latest"] = history.Latest
+	info["Avg"] = history.Avg
+	info["Min"] = history.Min
+	info["Max"] = history.Max
+	info["P95"] = history.P95
+	info["Samples"] = samples
+	return info
+}
+
+// APIV1WorkflowPost handles workflow request which runs steps of services in the order of dependencies
+func (h *Handler) APIV1WorkflowPost(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1WorkflowPost", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	encryptBytes, _ := ioutil.ReadAll(r.Body)
+
+	appCommand, err := h.Key.DecryptByteToJSON(encryptBytes)
+	if err != nil {
+		log.Printf("[%s] can not decryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	var resp orchestrationapi.WorkflowStatus
+	request, ok := getWorkflowRequest(appCommand)
+	if !ok {
+		resp.Message = orchestrationapi.INVALID_PARAMETER
+		resp.WorkflowName = request.WorkflowName
+	} else {
+		resp = h.api.RequestWorkflow(request)
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(convertWorkflowStatus(resp))
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// APIV1WorkflowGet handles request of the workflow status
+func (h *Handler) APIV1WorkflowGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1WorkflowGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	resp, err := h.api.GetWorkflowStatus(mux.Vars(r)["workflowid"])
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusNotFound)
+		return
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(convertWorkflowStatus(resp))
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// getWorkflowRequest converts workflow request, each step has Name, DependsOn and the fields of service request
+func getWorkflowRequest(appCommand map[string]interface{}) (request orchestrationapi.WorkflowRequest, ok bool) {
+	request.WorkflowName, ok = appCommand["WorkflowName"].(string)
+	if !ok {
+		return request, false
+	}
+
+	steps, ok := appCommand["Steps"].([]interface{})
+	if !ok {
+		return request, false
+	}
+
+	request.Steps = make([]orchestrationapi.WorkflowStep, len(steps))
+	for idx, value := range steps {
+		step, ok := value.(map[string]interface{})
+		if !ok {
+			return request, false
+		}
+
+		request.Steps[idx].Name, ok = step["Name"].(string)
+		if !ok {
+			return request, false
+		}
+
+		if dependsOn, exist := step["DependsOn"]; exist && dependsOn != nil {
+			deps, ok := dependsOn.([]interface{})
+			if !ok {
+				return request, false
+			}
+			request.Steps[idx].DependsOn = make([]string, len(deps))
+			for idy, dep := range deps {
+				request.Steps[idx].DependsOn[idy], ok = dep.(string)
+				if !ok {
+					return request, false
+				}
+			}
+		}
+
+		request.Steps[idx].Service, ok = getRequestService(step)
+		if !ok {
+			return request, false
+		}
+	}
+
+	return request, true
+}
+
+func convertWorkflowStatus(resp orchestrationapi.WorkflowStatus) map[string]interface{} {
+	steps := make([]interface{}, 0, len(resp.Steps))
+	for _, step := range resp.Steps {
+		targetInfo := convertTargetInfo(step.RemoteTargetInfo)
+
+		info := make(map[string]interface{})
+		info["Name"] = step.Name
+		info["Status"] = step.Status
+		info["Message"] = step.Message
+		info["RemoteTargetInfo"] = targetInfo
+		info["Output"] = step.Output
+		steps = append(steps, info)
+	}
+
+	respJSONMsg := make(map[string]interface{})
+	respJSONMsg["Message"] = resp.Message
+	respJSONMsg["WorkflowID"] = resp.WorkflowID
+	respJSONMsg["WorkflowName"] = resp.WorkflowName
+	respJSONMsg["Status"] = resp.Status
+	respJSONMsg["Steps"] = steps
+
+	return respJSONMsg
+}
+
+// APIV1DeviceEventsGet streams device join/update/leave events to service application as server-sent events
+func (h *Handler) APIV1DeviceEventsGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1DeviceEventsGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("[%s] does not support streaming", logPrefix)
+		h.helper.Response(w, http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := h.api.SubscribeDeviceEvent()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Printf("[%s] device event subscriber is disconnected", logPrefix)
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			encryptBytes, err := h.Key.EncryptJSONToByte(convertDeviceEvent(event))
+			if err != nil {
+				log.Printf("[%s] can not encryption", logPrefix)
+				continue
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, encryptBytes)
+			flusher.Flush()
+		}
+	}
+}
+
+func convertDeviceEvent(event discoverymgr.DeviceEvent) map[string]interface{} {
+	info := make(map[string]interface{})
+	info["Platform"] = event.Info.Platform
+	info["ExecutionType"] = event.Info.ExecutionType
+	info["IPv4"] = event.Info.IPv4
+	info["ServiceList"] = event.Info.ServiceList
+	info["Labels"] = event.Info.Labels
+
+	eventJSONMsg := make(map[string]interface{})
+	eventJSONMsg["Type"] = event.Type
+	eventJSONMsg["DeviceID"] = event.DeviceID
+	eventJSONMsg["Info"] = info
+
+	return eventJSONMsg
+}
+
+// getRequestService converts service request, ServiceName is kept if the request is invalid
+func getRequestService(appCommand map[string]interface{}) (serviceInfos orchestrationapi.ReqeustService, ok bool) {
+	name, ok := appCommand["ServiceName"].(string)
+	if !ok {
+		return serviceInfos, false
+	}
 	serviceInfos.ServiceName = name
 
-	executeEnvs, ok = appCommand["ServiceInfo"].([]interface{})
+	executeEnvs, ok := appCommand["ServiceInfo"].([]interface{})
 	if !ok {
-		responseMsg = orchestrationapi.INVALID_PARAMETER
-		responseName = name
-		goto SEND_RESP
+		return serviceInfos, false
 	}
 
 	serviceInfos.ServiceInfo = make([]orchestrationapi.RequestServiceInfo, len(executeEnvs))
@@ -123,17 +917,22 @@ func (h *Handler) APIV1RequestServicePost(w http.ResponseWriter, r *http.Request
 		tmp := executeEnv.(map[string]interface{})
 		exeType, ok := tmp["ExecutionType"].(string)
 		if !ok {
-			responseMsg = orchestrationapi.INVALID_PARAMETER
-			responseName = name
-			goto SEND_RESP
+			return serviceInfos, false
 		}
 		serviceInfos.ServiceInfo[idx].ExecutionType = exeType
 
+		if spec, exist := tmp["ContainerSpec"]; exist && spec != nil {
+			serviceInfos.ServiceInfo[idx].ContainerSpec, ok = getContainerSpec(spec)
+			if !ok {
+				return serviceInfos, false
+			}
+		}
+
 		exeCmd, ok := tmp["ExecCmd"].([]interface{})
-		if !ok {
-			responseMsg = orchestrationapi.INVALID_PARAMETER
-			responseName = name
-			goto SEND_RESP
+		if !ok && serviceInfos.ServiceInfo[idx].ContainerSpec != nil {
+			continue
+		} else if !ok {
+			return serviceInfos, false
 		}
 
 		serviceInfos.ServiceInfo[idx].ExeCmd = make([]string, len(exeCmd))
@@ -142,29 +941,148 @@ func (h *Handler) APIV1RequestServicePost(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	resp = h.api.RequestService(serviceInfos)
+	serviceInfos.RequiredLabels, ok = getLabels(appCommand, "RequiredLabels")
+	if !ok {
+		return serviceInfos, false
+	}
 
-	responseMsg = resp.Message
-	responseName = resp.ServiceName
+	serviceInfos.PreferredLabels, ok = getLabels(appCommand, "PreferredLabels")
+	if !ok {
+		return serviceInfos, false
+	}
 
-	responseTargetInfo = make(map[string]interface{})
-	responseTargetInfo["ExecutionType"] = resp.RemoteTargetInfo.ExecutionType
-	responseTargetInfo["Target"] = resp.RemoteTargetInfo.Target
+	if policy, exist := appCommand["SchedulingPolicy"]; exist && policy != nil {
+		serviceInfos.SchedulingPolicy, ok = policy.(string)
+		if !ok {
+			return serviceInfos, false
+		}
+	}
 
-SEND_RESP:
-	respJSONMsg := make(map[string]interface{})
-	respJSONMsg["Message"] = responseMsg
-	respJSONMsg["ServiceName"] = responseName
-	respJSONMsg["RemoteTargetInfo"] = responseTargetInfo
+	if replicas, exist := appCommand["Replicas"]; exist && replicas != nil {
+		count, ok := replicas.(float64)
+		if !ok || count < 0 || count != float64(int(count)) {
+			return serviceInfos, false
+		}
+		serviceInfos.Replicas = int(count)
+	}
 
-	respEncryptBytes, err := h.Key.EncryptJSONToByte(respJSONMsg)
-	if err != nil {
-		log.Printf("[%s] can not encryption", logPrefix)
-		h.helper.Response(w, http.StatusServiceUnavailable)
+	if policy, exist := appCommand["SpreadPolicy"]; exist && policy != nil {
+		serviceInfos.SpreadPolicy, ok = policy.(string)
+		if !ok {
+			return serviceInfos, false
+		}
+	}
+
+	if timeout, exist := appCommand["PendingTimeout"]; exist && timeout != nil {
+		seconds, ok := timeout.(float64)
+		if !ok || seconds < 0 {
+			return serviceInfos, false
+		}
+		serviceInfos.PendingTimeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	if timeout, exist := appCommand["ExecutionTimeout"]; exist && timeout != nil {
+		seconds, ok := timeout.(float64)
+		if !ok || seconds < 0 {
+			return serviceInfos, false
+		}
+		serviceInfos.ExecutionTimeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	if restart, exist := appCommand["RestartPolicy"]; exist && restart != nil {
+		serviceInfos.RestartPolicy, ok = getRestartPolicy(restart)
+		if !ok {
+			return serviceInfos, false
+		}
+	}
+
+	if stdin, exist := appCommand["Stdin"]; exist && stdin != nil {
+		serviceInfos.Stdin, ok = stdin.(string)
+		if !ok {
+			return serviceInfos, false
+		}
+	}
+
+	return serviceInfos, true
+}
+
+// getRestartPolicy parses {"Policy": string, "MaxRetries": number, "Backoff": seconds}
+func getRestartPolicy(restart interface{}) (policy servicemgr.RestartPolicy, ok bool) {
+	restartMap, ok := restart.(map[string]interface{})
+	if !ok {
 		return
 	}
 
-	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+	if policy.Policy, ok = restartMap["Policy"].(string); !ok {
+		return
+	}
+
+	if retries, exist := restartMap["MaxRetries"]; exist && retries != nil {
+		count, isNumber := retries.(float64)
+		if !isNumber || count < 0 {
+			return policy, false
+		}
+		policy.MaxRetries = int(count)
+	}
+
+	if backoff, exist := restartMap["Backoff"]; exist && backoff != nil {
+		seconds, isNumber := backoff.(float64)
+		if !isNumber || seconds < 0 {
+			return policy, false
+		}
+		policy.Backoff = time.Duration(seconds * float64(time.Second))
+	}
+
+	return policy, true
+}
+
+// getContainerSpec parses the container spec of service info, the spec is validated
+func getContainerSpec(spec interface{}) (*containerspec.Spec, bool) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, false
+	}
+
+	decoded, err := containerspec.Decode(data)
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		return nil, false
+	}
+	return &decoded, true
+}
+
+// checkLabels reports whether the label selectors of service request are well-formed
+func checkLabels(appCommand map[string]interface{}) bool {
+	for _, key := range []string{"RequiredLabels", "PreferredLabels"} {
+		if _, ok := getLabels(appCommand, key); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// getLabels converts optional label selector of service request
+func getLabels(appCommand map[string]interface{}, key string) (labels map[string]string, ok bool) {
+	value, exist := appCommand[key]
+	if !exist || value == nil {
+		return nil, true
+	}
+
+	selector, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	labels = make(map[string]string)
+	for labelKey, labelValue := range selector {
+		str, ok := labelValue.(string)
+		if !ok {
+			return nil, false
+		}
+		labels[labelKey] = str
+	}
+
+	return labels, true
 }
 
 func (h *Handler) setHelper(helper resthelper.RestHelper) {
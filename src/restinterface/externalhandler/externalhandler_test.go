@@ -21,14 +21,23 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	commonErrors "common/errors"
+	discoverymgr "controller/discoverymgr"
+	"controller/servicemgr"
+	"controller/servicemgr/executor/containerspec"
+	"controller/servicemgr/servicelog"
+	resourceDB "db/bolt/resource"
 	orchestrationapi "orchestrationapi"
 	orchemock "orchestrationapi/mocks"
 	ciphermock "restinterface/cipher/mocks"
 	helpermock "restinterface/resthelper/mocks"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 )
 
 func TestGetHandler(t *testing.T) {
@@ -212,6 +221,69 @@ func TestAPIV1RequestServicePost(t *testing.T) {
 
 				handler.APIV1RequestServicePost(w, r)
 			})
+			t.Run("ExecutionTimeout", func(t *testing.T) {
+				handler.SetCipher(mockCipher)
+				handler.SetOrchestrationAPI(mockOrchestration)
+				handler.setHelper(mockHelper)
+
+				_, appCommand := getReqeustArgs()
+				appCommand["ExecutionTimeout"] = -1.0
+
+				gomock.InOrder(
+					mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+					mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+						if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+							t.Error("unexpected response")
+						}
+					}).Return(nil, nil),
+					mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+				)
+
+				handler.APIV1RequestServicePost(w, r)
+			})
+			t.Run("RestartPolicy", func(t *testing.T) {
+				handler.SetCipher(mockCipher)
+				handler.SetOrchestrationAPI(mockOrchestration)
+				handler.setHelper(mockHelper)
+
+				_, appCommand := getReqeustArgs()
+				appCommand["RestartPolicy"] = map[string]interface{}{"Policy": "always", "MaxRetries": "3"}
+
+				gomock.InOrder(
+					mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+					mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+						if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+							t.Error("unexpected response")
+						}
+					}).Return(nil, nil),
+					mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+				)
+
+				handler.APIV1RequestServicePost(w, r)
+			})
+			t.Run("ContainerSpec", func(t *testing.T) {
+				handler.SetCipher(mockCipher)
+				handler.SetOrchestrationAPI(mockOrchestration)
+				handler.setHelper(mockHelper)
+
+				_, appCommand := getReqeustArgs()
+				appCommand["ServiceInfo"] = []interface{}{map[string]interface{}{
+					"ExecutionType": "container",
+					"ContainerSpec": map[string]interface{}{"Image": "hello-world", "Volumes": []interface{}{"/tmp"}},
+				}}
+
+				gomock.InOrder(
+					mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+					mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+						if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+							t.Error("unexpected response")
+						}
+					}).Return(nil, nil),
+					mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+				)
+
+				handler.APIV1RequestServicePost(w, r)
+			})
 			t.Run("ExecutionType", func(t *testing.T) {
 				handler.SetCipher(mockCipher)
 				handler.SetOrchestrationAPI(mockOrchestration)
@@ -276,4 +348,749 @@ func TestAPIV1RequestServicePost(t *testing.T) {
 
 		handler.APIV1RequestServicePost(w, r)
 	})
+	t.Run("Labels", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		t.Run("Success", func(t *testing.T) {
+			requestService, appCommand := getReqeustArgs()
+			requestService.RequiredLabels = map[string]string{"zone": "kitchen"}
+			requestService.PreferredLabels = map[string]string{"gpu": "true"}
+			appCommand["RequiredLabels"] = map[string]interface{}{"zone": "kitchen"}
+			appCommand["PreferredLabels"] = map[string]interface{}{"gpu": "true"}
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockOrchestration.EXPECT().RequestService(gomock.Eq(requestService)),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
+				mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+		t.Run("NonStringValue", func(t *testing.T) {
+			_, appCommand := getReqeustArgs()
+			appCommand["RequiredLabels"] = map[string]interface{}{"zone": "kitchen", "floor": 2.0}
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+		t.Run("NonObjectSelector", func(t *testing.T) {
+			_, appCommand := getReqeustArgs()
+			appCommand["PreferredLabels"] = []interface{}{"zone=kitchen"}
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+	})
+	t.Run("SchedulingPolicy", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		t.Run("Success", func(t *testing.T) {
+			requestService, appCommand := getReqeustArgs()
+			requestService.SchedulingPolicy = "round-robin"
+			appCommand["SchedulingPolicy"] = "round-robin"
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockOrchestration.EXPECT().RequestService(gomock.Eq(requestService)),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
+				mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+		t.Run("InvalidParam", func(t *testing.T) {
+			_, appCommand := getReqeustArgs()
+			appCommand["SchedulingPolicy"] = 1.0
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+					if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+						t.Error("unexpected response")
+					}
+				}).Return(nil, nil),
+				mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+	})
+	t.Run("Replicas", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		t.Run("Success", func(t *testing.T) {
+			requestService, appCommand := getReqeustArgs()
+			requestService.Replicas = 2
+			requestService.SpreadPolicy = "wrap"
+			appCommand["Replicas"] = 2.0
+			appCommand["SpreadPolicy"] = "wrap"
+
+			resp := orchestrationapi.ResponseService{
+				Message:           orchestrationapi.ERROR_NONE,
+				GroupID:           "group",
+				ReplicaTargetInfo: []orchestrationapi.TargetInfo{{Target: "endpoint1"}, {Target: "endpoint2"}},
+			}
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockOrchestration.EXPECT().RequestService(gomock.Eq(requestService)).Return(resp),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+					targets, ok := resp["ReplicaTargetInfo"].([]interface{})
+					if resp["GroupID"] != "group" || !ok || len(targets) != 2 {
+						t.Error("unexpected response : ", resp)
+					}
+				}).Return(nil, nil),
+				mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+		t.Run("InvalidParam", func(t *testing.T) {
+			_, appCommand := getReqeustArgs()
+			appCommand["Replicas"] = 1.5
+
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+					if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+						t.Error("unexpected response")
+					}
+				}).Return(nil, nil),
+				mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+			)
+
+			handler.APIV1RequestServicePost(w, r)
+		})
+	})
+	t.Run("PendingTimeout", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		requestService, appCommand := getReqeustArgs()
+		requestService.PendingTimeout = 30 * time.Second
+		appCommand["PendingTimeout"] = 30.0
+
+		resp := orchestrationapi.ResponseService{Message: orchestrationapi.PENDING, PendingRequestID: "pending-1"}
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+			mockOrchestration.EXPECT().RequestService(gomock.Eq(requestService)).Return(resp),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				if resp["Message"] != orchestrationapi.PENDING || resp["PendingRequestID"] != "pending-1" {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1RequestServicePost(w, r)
+	})
+	t.Run("ExecutionOptions", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		requestService, appCommand := getReqeustArgs()
+		requestService.Stdin = "payload"
+		requestService.ExecutionTimeout = 1500 * time.Millisecond
+		requestService.RestartPolicy = servicemgr.RestartPolicy{Policy: servicemgr.RestartOnFailure, MaxRetries: 3, Backoff: 2 * time.Second}
+		appCommand["Stdin"] = "payload"
+		appCommand["ExecutionTimeout"] = 1.5
+		appCommand["RestartPolicy"] = map[string]interface{}{"Policy": "on-failure", "MaxRetries": 3.0, "Backoff": 2.0}
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+			mockOrchestration.EXPECT().RequestService(gomock.Eq(requestService)).Return(orchestrationapi.ResponseService{Message: orchestrationapi.ERROR_NONE}),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1RequestServicePost(w, r)
+	})
+	t.Run("ContainerSpec", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		requestService, appCommand := getReqeustArgs()
+		requestService.ServiceInfo = []orchestrationapi.RequestServiceInfo{{
+			ExecutionType: "container",
+			ContainerSpec: &containerspec.Spec{
+				Image:  "nginx",
+				Env:    map[string]string{"MODE": "edge"},
+				Ports:  []containerspec.Port{{ContainerPort: 80, HostPort: 8080}},
+				Mounts: []containerspec.Mount{{Source: "/var/www", Target: "/www", ReadOnly: true}},
+			},
+		}}
+		appCommand["ServiceInfo"] = []interface{}{map[string]interface{}{
+			"ExecutionType": "container",
+			"ContainerSpec": map[string]interface{}{
+				"Image":  "nginx",
+				"Env":    map[string]interface{}{"MODE": "edge"},
+				"Ports":  []interface{}{map[string]interface{}{"ContainerPort": 80.0, "HostPort": 8080.0}},
+				"Mounts": []interface{}{map[string]interface{}{"Source": "/var/www", "Target": "/www", "ReadOnly": true}},
+			},
+		}}
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+			mockOrchestration.EXPECT().RequestService(gomock.Eq(requestService)).Return(orchestrationapi.ResponseService{Message: orchestrationapi.ERROR_NONE}),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1RequestServicePost(w, r)
+	})
+}
+
+func TestAPIV1RequestServiceDryRunPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	if handler == nil {
+		t.Error("unexpected return value")
+	}
+
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	r := httptest.NewRequest("POST", "http://test.test", nil)
+	w := httptest.NewRecorder()
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	t.Run("InvalidParam", func(t *testing.T) {
+		_, appCommand := getReqeustArgs()
+		delete(appCommand, "ServiceInfo")
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+					t.Error("unexpected response")
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1RequestServiceDryRunPost(w, r)
+	})
+	t.Run("InvalidLabels", func(t *testing.T) {
+		_, appCommand := getReqeustArgs()
+		appCommand["RequiredLabels"] = "zone=kitchen"
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest)),
+		)
+
+		handler.APIV1RequestServiceDryRunPost(w, r)
+	})
+	t.Run("Success", func(t *testing.T) {
+		requestService, appCommand := getReqeustArgs()
+		dryRun := orchestrationapi.DryRunResponse{
+			Message:     orchestrationapi.ERROR_NONE,
+			ServiceName: requestService.ServiceName,
+			Candidates: []orchestrationapi.CandidateExplanation{
+				{DeviceID: "ID1", Score: 3.0, Rank: 1},
+				{DeviceID: "ID2", ExclusionReason: "service is not installed"},
+			},
+		}
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appCommand, nil),
+			mockOrchestration.EXPECT().RequestServiceDryRun(gomock.Eq(requestService)).Return(dryRun),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				candidates, ok := resp["Candidates"].([]interface{})
+				if resp["Message"] != orchestrationapi.ERROR_NONE || !ok || len(candidates) != 2 {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1RequestServiceDryRunPost(w, r)
+	})
+}
+
+func TestAPIV1PendingRequestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	r := mux.SetURLVars(httptest.NewRequest("GET", "http://test.test", nil), map[string]string{"requestid": "pending-1"})
+	w := httptest.NewRecorder()
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	request := orchestrationapi.PendingRequest{
+		RequestID:   "pending-1",
+		ServiceName: "MyApp",
+		Status:      orchestrationapi.PendingStatusWaiting,
+		Reason:      "no device",
+	}
+
+	t.Run("List", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetPendingRequests().Return([]orchestrationapi.PendingRequest{request}),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				requests, ok := resp["PendingRequests"].([]interface{})
+				if !ok || len(requests) != 1 {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1PendingRequestsGet(w, r)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetPendingRequest(gomock.Eq("pending-1")).Return(orchestrationapi.PendingRequest{}, errors.New("not found")),
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusNotFound)),
+		)
+
+		handler.APIV1PendingRequestGet(w, r)
+	})
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetPendingRequest(gomock.Eq("pending-1")).Return(request, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				if resp["Status"] != orchestrationapi.PendingStatusWaiting || resp["Reason"] != "no device" {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1PendingRequestGet(w, r)
+	})
+}
+
+func TestAPIV1ResourceHistoryGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	w := httptest.NewRecorder()
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	t.Run("Success", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://test.test/api/v1/orchestration/resources/history?name=cpu/usage&window=60", nil)
+		history := resourceDB.History{
+			Name:    "cpu/usage",
+			Window:  time.Minute,
+			Samples: []resourceDB.Sample{{Time: time.Now(), Value: 12.0}},
+			Count:   1,
+			Avg:     12.0,
+			P95:     12.0,
+		}
+
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetResourceHistory(gomock.Eq("cpu/usage"), gomock.Eq(time.Minute)).Return(history, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				samples, ok := resp["Samples"].([]interface{})
+				if !ok || len(samples) != 1 || resp["Avg"] != 12.0 || resp["Window"] != 60.0 {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1ResourceHistoryGet(w, r)
+	})
+	t.Run("MissingName", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://test.test/api/v1/orchestration/resources/history", nil)
+		mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest))
+
+		handler.APIV1ResourceHistoryGet(w, r)
+	})
+	t.Run("InvalidWindow", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://test.test/api/v1/orchestration/resources/history?name=cpu/usage&window=-1", nil)
+		mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest))
+
+		handler.APIV1ResourceHistoryGet(w, r)
+	})
+	t.Run("NotSupported", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://test.test/api/v1/orchestration/resources/history?name=network/rtt", nil)
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetResourceHistory(gomock.Eq("network/rtt"), gomock.Eq(time.Duration(0))).Return(resourceDB.History{}, commonErrors.NotSupport{}),
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest)),
+		)
+
+		handler.APIV1ResourceHistoryGet(w, r)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://test.test/api/v1/orchestration/resources/history?name=cpu/usage", nil)
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetResourceHistory(gomock.Eq("cpu/usage"), gomock.Any()).Return(resourceDB.History{}, commonErrors.NotFound{}),
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusNotFound)),
+		)
+
+		handler.APIV1ResourceHistoryGet(w, r)
+	})
+}
+
+func TestAPIV1WorkflowPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	r := httptest.NewRequest("POST", "http://test.test", nil)
+	w := httptest.NewRecorder()
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	getWorkflowArgs := func() (orchestrationapi.WorkflowRequest, map[string]interface{}) {
+		requestService, appCommand := getReqeustArgs()
+		step := appCommand
+		step["Name"] = "capture"
+		step["DependsOn"] = []interface{}{}
+
+		request := orchestrationapi.WorkflowRequest{
+			WorkflowName: "pipeline",
+			Steps: []orchestrationapi.WorkflowStep{
+				{Name: "capture", DependsOn: []string{}, Service: requestService},
+			},
+		}
+		workflow := map[string]interface{}{
+			"WorkflowName": "pipeline",
+			"Steps":        []interface{}{step},
+		}
+		return request, workflow
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		request, workflow := getWorkflowArgs()
+		status := orchestrationapi.WorkflowStatus{Message: orchestrationapi.ERROR_NONE, WorkflowID: "pipeline-1"}
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(workflow, nil),
+			mockOrchestration.EXPECT().RequestWorkflow(gomock.Eq(request)).Return(status),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				if resp["WorkflowID"] != "pipeline-1" {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1WorkflowPost(w, r)
+	})
+	t.Run("InvalidParam", func(t *testing.T) {
+		_, workflow := getWorkflowArgs()
+		workflow["Steps"].([]interface{})[0].(map[string]interface{})["DependsOn"] = "capture"
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(workflow, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				if resp["Message"] != orchestrationapi.INVALID_PARAMETER {
+					t.Error("unexpected response")
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1WorkflowPost(w, r)
+	})
+}
+
+func TestAPIV1WorkflowGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	r := mux.SetURLVars(httptest.NewRequest("GET", "http://test.test", nil), map[string]string{"workflowid": "pipeline-1"})
+	w := httptest.NewRecorder()
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	t.Run("NotFound", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetWorkflowStatus(gomock.Eq("pipeline-1")).Return(orchestrationapi.WorkflowStatus{}, errors.New("not found")),
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusNotFound)),
+		)
+
+		handler.APIV1WorkflowGet(w, r)
+	})
+	t.Run("Success", func(t *testing.T) {
+		status := orchestrationapi.WorkflowStatus{
+			Message:    orchestrationapi.ERROR_NONE,
+			WorkflowID: "pipeline-1",
+			Status:     "Started",
+			Steps:      []orchestrationapi.StepStatus{{Name: "capture", Status: "Finished", Output: "frames"}},
+		}
+
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetWorkflowStatus(gomock.Eq("pipeline-1")).Return(status, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				steps, ok := resp["Steps"].([]interface{})
+				if resp["Status"] != "Started" || !ok || len(steps) != 1 {
+					t.Error("unexpected response : ", resp)
+				} else if output := steps[0].(map[string]interface{})["Output"]; output != "frames" {
+					t.Error("unexpected step output : ", output)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1WorkflowGet(w, r)
+	})
+}
+
+func TestAPIV1ServiceLogsGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	newRequest := func(target string, serviceID string) *http.Request {
+		return mux.SetURLVars(httptest.NewRequest("GET", target, nil), map[string]string{"serviceid": serviceID})
+	}
+	line := servicelog.Line{Seq: 2, Time: time.Now(), Stream: servicelog.StreamStdout, Text: "hello"}
+
+	t.Run("Error", func(t *testing.T) {
+		t.Run("InvalidServiceID", func(t *testing.T) {
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest))
+			handler.APIV1ServiceLogsGet(httptest.NewRecorder(), newRequest("http://test.test", "test"))
+		})
+		t.Run("InvalidSince", func(t *testing.T) {
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest))
+			handler.APIV1ServiceLogsGet(httptest.NewRecorder(), newRequest("http://test.test?since=-1", "1"))
+		})
+		t.Run("NotFound", func(t *testing.T) {
+			gomock.InOrder(
+				mockOrchestration.EXPECT().GetServiceLog(uint64(1), uint64(0), time.Duration(0)).Return(servicelog.Chunk{}, commonErrors.NotFound{Message: "1"}),
+				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusNotFound)),
+			)
+			handler.APIV1ServiceLogsGet(httptest.NewRecorder(), newRequest("http://test.test", "1"))
+		})
+	})
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetServiceLog(uint64(1), uint64(2), time.Duration(0)).Return(servicelog.Chunk{ServiceID: 1, Lines: []servicelog.Line{line}, Next: 3}, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				lines, ok := resp["Lines"].([]interface{})
+				if !ok || len(lines) != 1 || resp["Next"] != float64(3) {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+		handler.APIV1ServiceLogsGet(httptest.NewRecorder(), newRequest("http://test.test?since=2", "1"))
+	})
+	t.Run("Follow", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetServiceLog(uint64(1), uint64(2), servicelog.MaxWait).Return(servicelog.Chunk{ServiceID: 1, Lines: []servicelog.Line{line}, Next: 3}, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return([]byte("line"), nil),
+			mockOrchestration.EXPECT().GetServiceLog(uint64(1), uint64(3), servicelog.MaxWait).Return(servicelog.Chunk{ServiceID: 1, Next: 3, Finished: true}, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return([]byte("end"), nil),
+		)
+
+		w := httptest.NewRecorder()
+		handler.APIV1ServiceLogsGet(w, newRequest("http://test.test?since=2&follow=true", "1"))
+
+		if w.Body.String() != "event: log\ndata: line\n\nevent: end\ndata: end\n\n" {
+			t.Error("unexpected body : ", w.Body.String())
+		}
+	})
+}
+
+func TestAPIV1DeviceEventsGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	r := httptest.NewRequest("GET", "http://test.test", nil)
+
+	t.Run("Error", func(t *testing.T) {
+		t.Run("IsNotSetApi", func(t *testing.T) {
+			handler.setHelper(mockHelper)
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusServiceUnavailable))
+
+			handler.isSetAPI = false
+			handler.APIV1DeviceEventsGet(httptest.NewRecorder(), r)
+		})
+	})
+	t.Run("Success", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		events := make(chan discoverymgr.DeviceEvent, 1)
+		events <- discoverymgr.DeviceEvent{Type: discoverymgr.DeviceJoined, DeviceID: "test"}
+		close(events)
+
+		canceled := false
+		cancel := func() { canceled = true }
+
+		gomock.InOrder(
+			mockOrchestration.EXPECT().SubscribeDeviceEvent().Return((<-chan discoverymgr.DeviceEvent)(events), cancel),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(event map[string]interface{}) {
+				if event["DeviceID"] != "test" {
+					t.Error("unexpected event")
+				}
+			}).Return([]byte("test"), nil),
+		)
+
+		w := httptest.NewRecorder()
+		handler.APIV1DeviceEventsGet(w, r)
+
+		if !strings.Contains(w.Body.String(), "data: test\n\n") {
+			t.Error("unexpected body : ", w.Body.String())
+		}
+		if !canceled {
+			t.Error("subscription is not canceled")
+		}
+	})
+}
+
+func TestAPIV1ServiceGroupGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	newRequest := func(groupID string) *http.Request {
+		return mux.SetURLVars(httptest.NewRequest("GET", "http://test.test", nil), map[string]string{"groupid": groupID})
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetServiceGroup("MyApp-0").Return(orchestrationapi.ServiceGroupEvent{}, commonErrors.NotFound{Message: "MyApp-0"}),
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusNotFound)),
+		)
+
+		handler.APIV1ServiceGroupGet(httptest.NewRecorder(), newRequest("MyApp-0"))
+	})
+	t.Run("Success", func(t *testing.T) {
+		group := orchestrationapi.ServiceGroupEvent{
+			GroupID:     "MyApp-1",
+			ServiceName: "MyApp",
+			Status:      "Started",
+			Replicas: []orchestrationapi.ReplicaStatus{
+				{DeviceID: "ID1", Target: "endpoint1", Status: "Finished"},
+				{DeviceID: "ID2", Target: "endpoint2", Status: "Started"},
+			},
+		}
+
+		gomock.InOrder(
+			mockOrchestration.EXPECT().GetServiceGroup("MyApp-1").Return(group, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(resp map[string]interface{}) {
+				replicas, ok := resp["Replicas"].([]interface{})
+				if resp["GroupID"] != "MyApp-1" || resp["Done"] != false || !ok || len(replicas) != 2 {
+					t.Error("unexpected response : ", resp)
+				}
+			}).Return([]byte("group"), nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Eq([]byte("group")), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1ServiceGroupGet(httptest.NewRecorder(), newRequest("MyApp-1"))
+	})
+}
+
+func TestAPIV1ServiceGroupEventsGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+
+	mockOrchestration := orchemock.NewMockOrcheExternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	events := make(chan orchestrationapi.ServiceGroupEvent, 1)
+	events <- orchestrationapi.ServiceGroupEvent{GroupID: "MyApp-1", Status: "Finished", Done: true}
+	close(events)
+
+	canceled := false
+	cancel := func() { canceled = true }
+
+	gomock.InOrder(
+		mockOrchestration.EXPECT().SubscribeServiceGroup().Return((<-chan orchestrationapi.ServiceGroupEvent)(events), cancel),
+		mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(event map[string]interface{}) {
+			if event["GroupID"] != "MyApp-1" || event["Status"] != "Finished" {
+				t.Error("unexpected event : ", event)
+			}
+		}).Return([]byte("group"), nil),
+	)
+
+	w := httptest.NewRecorder()
+	handler.APIV1ServiceGroupEventsGet(w, httptest.NewRequest("GET", "http://test.test", nil))
+
+	if w.Body.String() != "event: group\ndata: group\n\n" {
+		t.Error("unexpected body : ", w.Body.String())
+	}
+	if !canceled {
+		t.Error("subscription is not canceled")
+	}
 }
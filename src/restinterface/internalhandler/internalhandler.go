@@ -23,17 +23,26 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"common/types/servicemgrtypes"
+	"controller/servicemgr"
+	"controller/servicemgr/notification"
 	"orchestrationapi"
 	"restinterface"
 	"restinterface/cipher"
 	"restinterface/resthelper"
+
+	"github.com/gorilla/mux"
 )
 
 const logPrefix = "RestInternalInterface"
 
+// maxThroughputProbeSize limits the payload of throughput probe
+const maxThroughputProbeSize = 8 << 20
+
 // Handler struct
 type Handler struct {
 	isSetAPI bool
@@ -58,6 +67,13 @@ func init() {
 			HandlerFunc: handler.APIV1Ping,
 		},
 
+		restinterface.Route{
+			Name:        "APIV1PingThroughputGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/ping/throughput",
+			HandlerFunc: handler.APIV1PingThroughputGet,
+		},
+
 		restinterface.Route{
 			Name:        "APIV1ServicemgrServicesPost",
 			Method:      strings.ToUpper("Post"),
@@ -72,12 +88,26 @@ func init() {
 			HandlerFunc: handler.APIV1ServicemgrServicesNotificationServiceIDPost,
 		},
 
+		restinterface.Route{
+			Name:        "APIV1ServicemgrServicesLogsServiceIDGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/servicemgr/services/logs/{serviceid}",
+			HandlerFunc: handler.APIV1ServicemgrServicesLogsServiceIDGet,
+		},
+
 		restinterface.Route{
 			Name:        "APIV1ScoringmgrScoreLibnameGet",
 			Method:      strings.ToUpper("Get"),
 			Pattern:     "/api/v1/scoringmgr/score",
 			HandlerFunc: handler.APIV1ScoringmgrScoreLibnameGet,
 		},
+
+		restinterface.Route{
+			Name:        "APIV1ScoringmgrScoreDetailGet",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/api/v1/scoringmgr/score/detail",
+			HandlerFunc: handler.APIV1ScoringmgrScoreDetailGet,
+		},
 	}
 }
 
@@ -98,6 +128,18 @@ func (h *Handler) APIV1Ping(w http.ResponseWriter, r *http.Request) {
 	h.helper.ResponseJSON(w, nil, http.StatusOK)
 }
 
+// APIV1PingThroughputGet sends a payload of the requested size for throughput probe from remote orchestration
+func (h *Handler) APIV1PingThroughputGet(w http.ResponseWriter, r *http.Request) {
+	size, err := strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil || size <= 0 || size > maxThroughputProbeSize {
+		log.Printf("[%s] invalid throughput probe size", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	h.helper.ResponseJSON(w, make([]byte, size), http.StatusOK)
+}
+
 // APIV1ServicemgrServicesPost handles service execution request from remote orchestration
 func (h *Handler) APIV1ServicemgrServicesPost(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[%s] APIV1ServicemgrServicesPost", logPrefix)
@@ -124,10 +166,13 @@ func (h *Handler) APIV1ServicemgrServicesPost(w http.ResponseWriter, r *http.Req
 	appInfo["NotificationTargetURL"] = remoteAddr
 	log.Println(appInfo)
 
-	h.api.ExecuteAppOnLocal(appInfo)
-
 	respJSONMsg := make(map[string]interface{})
-	respJSONMsg["Status"] = servicemgrtypes.ConstServiceStatusStarted
+	if err := h.api.ExecuteAppOnLocal(appInfo); err != nil {
+		respJSONMsg["Status"] = servicemgrtypes.ConstServiceStatusRejected
+		respJSONMsg["Message"] = err.Error()
+	} else {
+		respJSONMsg["Status"] = servicemgrtypes.ConstServiceStatusStarted
+	}
 
 	respEncryptBytes, err := h.Key.EncryptJSONToByte(respJSONMsg)
 	if err != nil {
@@ -161,10 +206,14 @@ func (h *Handler) APIV1ServicemgrServicesNotificationServiceIDPost(w http.Respon
 		return
 	}
 
-	serviceID := statusNotification["ServiceID"].(float64)
-	status := statusNotification["Status"].(string)
+	result, err := notification.ParseExecutionResult(statusNotification)
+	if err != nil {
+		log.Printf("[%s] invalid notification : %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
 
-	err = h.api.HandleNotificationOnLocal(serviceID, status)
+	err = h.api.HandleExecutionResultOnLocal(result)
 	if err != nil {
 		h.helper.Response(w, http.StatusInternalServerError)
 		return
@@ -173,6 +222,66 @@ func (h *Handler) APIV1ServicemgrServicesNotificationServiceIDPost(w http.Respon
 	handler.helper.Response(w, http.StatusOK)
 }
 
+// APIV1ServicemgrServicesLogsServiceIDGet handles request of the output of a service executed for remote orchestration,
+// since is the sequence number of the first line to return and wait is the seconds waiting for new output
+func (h *Handler) APIV1ServicemgrServicesLogsServiceIDGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1ServicemgrServicesLogsServiceIDGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	serviceID, err := strconv.ParseUint(mux.Vars(r)["serviceid"], 10, 64)
+	if err != nil {
+		log.Printf("[%s] invalid service id", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var since uint64
+	if value := query.Get("since"); len(value) != 0 {
+		if since, err = strconv.ParseUint(value, 10, 64); err != nil {
+			log.Printf("[%s] invalid since : %s", logPrefix, value)
+			h.helper.Response(w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	var wait time.Duration
+	if value := query.Get("wait"); len(value) != 0 {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			log.Printf("[%s] invalid wait : %s", logPrefix, value)
+			h.helper.Response(w, http.StatusBadRequest)
+			return
+		}
+		wait = time.Duration(seconds * float64(time.Second))
+	}
+
+	remoteAddr, _, _ := net.SplitHostPort(r.RemoteAddr)
+	chunk, err := h.api.ReadServiceLogOnLocal(remoteAddr, serviceID, since, wait)
+	if err != nil {
+		log.Printf("[%s] %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusNotFound)
+		return
+	}
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(chunk.ToMap())
+	if err != nil {
+		log.Printf("[%s] can not encryption", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
 // APIV1ScoringmgrScoreLibnameGet handles scoring request from remote orchestration
 func (h *Handler) APIV1ScoringmgrScoreLibnameGet(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[%s] APIV1ScoringmgrScoreLibnameGet", logPrefix)
@@ -205,6 +314,57 @@ func (h *Handler) APIV1ScoringmgrScoreLibnameGet(w http.ResponseWriter, r *http.
 
 	respJSONMsg := make(map[string]interface{})
 	respJSONMsg["ScoreValue"] = scoreValue
+	respJSONMsg["Capacity"] = convertCapacity(h.api.GetCapacity())
+
+	respEncryptBytes, err := h.Key.EncryptJSONToByte(respJSONMsg)
+	if err != nil {
+		log.Printf("[%s] can not encryption %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
+}
+
+// APIV1ScoringmgrScoreDetailGet handles scoring request with sub-scores from remote orchestration
+func (h *Handler) APIV1ScoringmgrScoreDetailGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] APIV1ScoringmgrScoreDetailGet", logPrefix)
+	if h.isSetAPI == false {
+		log.Printf("[%s] does not set api", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	} else if h.IsSetKey == false {
+		log.Printf("[%s] does not set key", logPrefix)
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	encryptBytes, _ := ioutil.ReadAll(r.Body)
+	Info, err := h.Key.DecryptByteToJSON(encryptBytes)
+	if err != nil {
+		log.Printf("[%s] can not decryption %s", logPrefix, err.Error())
+		h.helper.Response(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	devID, ok := Info["devID"].(string)
+	if !ok {
+		log.Printf("[%s] invalid devID", logPrefix)
+		h.helper.Response(w, http.StatusBadRequest)
+		return
+	}
+
+	respJSONMsg := make(map[string]interface{})
+	scoreValue, components, err := h.api.GetScoreWithComponents(devID)
+	if err != nil {
+		log.Printf("[%s] GetScoreWithComponents fail : %s", logPrefix, err.Error())
+		respJSONMsg["ScoreValue"] = float64(0)
+		respJSONMsg["ScoreError"] = err.Error()
+	} else {
+		respJSONMsg["ScoreValue"] = scoreValue
+		respJSONMsg["ScoreComponents"] = components
+	}
+	respJSONMsg["Capacity"] = convertCapacity(h.api.GetCapacity())
 
 	respEncryptBytes, err := h.Key.EncryptJSONToByte(respJSONMsg)
 	if err != nil {
@@ -216,6 +376,18 @@ func (h *Handler) APIV1ScoringmgrScoreLibnameGet(w http.ResponseWriter, r *http.
 	h.helper.ResponseJSON(w, respEncryptBytes, http.StatusOK)
 }
 
+func convertCapacity(capacity servicemgr.Capacity) map[string]interface{} {
+	info := make(map[string]interface{})
+	info["MaxServices"] = capacity.MaxServices
+	info["MaxInstances"] = capacity.MaxInstances
+	info["MaxCPUUsage"] = capacity.MaxCPUUsage
+	info["MinMemoryAvailable"] = capacity.MinMemoryAvailable
+	info["RunningServices"] = capacity.RunningServices
+	info["RunningInstances"] = capacity.RunningInstances
+	info["Saturated"] = capacity.Saturated
+	return info
+}
+
 func (h *Handler) setHelper(helper resthelper.RestHelper) {
 	h.helper = helper
 }
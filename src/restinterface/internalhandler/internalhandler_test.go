@@ -22,12 +22,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"common/types/servicemgrtypes"
+	"controller/servicemgr"
+	servicenotification "controller/servicemgr/notification"
+	"controller/servicemgr/servicelog"
 	orchemock "orchestrationapi/mocks"
 	ciphermock "restinterface/cipher/mocks"
 	helpermock "restinterface/resthelper/mocks"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 )
 
 func TestGetHandler(t *testing.T) {
@@ -67,6 +73,37 @@ func TestSetOrchestrationAPI(t *testing.T) {
 	})
 }
 
+func TestAPIV1PingThroughputGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+	handler.setHelper(mockHelper)
+
+	w := httptest.NewRecorder()
+
+	t.Run("Success", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://test.test/api/v1/ping/throughput?size=1024", nil)
+		mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)).Do(
+			func(w http.ResponseWriter, payload []byte, status int) {
+				if len(payload) != 1024 {
+					t.Error("unexpected payload size : ", len(payload))
+				}
+			})
+
+		handler.APIV1PingThroughputGet(w, r)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		for _, size := range []string{"", "-1", "abc", "1073741824"} {
+			r := httptest.NewRequest("GET", "http://test.test/api/v1/ping/throughput?size="+size, nil)
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest))
+
+			handler.APIV1PingThroughputGet(w, r)
+		}
+	})
+}
+
 func TestAPIV1ServicemgrServicesPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -137,6 +174,24 @@ func TestAPIV1ServicemgrServicesPost(t *testing.T) {
 			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
 		)
 
+		handler.APIV1ServicemgrServicesPost(w, r)
+	})
+	t.Run("Rejected", func(t *testing.T) {
+		handler.SetCipher(mockCipher)
+		handler.SetOrchestrationAPI(mockOrchestration)
+		handler.setHelper(mockHelper)
+
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(make(map[string]interface{}), nil),
+			mockOrchestration.EXPECT().ExecuteAppOnLocal(gomock.Any()).Return(errors.New("max services are running")),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Do(func(msg map[string]interface{}) {
+				if msg["Status"] != servicemgrtypes.ConstServiceStatusRejected {
+					t.Error("unexpected status : ", msg["Status"])
+				}
+			}).Return(nil, nil),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
 		handler.APIV1ServicemgrServicesPost(w, r)
 	})
 }
@@ -159,6 +214,17 @@ func TestAPIV1ServicemgrServicesNotificationServiceIDPost(t *testing.T) {
 	notification := make(map[string]interface{})
 	notification["ServiceID"] = serviceID
 	notification["Status"] = status
+	notification["ExitCode"] = float64(137)
+	notification["Signal"] = "killed"
+	notification["Duration"] = 1.5
+
+	result := servicenotification.ExecutionResult{
+		ServiceID: uint64(serviceID),
+		Status:    status,
+		ExitCode:  137,
+		Signal:    "killed",
+		Duration:  1500 * time.Millisecond,
+	}
 
 	r := httptest.NewRequest("POST", "http://test.test", nil)
 	w := httptest.NewRecorder()
@@ -190,13 +256,24 @@ func TestAPIV1ServicemgrServicesNotificationServiceIDPost(t *testing.T) {
 
 			handler.APIV1ServicemgrServicesNotificationServiceIDPost(w, r)
 		})
+		t.Run("InvalidNotification", func(t *testing.T) {
+			handler.SetCipher(mockCipher)
+			handler.SetOrchestrationAPI(mockOrchestration)
+			handler.setHelper(mockHelper)
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(map[string]interface{}{"Status": status}, nil),
+				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest)),
+			)
+
+			handler.APIV1ServicemgrServicesNotificationServiceIDPost(w, r)
+		})
 		t.Run("HandleNotificationFail", func(t *testing.T) {
 			handler.SetCipher(mockCipher)
 			handler.SetOrchestrationAPI(mockOrchestration)
 			handler.setHelper(mockHelper)
 			gomock.InOrder(
 				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(notification, nil),
-				mockOrchestration.EXPECT().HandleNotificationOnLocal(gomock.Eq(serviceID), gomock.Eq(status)).Return(errors.New("")),
+				mockOrchestration.EXPECT().HandleExecutionResultOnLocal(gomock.Eq(result)).Return(errors.New("")),
 				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusInternalServerError)),
 			)
 
@@ -210,7 +287,7 @@ func TestAPIV1ServicemgrServicesNotificationServiceIDPost(t *testing.T) {
 		handler.setHelper(mockHelper)
 		gomock.InOrder(
 			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(notification, nil),
-			mockOrchestration.EXPECT().HandleNotificationOnLocal(gomock.Eq(serviceID), gomock.Eq(status)).Return(nil),
+			mockOrchestration.EXPECT().HandleExecutionResultOnLocal(gomock.Eq(result)).Return(nil),
 			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusOK)),
 		)
 
@@ -218,6 +295,53 @@ func TestAPIV1ServicemgrServicesNotificationServiceIDPost(t *testing.T) {
 	})
 }
 
+func TestAPIV1ServicemgrServicesLogsServiceIDGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	mockOrchestration := orchemock.NewMockOrcheInternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	newRequest := func(target string, serviceID string) *http.Request {
+		r := httptest.NewRequest("GET", target, nil)
+		r.RemoteAddr = "127.0.0.2:56001"
+		return mux.SetURLVars(r, map[string]string{"serviceid": serviceID})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		t.Run("InvalidWait", func(t *testing.T) {
+			mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest))
+			handler.APIV1ServicemgrServicesLogsServiceIDGet(httptest.NewRecorder(), newRequest("http://test.test?wait=-1", "1"))
+		})
+		t.Run("NotFound", func(t *testing.T) {
+			gomock.InOrder(
+				mockOrchestration.EXPECT().ReadServiceLogOnLocal("127.0.0.2", uint64(1), uint64(0), time.Duration(0)).Return(servicelog.Chunk{}, errors.New("not found")),
+				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusNotFound)),
+			)
+			handler.APIV1ServicemgrServicesLogsServiceIDGet(httptest.NewRecorder(), newRequest("http://test.test", "1"))
+		})
+	})
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			mockOrchestration.EXPECT().ReadServiceLogOnLocal("127.0.0.2", uint64(1), uint64(3), 1500*time.Millisecond).Return(servicelog.Chunk{ServiceID: 1, Next: 3, Finished: true}, nil),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).DoAndReturn(func(msg map[string]interface{}) ([]byte, error) {
+				if msg["Finished"] != true || msg["Next"] != float64(3) {
+					t.Error("unexpected logs : ", msg)
+				}
+				return nil, nil
+			}),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+		handler.APIV1ServicemgrServicesLogsServiceIDGet(httptest.NewRecorder(), newRequest("http://test.test?since=3&wait=1.5", "1"))
+	})
+}
+
 func TestAPIV1ScoringmgrScoreLibnameGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -289,6 +413,7 @@ func TestAPIV1ScoringmgrScoreLibnameGet(t *testing.T) {
 			gomock.InOrder(
 				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appNameInfo, nil),
 				mockOrchestration.EXPECT().GetScore(gomock.Any()).Return(serviceID, nil),
+				mockOrchestration.EXPECT().GetCapacity(),
 				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, errors.New("")),
 				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusServiceUnavailable)),
 			)
@@ -304,6 +429,7 @@ func TestAPIV1ScoringmgrScoreLibnameGet(t *testing.T) {
 		gomock.InOrder(
 			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(appNameInfo, nil),
 			mockOrchestration.EXPECT().GetScore(gomock.Any()).Return(serviceID, nil),
+			mockOrchestration.EXPECT().GetCapacity(),
 			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).Return(nil, nil),
 			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
 		)
@@ -311,3 +437,78 @@ func TestAPIV1ScoringmgrScoreLibnameGet(t *testing.T) {
 		handler.APIV1ScoringmgrScoreLibnameGet(w, r)
 	})
 }
+
+func TestAPIV1ScoringmgrScoreDetailGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GetHandler()
+	if handler == nil {
+		t.Error("unexpected return value")
+	}
+
+	mockOrchestration := orchemock.NewMockOrcheInternalAPI(ctrl)
+	mockCipher := ciphermock.NewMockIEdgeCipherer(ctrl)
+	mockHelper := helpermock.NewMockRestHelper(ctrl)
+
+	devInfo := make(map[string]interface{})
+	devInfo["devID"] = "deviceID"
+	components := map[string]float64{"network": 1.0, "cpu": 2.0, "rendering": 3.0}
+
+	r := httptest.NewRequest("GET", "http://test.test", nil)
+	w := httptest.NewRecorder()
+
+	handler.SetCipher(mockCipher)
+	handler.SetOrchestrationAPI(mockOrchestration)
+	handler.setHelper(mockHelper)
+
+	t.Run("Error", func(t *testing.T) {
+		t.Run("InvalidDevID", func(t *testing.T) {
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(map[string]interface{}{}, nil),
+				mockHelper.EXPECT().Response(gomock.Any(), gomock.Eq(http.StatusBadRequest)),
+			)
+
+			handler.APIV1ScoringmgrScoreDetailGet(w, r)
+		})
+		t.Run("GetScoreFail", func(t *testing.T) {
+			gomock.InOrder(
+				mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(devInfo, nil),
+				mockOrchestration.EXPECT().GetScoreWithComponents(gomock.Eq("deviceID")).Return(float64(0), nil, errors.New("no resource")),
+				mockOrchestration.EXPECT().GetCapacity(),
+				mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).DoAndReturn(func(msg map[string]interface{}) ([]byte, error) {
+					if msg["ScoreError"] != "no resource" {
+						t.Error("unexpected score error : ", msg["ScoreError"])
+					}
+					return nil, nil
+				}),
+				mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+			)
+
+			handler.APIV1ScoringmgrScoreDetailGet(w, r)
+		})
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			mockCipher.EXPECT().DecryptByteToJSON(gomock.Any()).Return(devInfo, nil),
+			mockOrchestration.EXPECT().GetScoreWithComponents(gomock.Eq("deviceID")).Return(float64(6), components, nil),
+			mockOrchestration.EXPECT().GetCapacity().Return(servicemgr.Capacity{Saturated: true}),
+			mockCipher.EXPECT().EncryptJSONToByte(gomock.Any()).DoAndReturn(func(msg map[string]interface{}) ([]byte, error) {
+				if msg["ScoreValue"] != float64(6) {
+					t.Error("unexpected score value : ", msg["ScoreValue"])
+				}
+				if _, ok := msg["ScoreComponents"]; !ok {
+					t.Error("score components are missing")
+				}
+				if capacity, ok := msg["Capacity"].(map[string]interface{}); !ok || capacity["Saturated"] != true {
+					t.Error("unexpected capacity : ", msg["Capacity"])
+				}
+				return nil, nil
+			}),
+			mockHelper.EXPECT().ResponseJSON(gomock.Any(), gomock.Any(), gomock.Eq(http.StatusOK)),
+		)
+
+		handler.APIV1ScoringmgrScoreDetailGet(w, r)
+	})
+}
@@ -19,25 +19,43 @@
 package route
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"common/metrics"
 	"restinterface"
 )
 
 const (
 	// ConstWellknownPort is the common port for REST API
 	ConstWellknownPort = 56001
+
+	// ConstMetricsPattern is the path serving metrics in the Prometheus text format
+	ConstMetricsPattern = "/metrics"
+)
+
+var (
+	requestsTotal = metrics.NewCounter(
+		"edge_orchestration_http_requests_total",
+		"Number of REST requests by route, method and status code",
+		"route", "method", "code")
+	requestDuration = metrics.NewHistogram(
+		"edge_orchestration_http_request_duration_seconds",
+		"Latency of REST requests by route",
+		nil, "route")
 )
 
 // RestRouter struct {
 type RestRouter struct {
 	routes restinterface.Routes
 	router *mux.Router
+	server *http.Server
 }
 
 // NewRestRouter constructs RestRouter instance
@@ -45,6 +63,11 @@ func NewRestRouter() *RestRouter {
 
 	edgeRouter := new(RestRouter)
 	edgeRouter.router = mux.NewRouter().StrictSlash(true)
+	edgeRouter.router.
+		Methods(http.MethodGet).
+		Path(ConstMetricsPattern).
+		Name("Metrics").
+		Handler(metrics.Handler())
 
 	return edgeRouter
 }
@@ -55,13 +78,41 @@ func (r *RestRouter) Add(s restinterface.IRestRoutes) {
 }
 
 // Start wraps ListenAndServe function
-func (r RestRouter) Start() {
-	go r.listenAndServe()
+func (r *RestRouter) Start() {
+	// requests are canceled on shutdown so that streaming handlers return
+	ctx, cancel := context.WithCancel(context.Background())
+	r.server = &http.Server{
+		Addr:        ":" + strconv.Itoa(ConstWellknownPort),
+		Handler:     r.router,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	r.server.RegisterOnShutdown(cancel)
+	go r.listenAndServe(r.server)
 }
 
-func (r RestRouter) listenAndServe() {
+// Stop shutdowns REST server after in-flight requests are done or ctx is expired,
+// streaming requests are canceled at the beginning of shutdown
+func (r *RestRouter) Stop(ctx context.Context) (err error) {
+	if r.server == nil {
+		return
+	}
+
+	err = r.server.Shutdown(ctx)
+	if err != nil {
+		log.Printf("Shutdown : %s", err.Error())
+		r.server.Close()
+	}
+	r.server = nil
+
+	return
+}
+
+func (r RestRouter) listenAndServe(server *http.Server) {
 	log.Printf("ListenAndServe")
-	http.ListenAndServe(":"+strconv.Itoa(ConstWellknownPort), r.router)
+	err := server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		log.Printf("ListenAndServe : %s", err.Error())
+	}
 }
 
 func (r RestRouter) add(routes restinterface.Routes) {
@@ -82,7 +133,12 @@ func logger(inner http.Handler, name string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		inner.ServeHTTP(w, r)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		inner.ServeHTTP(recorder, r)
+
+		elapsed := time.Since(start)
+		requestsTotal.Inc(name, r.Method, strconv.Itoa(recorder.status))
+		requestDuration.Observe(elapsed.Seconds(), name)
 
 		log.Printf(
 			"From [%s] %s %s %s %s",
@@ -90,11 +146,34 @@ func logger(inner http.Handler, name string) http.Handler {
 			r.Method,
 			r.RequestURI,
 			name,
-			time.Since(start),
+			elapsed,
 		)
 	})
 }
 
+// statusRecorder keeps the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends buffered data of streaming handlers to the client
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the original ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func readClientIP(r *http.Request) string {
 	IPAddress := r.Header.Get("X-Real-Ip")
 	if IPAddress == "" {
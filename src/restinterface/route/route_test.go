@@ -18,7 +18,13 @@
 package route
 
 import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -61,3 +67,154 @@ func TestAdd(t *testing.T) {
 }
 
 // TODO check to call expected function as restapi using httpserver mock
+
+func TestMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoute := routemock.NewMockIRestRoutes(ctrl)
+	mockRoute.EXPECT().GetRoutes().Return(restinterface.Routes{
+		restinterface.Route{Name: "metricsroute", Method: "GET", Pattern: "/api/v1/metricsroute",
+			HandlerFunc: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) }},
+	})
+
+	router := NewRestRouter()
+	router.Add(mockRoute)
+
+	router.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/metricsroute", nil))
+
+	w := httptest.NewRecorder()
+	router.router.ServeHTTP(w, httptest.NewRequest("GET", ConstMetricsPattern, nil))
+	if w.Code != http.StatusOK {
+		t.Fatal("unexpected status code", w.Code)
+	}
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		`edge_orchestration_http_requests_total{route="metricsroute",method="GET",code="404"} 1`,
+		`edge_orchestration_http_request_duration_seconds_count{route="metricsroute"} 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in\n%s", expected, body)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	mockRoute := routemock.NewMockIRestRoutes(ctrl)
+	mockRoute.EXPECT().GetRoutes().Return(restinterface.Routes{
+		restinterface.Route{Name: "streamroute", Method: "GET", Pattern: "/api/v1/streamroute",
+			HandlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				flusher, ok := w.(http.Flusher)
+				if !ok {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("data: first\n\n"))
+				flusher.Flush()
+
+				select {
+				case <-done:
+				case <-r.Context().Done():
+				}
+			}},
+	})
+
+	router := NewRestRouter()
+	router.Add(mockRoute)
+
+	server := httptest.NewServer(router.router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/streamroute")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal("unexpected status code", resp.StatusCode)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "data: first\n" {
+		t.Error("unexpected stream : ", line, err)
+	}
+}
+
+func TestStop(t *testing.T) {
+	router := NewRestRouter()
+
+	t.Run("NotStarted", func(t *testing.T) {
+		if err := router.Stop(context.Background()); err != nil {
+			t.Error(err.Error())
+		}
+	})
+	t.Run("Success", func(t *testing.T) {
+		router.Start()
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if err := router.Stop(ctx); err != nil {
+			t.Error(err.Error())
+		}
+		if router.server != nil {
+			t.Error("server is not released")
+		}
+	})
+	t.Run("Streaming", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		canceled := make(chan struct{})
+		started := make(chan struct{})
+
+		mockRoute := routemock.NewMockIRestRoutes(ctrl)
+		mockRoute.EXPECT().GetRoutes().Return(restinterface.Routes{
+			restinterface.Route{Name: "stopstream", Method: "GET", Pattern: "/api/v1/stopstream",
+				HandlerFunc: func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.(http.Flusher).Flush()
+					close(started)
+
+					<-r.Context().Done()
+					close(canceled)
+				}},
+		})
+
+		router := NewRestRouter()
+		router.Add(mockRoute)
+		router.Start()
+		time.Sleep(100 * time.Millisecond)
+
+		resp, err := http.Get("http://localhost:" + strconv.Itoa(ConstWellknownPort) + "/api/v1/stopstream")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		defer resp.Body.Close()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if err := router.Stop(ctx); err != nil {
+			t.Error(err.Error())
+		}
+
+		select {
+		case <-canceled:
+		default:
+			t.Error("streaming request is not canceled")
+		}
+	})
+}